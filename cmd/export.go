@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/archive"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFilter string
+	exportOutput string
+	exportFormat string
+)
+
+// exportCmd writes frames matching a filter to a portable archive.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Exports frames matching a filter to a portable archive",
+	Long: `Exports frames matching a filter to a portable archive.
+
+The archive contains the full frame (data and metadata) for every frame matching
+the JSON frame filter, and can be loaded into another forky instance with
+"forky import". For example, to export every frame between two slots:
+
+  forky export --config config.yaml --filter '{"min_slot": 7000000, "max_slot": 7000100}' --output frames.tar.gz`,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := &service.FrameFilter{}
+
+		if err := json.Unmarshal([]byte(exportFilter), filter); err != nil {
+			log.WithError(err).Fatal("failed to parse filter")
+		}
+
+		if err := filter.Validate(); err != nil {
+			log.WithError(err).Fatal("invalid filter")
+		}
+
+		format := archive.Format(exportFormat)
+		if format == "" {
+			derived, err := archive.FormatFromFilename(exportOutput)
+			if err != nil {
+				log.WithError(err).Fatal("failed to derive archive format, set --format")
+			}
+
+			format = derived
+		}
+
+		cfg := initCommon()
+
+		svc, err := service.NewForkChoice("forky", log, cfg.Forky, service.DefaultOptions().SetMetricsEnabled(false))
+		if err != nil {
+			log.WithError(err).Fatal("failed to create service")
+		}
+
+		file, err := os.Create(exportOutput)
+		if err != nil {
+			log.WithError(err).Fatal("failed to create output file")
+		}
+		defer file.Close()
+
+		writer, err := archive.NewWriter(file, format)
+		if err != nil {
+			log.WithError(err).Fatal("failed to create archive writer")
+		}
+
+		start := time.Now()
+		total := service.ExportResult{}
+		page := service.DefaultPagination()
+
+		for {
+			result, err := svc.ExportFrames(context.Background(), filter, *page, writer)
+			if err != nil {
+				log.WithError(err).Fatal("failed to export frames")
+			}
+
+			total.Listed += result.Listed
+			total.Exported += result.Exported
+			total.Missing += result.Missing
+
+			log.WithField("exported", total.Exported).Info("Export progress")
+
+			if result.Listed < page.Limit {
+				break
+			}
+
+			page.Offset += page.Limit
+		}
+
+		if err := writer.Close(); err != nil {
+			log.WithError(err).Fatal("failed to finalize archive")
+		}
+
+		log.WithFields(logrus.Fields{
+			"output":   exportOutput,
+			"format":   format,
+			"exported": total.Exported,
+			"missing":  total.Missing,
+			"elapsed":  time.Since(start).Round(time.Second).String(),
+		}).Info("Export complete")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportFilter, "filter", "", "JSON frame filter selecting the frames to export")
+	exportCmd.Flags().StringVar(&exportOutput, "output", "", "file to write the archive to")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "archive format (tar.gz or ndjson.gz). Derived from --output if not set")
+
+	if err := exportCmd.MarkFlagRequired("filter"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := exportCmd.MarkFlagRequired("output"); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fsckRepair         bool
+	fsckOrphanedFrames string
+)
+
+// fsckCmd checks the store and indexer for inconsistencies.
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Checks the store and indexer for inconsistencies and optionally repairs them",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := initCommon()
+
+		action := service.OrphanedFrameAction(fsckOrphanedFrames)
+		if !service.IsValidOrphanedFrameAction(action) {
+			log.WithField("orphaned_frames", fsckOrphanedFrames).Fatal("invalid orphaned frames action")
+		}
+
+		svc, err := service.NewForkChoice("forky", log, cfg.Forky, service.DefaultOptions().SetMetricsEnabled(false))
+		if err != nil {
+			log.WithError(err).Fatal("failed to create service")
+		}
+
+		ctx := context.Background()
+
+		report, err := svc.CheckConsistency(ctx)
+		if err != nil {
+			log.WithError(err).Fatal("failed to check consistency")
+		}
+
+		for _, id := range report.OrphanedFrames {
+			fmt.Printf("orphaned frame (in store, not indexed): %s\n", id)
+		}
+
+		for _, id := range report.OrphanedMetadata {
+			fmt.Printf("orphaned metadata (indexed, not in store): %s\n", id)
+		}
+
+		logCtx := log.WithFields(logrus.Fields{
+			"stored_frames":     report.StoredFrames,
+			"indexed_frames":    report.IndexedFrames,
+			"orphaned_frames":   len(report.OrphanedFrames),
+			"orphaned_metadata": len(report.OrphanedMetadata),
+		})
+
+		if report.Consistent() {
+			logCtx.Info("Store and indexer are consistent")
+
+			return
+		}
+
+		if !fsckRepair {
+			logCtx.Fatal("Store and indexer are inconsistent. Run with --repair to fix")
+		}
+
+		result, err := svc.RepairConsistency(ctx, report, service.RepairOptions{
+			OrphanedFrames:         action,
+			DeleteOrphanedMetadata: true,
+		})
+		if err != nil {
+			log.WithError(err).Fatal("failed to repair inconsistencies")
+		}
+
+		logCtx = logCtx.WithFields(logrus.Fields{
+			"reindexed_frames": result.ReindexedFrames,
+			"deleted_frames":   result.DeletedFrames,
+			"deleted_metadata": result.DeletedMetadata,
+			"failed":           result.Failed,
+		})
+
+		if result.Failed > 0 {
+			logCtx.Fatal("Failed to repair some inconsistencies")
+		}
+
+		logCtx.Info("Repaired store and indexer inconsistencies")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fsckCmd)
+
+	fsckCmd.Flags().BoolVar(&fsckRepair, "repair", false, "repair any inconsistencies that are found")
+	fsckCmd.Flags().StringVar(&fsckOrphanedFrames, "orphaned-frames", string(service.OrphanedFrameActionReindex), "action to take on frames that are stored but not indexed (reindex, delete, none)")
+}
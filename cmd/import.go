@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/archive"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importInput  string
+	importFormat string
+)
+
+// importCmd loads frames from a portable archive into the configured store and indexer.
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports frames from a portable archive",
+	Long: `Imports frames from an archive created by "forky export" (or the export API)
+into the configured store and indexer. Frames that are already indexed are
+skipped, so an import can safely be run more than once.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := archive.Format(importFormat)
+		if format == "" {
+			derived, err := archive.FormatFromFilename(importInput)
+			if err != nil {
+				log.WithError(err).Fatal("failed to derive archive format, set --format")
+			}
+
+			format = derived
+		}
+
+		cfg := initCommon()
+
+		svc, err := service.NewForkChoice("forky", log, cfg.Forky, service.DefaultOptions().SetMetricsEnabled(false))
+		if err != nil {
+			log.WithError(err).Fatal("failed to create service")
+		}
+
+		file, err := os.Open(importInput)
+		if err != nil {
+			log.WithError(err).Fatal("failed to open input file")
+		}
+		defer file.Close()
+
+		reader, err := archive.NewReader(file, format)
+		if err != nil {
+			log.WithError(err).Fatal("failed to read archive")
+		}
+		defer reader.Close()
+
+		start := time.Now()
+
+		result, err := svc.ImportFrames(context.Background(), reader)
+		if err != nil {
+			log.WithError(err).Fatal("failed to import frames")
+		}
+
+		logCtx := log.WithFields(logrus.Fields{
+			"input":    importInput,
+			"imported": result.Imported,
+			"skipped":  result.Skipped,
+			"failed":   result.Failed,
+			"elapsed":  time.Since(start).Round(time.Second).String(),
+		})
+
+		if result.Failed > 0 {
+			logCtx.Fatal("Import completed with failures. Run again to retry")
+		}
+
+		logCtx.Info("Import complete")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importInput, "input", "", "archive file to import")
+	importCmd.Flags().StringVar(&importFormat, "format", "", "archive format (tar.gz or ndjson.gz). Derived from --input if not set")
+
+	if err := importCmd.MarkFlagRequired("input"); err != nil {
+		log.Fatal(err)
+	}
+}
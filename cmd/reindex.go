@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reindexWorkers   int
+	reindexBatchSize int
+)
+
+// reindexCmd rebuilds the indexer from the frames held in the store.
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuilds the indexer from the frames held in the store",
+	Long: `Rebuilds the indexer from the frames held in the store.
+
+Frames that are already indexed are skipped, so an interrupted reindex can be
+resumed by running it again. Pointing the indexer config at a new database
+(e.g. postgres instead of sqlite) migrates the index to it.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := initCommon()
+
+		svc, err := service.NewForkChoice("forky", log, cfg.Forky, service.DefaultOptions().SetMetricsEnabled(false))
+		if err != nil {
+			log.WithError(err).Fatal("failed to create service")
+		}
+
+		start := time.Now()
+
+		opts := service.DefaultReindexOptions()
+		opts.Workers = reindexWorkers
+		opts.BatchSize = reindexBatchSize
+		opts.Progress = func(progress service.ReindexProgress) {
+			log.WithFields(logrus.Fields{
+				"indexed":   progress.Indexed,
+				"failed":    progress.Failed,
+				"remaining": progress.Remaining(),
+				"elapsed":   time.Since(start).Round(time.Second).String(),
+			}).Info("Reindex progress")
+		}
+
+		progress, err := svc.Reindex(context.Background(), opts)
+		if err != nil {
+			log.WithError(err).Fatal("failed to reindex")
+		}
+
+		logCtx := log.WithFields(logrus.Fields{
+			"total":   progress.Total,
+			"skipped": progress.Skipped,
+			"indexed": progress.Indexed,
+			"failed":  progress.Failed,
+			"elapsed": time.Since(start).Round(time.Second).String(),
+		})
+
+		if progress.Failed > 0 {
+			logCtx.Fatal("Reindex completed with failures. Run again to retry")
+		}
+
+		logCtx.Info("Reindex complete")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reindexCmd)
+
+	defaults := service.DefaultReindexOptions()
+
+	reindexCmd.Flags().IntVar(&reindexWorkers, "workers", defaults.Workers, "number of frames to fetch from the store concurrently")
+	reindexCmd.Flags().IntVar(&reindexBatchSize, "batch-size", defaults.BatchSize, "number of frames to insert into the indexer at once")
+}
@@ -34,6 +34,9 @@ func Execute() {
 	if err != nil {
 		os.Exit(1)
 	}
+
+	// Commands that run to completion (e.g. fsck) should exit instead of waiting for a signal.
+	os.Exit(0)
 }
 
 func init() {
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/ethpandaops/forky/pkg/forky/store"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	storeMigrateFrom         string
+	storeMigrateTo           string
+	storeMigrateFilter       string
+	storeMigrateWorkers      int
+	storeMigrateVerify       bool
+	storeMigrateSkipExisting bool
+)
+
+// storeCmd groups commands that operate directly on stores.
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Commands that operate directly on stores",
+}
+
+// storeMigrateCmd copies frames between two stores.
+var storeMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copies frames from one store to another",
+	Long: `Copies frames from one store to another.
+
+--from and --to are YAML files containing a store config, in the same shape as
+the "forky.store" section of the main config:
+
+  type: s3
+  config:
+    bucket_name: forkchoice
+    format: json.gz
+
+Frames are re-encoded by the destination store, so setting a different "format"
+on the destination transcodes frames as they're copied. --filter limits the
+migration to the frames matching a metadata filter (the same filter the API
+accepts) and uses the indexer from --config to find them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.SetFormatter(&logrus.TextFormatter{})
+
+		ctx := context.Background()
+
+		from := newStoreFromConfigFile(storeMigrateFrom, "forky_migrate_from")
+		to := newStoreFromConfigFile(storeMigrateTo, "forky_migrate_to")
+
+		var ids []string
+
+		var err error
+
+		if storeMigrateFilter != "" {
+			ids, err = listFrameIDsMatchingFilter(ctx, storeMigrateFilter)
+		} else {
+			ids, err = from.ListFrames(ctx)
+		}
+
+		if err != nil {
+			log.WithError(err).Fatal("failed to list frames to migrate")
+		}
+
+		start := time.Now()
+		lastLogged := time.Now()
+
+		opts := store.DefaultMigrateOptions()
+		opts.Workers = storeMigrateWorkers
+		opts.Verify = storeMigrateVerify
+		opts.SkipExisting = storeMigrateSkipExisting
+		opts.Progress = func(result store.MigrateResult) {
+			if time.Since(lastLogged) < 5*time.Second {
+				return
+			}
+
+			lastLogged = time.Now()
+
+			log.WithFields(logrus.Fields{
+				"copied":    result.Copied,
+				"skipped":   result.Skipped,
+				"failed":    result.Failed,
+				"remaining": result.Total - result.Copied - result.Skipped - result.Failed,
+			}).Info("Migration progress")
+		}
+
+		log.WithField("frames", len(ids)).Info("Migrating frames")
+
+		result, err := store.Migrate(ctx, log, from, to, ids, opts)
+		if err != nil {
+			log.WithError(err).Fatal("failed to migrate frames")
+		}
+
+		logCtx := log.WithFields(logrus.Fields{
+			"total":    result.Total,
+			"copied":   result.Copied,
+			"verified": result.Verified,
+			"skipped":  result.Skipped,
+			"failed":   result.Failed,
+			"elapsed":  time.Since(start).Round(time.Second).String(),
+		})
+
+		if result.Failed > 0 {
+			logCtx.Fatal("Migration completed with failures. Run again to retry")
+		}
+
+		logCtx.Info("Migration complete")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(storeCmd)
+	storeCmd.AddCommand(storeMigrateCmd)
+
+	defaults := store.DefaultMigrateOptions()
+
+	storeMigrateCmd.Flags().StringVar(&storeMigrateFrom, "from", "", "store config file to copy frames from")
+	storeMigrateCmd.Flags().StringVar(&storeMigrateTo, "to", "", "store config file to copy frames to")
+	storeMigrateCmd.Flags().StringVar(&storeMigrateFilter, "filter", "", "only migrate frames matching this JSON frame filter (requires --config for the indexer)")
+	storeMigrateCmd.Flags().IntVar(&storeMigrateWorkers, "workers", defaults.Workers, "number of frames to copy concurrently")
+	storeMigrateCmd.Flags().BoolVar(&storeMigrateVerify, "verify", defaults.Verify, "read every frame back from the destination and compare it to the source")
+	storeMigrateCmd.Flags().BoolVar(&storeMigrateSkipExisting, "skip-existing", defaults.SkipExisting, "skip frames that already exist in the destination")
+
+	if err := storeMigrateCmd.MarkFlagRequired("from"); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := storeMigrateCmd.MarkFlagRequired("to"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newStoreFromConfigFile(file, namespace string) store.Store {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		log.WithError(err).WithField("file", file).Fatal("failed to read store config")
+	}
+
+	config := store.Config{}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		log.WithError(err).WithField("file", file).Fatal("failed to parse store config")
+	}
+
+	if err := config.Validate(); err != nil {
+		log.WithError(err).WithField("file", file).Fatal("invalid store config")
+	}
+
+	st, err := store.NewStore(namespace, log, config.Type, config.Config, store.DefaultOptions().SetMetricsEnabled(false))
+	if err != nil {
+		log.WithError(err).WithField("file", file).Fatal("failed to create store")
+	}
+
+	return st
+}
+
+// listFrameIDsMatchingFilter returns the IDs of every indexed frame matching the JSON filter.
+func listFrameIDsMatchingFilter(ctx context.Context, rawFilter string) ([]string, error) {
+	filter := &service.FrameFilter{}
+
+	if err := json.Unmarshal([]byte(rawFilter), filter); err != nil {
+		return nil, err
+	}
+
+	cfg := initCommon()
+
+	svc, err := service.NewForkChoice("forky", log, cfg.Forky, service.DefaultOptions().SetMetricsEnabled(false))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+
+	page := service.DefaultPagination()
+
+	for {
+		metadata, _, err := svc.ListMetadata(ctx, filter, *page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range metadata {
+			ids = append(ids, m.ID)
+		}
+
+		if len(metadata) < page.Limit {
+			break
+		}
+
+		page.Offset += page.Limit
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/ethpandaops/forky/pkg/yaml"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+var errUnauthorized = errors.New("unauthorized")
+
+func (h *HTTP) authorizeAdmin(r *http.Request) error {
+	if !h.config.Admin.Auth.Authorize(r.Header.Get("Authorization")) {
+		return errUnauthorized
+	}
+
+	return nil
+}
+
+func (h *HTTP) handleV1AdminUpsertSource(ctx context.Context, r *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	if err := h.authorizeAdmin(r); err != nil {
+		return fhttp.NewUnauthorizedResponse(nil), err
+	}
+
+	var req fhttp.V1AdminUpsertSourceRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	config := req.Config
+	if len(config) == 0 {
+		config = json.RawMessage("{}")
+	}
+
+	created, err := h.svc.UpsertSource(ctx, req.Name, req.Type, req.Network, yaml.NewRawMessage(config))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSource) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1AdminUpsertSourceResponse{
+		Created: created,
+	}
+
+	sources, err := h.svc.ListSources(ctx)
+	if err != nil {
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	for _, status := range sources {
+		if status.Name == req.Name {
+			rsp.Source = status
+		}
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("no-store")
+
+	return response, nil
+}
+
+func (h *HTTP) handleV1AdminRemoveSource(ctx context.Context, r *http.Request, p httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	if err := h.authorizeAdmin(r); err != nil {
+		return fhttp.NewUnauthorizedResponse(nil), err
+	}
+
+	if err := h.svc.RemoveSource(ctx, p.ByName("name")); err != nil {
+		if errors.Is(err, service.ErrSourceNotFound) {
+			return fhttp.NewNotFoundResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(struct{}{})
+		},
+	})
+
+	response.SetCacheControl("no-store")
+
+	return response, nil
+}
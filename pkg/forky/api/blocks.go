@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// parseRoot parses a 0x-prefixed hex block root.
+func parseRoot(s string) (phase0.Root, error) {
+	var root phase0.Root
+
+	if !strings.HasPrefix(s, "0x") {
+		return root, errors.New("root must be 0x-prefixed")
+	}
+
+	decoded, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(decoded) != len(root) {
+		return root, errors.New("invalid root")
+	}
+
+	copy(root[:], decoded)
+
+	return root, nil
+}
+
+func (h *HTTP) handleV1GetBlockWeights(ctx context.Context, r *http.Request, p httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	root, err := parseRoot(p.ByName("root"))
+	if err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	var req fhttp.V1GetBlockWeightsRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	series, err := h.svc.GetBlockWeights(ctx, root, req.Filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1GetBlockWeightsResponse{
+		Series: series,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("private, max-age=0, no-cache, no-store, must-revalidate")
+
+	return response, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func (h *HTTP) handleV1GetChainReport(ctx context.Context, r *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	var req fhttp.V1GetChainReportRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	report, err := h.svc.GetChainReport(ctx, req.Filter, req.FromSlot, req.ToSlot)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSlotRange) ||
+			errors.Is(err, service.ErrTooManyFrames) ||
+			errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1GetChainReportResponse{
+		Report: report,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("private, max-age=0, no-cache, no-store, must-revalidate")
+
+	return response, nil
+}
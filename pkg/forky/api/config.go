@@ -2,11 +2,14 @@ package api
 
 import (
 	"github.com/ethpandaops/forky/pkg/forky/human"
+	"github.com/ethpandaops/forky/pkg/forky/source"
 	"github.com/pkg/errors"
 )
 
 type Config struct {
 	EdgeCacheConfig EdgeCacheConfig `yaml:"edge_cache" default:"{}"`
+	Export          ExportConfig    `yaml:"export" default:"{}"`
+	Admin           AdminConfig     `yaml:"admin" default:"{}"`
 }
 
 type EdgeCacheConfig struct {
@@ -15,11 +18,34 @@ type EdgeCacheConfig struct {
 	FrameTTL human.Duration `yaml:"frame_ttl" default:"1440m"`
 }
 
+type ExportConfig struct {
+	Enabled bool `yaml:"enabled" default:"true"`
+
+	// MaxFrames is the maximum number of frames a single export request can return.
+	MaxFrames int `yaml:"max_frames" default:"1000"`
+}
+
+type AdminConfig struct {
+	// Enabled exposes the admin API, which can add and remove sources at runtime.
+	Enabled bool `yaml:"enabled" default:"false"`
+
+	// Auth is the authentication required for the admin API. Required if enabled.
+	Auth source.AuthConfig `yaml:"auth"`
+}
+
 func (c *Config) Validate() error {
 	if err := c.EdgeCacheConfig.Validate(); err != nil {
 		return errors.Wrap(err, "invalid edge cache config")
 	}
 
+	if err := c.Export.Validate(); err != nil {
+		return errors.Wrap(err, "invalid export config")
+	}
+
+	if err := c.Admin.Validate(); err != nil {
+		return errors.Wrap(err, "invalid admin config")
+	}
+
 	return nil
 }
 
@@ -30,3 +56,23 @@ func (c *EdgeCacheConfig) Validate() error {
 
 	return nil
 }
+
+func (c *ExportConfig) Validate() error {
+	if c.Enabled && c.MaxFrames < 1 {
+		return errors.New("max_frames must be greater than 0")
+	}
+
+	return nil
+}
+
+func (c *AdminConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if !c.Auth.Enabled() {
+		return errors.New("auth is required when the admin api is enabled")
+	}
+
+	return c.Auth.Validate()
+}
@@ -3,25 +3,105 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/service"
 
 	"github.com/julienschmidt/httprouter"
 )
 
-func (h *HTTP) handleV1GetEthereumSpec(ctx context.Context, _ *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+// ethereumErrorResponse maps errors about the requested network to a response.
+func ethereumErrorResponse(err error) *fhttp.Response {
+	switch {
+	case errors.Is(err, service.ErrNetworkNotFound):
+		return fhttp.NewNotFoundResponse(nil)
+	case errors.Is(err, service.ErrNetworkNotReady):
+		return fhttp.NewServiceUnavailableResponse(nil)
+	default:
+		return fhttp.NewInternalServerErrorResponse(nil)
+	}
+}
+
+func (h *HTTP) handleV1GetEthereumSpec(ctx context.Context, r *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
 	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
 		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
 	}
 
+	// The network defaults to the default network.
+	network := r.URL.Query().Get("network")
+
+	ready, err := h.svc.EthereumReady(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	if !ready {
+		return fhttp.NewServiceUnavailableResponse(nil), service.ErrNetworkNotReady
+	}
+
+	// The active fork is reported for the current slot unless a slot is requested.
+	var slot phase0.Slot
+
+	if requested := r.URL.Query().Get("slot"); requested != "" {
+		parsed, err := strconv.ParseUint(requested, 10, 64)
+		if err != nil {
+			return fhttp.NewBadRequestResponse(nil), errors.New("invalid slot")
+		}
+
+		slot = phase0.Slot(parsed)
+	} else {
+		now, _, err := h.svc.GetEthereumNow(ctx, network)
+		if err != nil {
+			return ethereumErrorResponse(err), err
+		}
+
+		slot = now
+	}
+
+	fork, err := h.svc.GetEthereumForkAtSlot(ctx, network, slot)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	name, err := h.svc.GetEthereumNetworkName(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	secondsPerSlot, err := h.svc.GetEthereumSpecSecondsPerSlot(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	slotsPerEpoch, err := h.svc.GetEthereumSpecSlotsPerEpoch(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	genesisTime, err := h.svc.GetEthereumSpecGenesisTime(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	forks, err := h.svc.GetEthereumForks(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
 	rsp := fhttp.V1GetEthereumSpecResponse{
-		NetworkName: h.svc.GetEthereumNetworkName(ctx),
+		NetworkName: name,
 		Spec: fhttp.EthereumSpec{
-			SecondsPerSlot: h.svc.GetEthereumSpecSecondsPerSlot(ctx),
-			SlotsPerEpoch:  h.svc.GetEthereumSpecSlotsPerEpoch(ctx),
-			GenesisTime:    h.svc.GetEthereumSpecGenesisTime(ctx),
+			SecondsPerSlot: secondsPerSlot,
+			SlotsPerEpoch:  slotsPerEpoch,
+			GenesisTime:    genesisTime,
+			Forks:          forks,
 		},
+		ActiveFork: fork,
+		Slot:       slot,
 	}
 
 	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
@@ -30,24 +110,33 @@ func (h *HTTP) handleV1GetEthereumSpec(ctx context.Context, _ *http.Request, _ h
 		},
 	})
 
-	response.SetCacheControl("public, max-age=60, s-maxage=60")
+	// The active fork changes with the current slot, so don't cache for long.
+	response.SetCacheControl("public, max-age=12, s-maxage=12")
 
 	return response, nil
 }
 
-func (h *HTTP) handleV1GetEthereumNow(ctx context.Context, _ *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+func (h *HTTP) handleV1GetEthereumNow(ctx context.Context, r *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
 	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
 		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
 	}
 
-	slot, epoch, err := h.svc.GetEthereumNow(ctx)
+	network := r.URL.Query().Get("network")
+
+	slot, epoch, err := h.svc.GetEthereumNow(ctx, network)
+	if err != nil {
+		return ethereumErrorResponse(err), err
+	}
+
+	name, err := h.svc.GetEthereumNetworkName(ctx, network)
 	if err != nil {
-		return fhttp.NewInternalServerErrorResponse(nil), err
+		return ethereumErrorResponse(err), err
 	}
 
 	rsp := fhttp.V1GetEthereumNowResponse{
-		Slot:  uint64(slot),
-		Epoch: uint64(epoch),
+		NetworkName: name,
+		Slot:        uint64(slot),
+		Epoch:       uint64(epoch),
 	}
 
 	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
@@ -60,3 +149,23 @@ func (h *HTTP) handleV1GetEthereumNow(ctx context.Context, _ *http.Request, _ ht
 
 	return response, nil
 }
+
+func (h *HTTP) handleV1ListEthereumNetworks(ctx context.Context, _ *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	rsp := fhttp.V1ListEthereumNetworksResponse{
+		Networks: h.svc.ListEthereumNetworks(ctx),
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("public, max-age=60, s-maxage=60")
+
+	return response, nil
+}
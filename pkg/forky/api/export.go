@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/archive"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// exportedFramesHeader is the trailer holding the number of frames exported.
+const exportedFramesHeader = "X-Forky-Exported-Frames"
+
+func (h *HTTP) handleV1Export(ctx context.Context, r *http.Request, _ httprouter.Params, _ fhttp.ContentType) (*fhttp.Response, error) {
+	var req fhttp.V1ExportRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	filter := req.Filter
+	if filter == nil {
+		filter = &service.FrameFilter{}
+	}
+
+	if err := filter.Validate(); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	page := req.Pagination
+	if page == nil {
+		page = &service.PaginationCursor{
+			Limit: h.config.Export.MaxFrames,
+		}
+	}
+
+	if page.Limit < 1 || page.Limit > h.config.Export.MaxFrames {
+		return fhttp.NewBadRequestResponse(nil), fmt.Errorf("pagination limit must be between 1 and %d", h.config.Export.MaxFrames)
+	}
+
+	format := req.Format
+	if format == "" {
+		format = archive.FormatTarGz
+	}
+
+	if !archive.IsValidFormat(format) {
+		return fhttp.NewBadRequestResponse(nil), fmt.Errorf("invalid format: %s", format)
+	}
+
+	if err := filter.ValidateVersions(); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	response := fhttp.NewSuccessResponse(nil)
+
+	response.Headers["Content-Type"] = format.ContentType()
+	response.Headers["Content-Disposition"] = fmt.Sprintf("attachment; filename=\"forky-export-%d%s\"", time.Now().Unix(), format.Extension())
+	// The number of exported frames is only known once the archive has been
+	// streamed, so it's sent as a trailer.
+	response.Headers["Trailer"] = exportedFramesHeader
+	response.SetCacheControl("private, max-age=0, no-cache, no-store, must-revalidate")
+
+	response.AddExtraData("_stream", fhttp.StreamFunc(func(w http.ResponseWriter) error {
+		writer, err := archive.NewWriter(w, format)
+		if err != nil {
+			return err
+		}
+
+		result, err := h.svc.ExportFrames(ctx, filter, *page, writer)
+		if err != nil {
+			return err
+		}
+
+		if err := writer.Close(); err != nil {
+			return err
+		}
+
+		w.Header().Set(exportedFramesHeader, fmt.Sprintf("%d", result.Exported))
+
+		return nil
+	}))
+
+	return response, nil
+}
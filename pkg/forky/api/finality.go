@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func (h *HTTP) handleV1GetFinality(ctx context.Context, r *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	// All networks are included unless one is requested.
+	network := r.URL.Query().Get("network")
+
+	nodes, events, err := h.svc.GetFinalityStatus(ctx, network)
+	if err != nil {
+		if errors.Is(err, service.ErrFinalityMonitorDisabled) || errors.Is(err, service.ErrNetworkNotFound) {
+			return fhttp.NewNotFoundResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1GetFinalityResponse{
+		Nodes:  nodes,
+		Events: events,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("public, max-age=12, s-maxage=12")
+
+	return response, nil
+}
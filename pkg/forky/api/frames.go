@@ -53,3 +53,78 @@ func (h *HTTP) handleV1GetFrame(ctx context.Context, _ *http.Request, p httprout
 
 	return response, nil
 }
+
+func (h *HTTP) handleV1GetFrameAnalysis(ctx context.Context, _ *http.Request, p httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	id := p.ByName("id")
+	if id == "" {
+		return fhttp.NewBadRequestResponse(nil), errors.New("id is required")
+	}
+
+	analysis, err := h.svc.GetFrameAnalysis(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrFrameNotFound) {
+			return fhttp.NewNotFoundResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1GetFrameAnalysisResponse{
+		Analysis: analysis,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	// Frames never change, so neither does their analysis once the network's
+	// spec is known. Until then it's only cached briefly.
+	switch {
+	case analysis.SlotsPerEpoch == 0:
+		response.SetCacheControl("public, max-age=12, s-maxage=12")
+	case h.config.EdgeCacheConfig.Enabled:
+		response.SetCacheControl(fmt.Sprintf("public, max-age=%[1]v, s-maxage=%[1]v", h.config.EdgeCacheConfig.FrameTTL.Seconds()))
+	}
+
+	return response, nil
+}
+
+func (h *HTTP) handleV1GetRawFrame(ctx context.Context, _ *http.Request, p httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	id := p.ByName("id")
+	if id == "" {
+		return fhttp.NewBadRequestResponse(nil), errors.New("id is required")
+	}
+
+	data, err := h.svc.GetRawFrame(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrRawFrameNotFound) {
+			return fhttp.NewNotFoundResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	response := fhttp.NewSuccessResponse(nil)
+
+	// The payload is returned exactly as the node sent it, without the usual
+	// data envelope.
+	response.Headers["Content-Type"] = fhttp.ContentTypeJSON.String()
+
+	if h.config.EdgeCacheConfig.Enabled {
+		response.SetCacheControl(fmt.Sprintf("public, max-age=%[1]v, s-maxage=%[1]v", h.config.EdgeCacheConfig.FrameTTL.Seconds()))
+	}
+
+	response.AddExtraData("_raw_content", data)
+
+	return response, nil
+}
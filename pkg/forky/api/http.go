@@ -42,8 +42,26 @@ func NewHTTP(log logrus.FieldLogger, svc *service.ForkChoice, config *Config, op
 func (h *HTTP) BindToRouter(_ context.Context, router *httprouter.Router) error {
 	router.GET("/api/v1/ethereum/now", h.wrappedHandler(h.handleV1GetEthereumNow))
 	router.GET("/api/v1/ethereum/spec", h.wrappedHandler(h.handleV1GetEthereumSpec))
+	router.GET("/api/v1/ethereum/networks", h.wrappedHandler(h.handleV1ListEthereumNetworks))
+
+	router.GET("/api/v1/sources", h.wrappedHandler(h.handleV1ListSources))
 
 	router.GET("/api/v1/frames/:id", h.wrappedHandler(h.handleV1GetFrame))
+	router.GET("/api/v1/frames/:id/raw", h.wrappedHandler(h.handleV1GetRawFrame))
+	router.GET("/api/v1/frames/:id/analysis", h.wrappedHandler(h.handleV1GetFrameAnalysis))
+
+	if h.config.Export.Enabled {
+		router.POST("/api/v1/export", h.wrappedHandler(h.handleV1Export))
+	}
+
+	router.POST("/api/v1/chain/report", h.wrappedHandler(h.handleV1GetChainReport))
+	router.POST("/api/v1/blocks/:root/weights", h.wrappedHandler(h.handleV1GetBlockWeights))
+
+	router.GET("/api/v1/finality", h.wrappedHandler(h.handleV1GetFinality))
+
+	router.POST("/api/v1/reorgs", h.wrappedHandler(h.handleV1ReorgsList))
+	router.GET("/api/v1/reorgs/:id", h.wrappedHandler(h.handleV1GetReorg))
+	router.GET("/api/v1/reorgs/:id/frames", h.wrappedHandler(h.handleV1GetReorgFrames))
 
 	router.POST("/api/v1/metadata", h.wrappedHandler(h.handleV1MetadataList))
 	router.POST("/api/v1/metadata/nodes", h.wrappedHandler(h.handleV1MetadataListNodes))
@@ -51,6 +69,11 @@ func (h *HTTP) BindToRouter(_ context.Context, router *httprouter.Router) error
 	router.POST("/api/v1/metadata/epochs", h.wrappedHandler(h.handleV1MetadataListEpochs))
 	router.POST("/api/v1/metadata/labels", h.wrappedHandler(h.handleV1MetadataListLabels))
 
+	if h.config.Admin.Enabled {
+		router.POST("/api/v1/admin/sources", h.wrappedHandler(h.handleV1AdminUpsertSource))
+		router.DELETE("/api/v1/admin/sources/:name", h.wrappedHandler(h.handleV1AdminRemoveSource))
+	}
+
 	return nil
 }
 
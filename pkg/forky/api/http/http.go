@@ -1,10 +1,15 @@
 package http
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/archive"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
+	"github.com/ethpandaops/forky/pkg/forky/forkchoice"
 	"github.com/ethpandaops/forky/pkg/forky/service"
+	"github.com/ethpandaops/forky/pkg/forky/source"
 	"github.com/ethpandaops/forky/pkg/forky/types"
 )
 
@@ -14,21 +19,104 @@ type V1GetEthereumSpecRequest struct {
 }
 
 type EthereumSpec struct {
-	SecondsPerSlot uint64    `json:"seconds_per_slot"`
-	SlotsPerEpoch  uint64    `json:"slots_per_epoch"`
-	GenesisTime    time.Time `json:"genesis_time"`
+	SecondsPerSlot uint64                   `json:"seconds_per_slot"`
+	SlotsPerEpoch  uint64                   `json:"slots_per_epoch"`
+	GenesisTime    time.Time                `json:"genesis_time"`
+	Forks          []ethereum.ScheduledFork `json:"forks"`
 }
 type V1GetEthereumSpecResponse struct {
 	NetworkName string       `json:"network_name"`
 	Spec        EthereumSpec `json:"spec"`
+	// ActiveFork is the fork active at Slot, which is the current slot unless
+	// one was requested.
+	ActiveFork ethereum.Fork `json:"active_fork"`
+	Slot       phase0.Slot   `json:"slot"`
 }
 
 type V1GetEthereumNowRequest struct {
 }
 
 type V1GetEthereumNowResponse struct {
-	Slot  uint64 `json:"slot"`
-	Epoch uint64 `json:"epoch"`
+	NetworkName string `json:"network_name"`
+	Slot        uint64 `json:"slot"`
+	Epoch       uint64 `json:"epoch"`
+}
+
+type V1ListEthereumNetworksResponse struct {
+	Networks []string `json:"networks"`
+}
+
+// // Sources
+type V1ListSourcesResponse struct {
+	Sources []source.Status `json:"sources"`
+}
+
+// // Admin
+type V1AdminUpsertSourceRequest struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Network defaults to the default network.
+	Network string          `json:"network"`
+	Config  json.RawMessage `json:"config"`
+}
+
+type V1AdminUpsertSourceResponse struct {
+	Created bool          `json:"created"`
+	Source  source.Status `json:"source"`
+}
+
+// // Export
+type V1ExportRequest struct {
+	Filter     *service.FrameFilter      `json:"filter"`
+	Pagination *service.PaginationCursor `json:"pagination"`
+	Format     archive.Format            `json:"format"`
+}
+
+// // Chain
+type V1GetChainReportRequest struct {
+	Filter   *service.FrameFilter `json:"filter"`
+	FromSlot phase0.Slot          `json:"from_slot"`
+	ToSlot   phase0.Slot          `json:"to_slot"`
+}
+
+type V1GetChainReportResponse struct {
+	Report *forkchoice.ChainReport `json:"report"`
+}
+
+// // Blocks
+type V1GetBlockWeightsRequest struct {
+	Filter *service.FrameFilter `json:"filter"`
+}
+
+type V1GetBlockWeightsResponse struct {
+	Series *forkchoice.WeightSeries `json:"series"`
+}
+
+// // Finality
+type V1GetFinalityResponse struct {
+	Nodes  []*service.NodeFinality    `json:"nodes"`
+	Events []service.FinalityLagEvent `json:"events"`
+}
+
+// // Reorgs
+type V1ReorgsListRequest struct {
+	Filter     *service.ReorgFilter      `json:"filter"`
+	Pagination *service.PaginationCursor `json:"pagination"`
+}
+
+type V1ReorgsListResponse struct {
+	Reorgs     []*types.Reorg              `json:"reorgs"`
+	Pagination *service.PaginationResponse `json:"pagination"`
+}
+
+type V1GetReorgResponse struct {
+	Reorg *types.Reorg `json:"reorg"`
+}
+
+type V1GetReorgFramesResponse struct {
+	Reorg  *types.Reorg `json:"reorg"`
+	Before *types.Frame `json:"before"`
+	After  *types.Frame `json:"after"`
 }
 
 // // Metadata
@@ -89,3 +177,7 @@ type V1GetFrameRequest struct {
 type V1GetFrameResponse struct {
 	Frame *types.Frame `json:"frame"`
 }
+
+type V1GetFrameAnalysisResponse struct {
+	Analysis *forkchoice.Analysis `json:"analysis"`
+}
@@ -15,6 +15,11 @@ type Response struct {
 	Headers    map[string]string `json:"headers"`
 	ExtraData  map[string]interface{}
 }
+
+// StreamFunc writes a response body as it's produced. It's stored as _stream
+// in ExtraData, and called once the status and headers have been sent.
+type StreamFunc func(w http.ResponseWriter) error
+
 type jsonResponse struct {
 	Data json.RawMessage `json:"data"`
 }
@@ -66,6 +71,15 @@ func NewBadRequestResponse(resolvers ContentTypeResolvers) *Response {
 	}
 }
 
+func NewUnauthorizedResponse(resolvers ContentTypeResolvers) *Response {
+	return &Response{
+		resolvers:  resolvers,
+		StatusCode: http.StatusUnauthorized,
+		Headers:    make(map[string]string),
+		ExtraData:  make(map[string]interface{}),
+	}
+}
+
 func NewNotFoundResponse(resolvers ContentTypeResolvers) *Response {
 	return &Response{
 		resolvers:  resolvers,
@@ -84,6 +98,15 @@ func NewUnsupportedMediaTypeResponse(resolvers ContentTypeResolvers) *Response {
 	}
 }
 
+func NewServiceUnavailableResponse(resolvers ContentTypeResolvers) *Response {
+	return &Response{
+		resolvers:  resolvers,
+		StatusCode: http.StatusServiceUnavailable,
+		Headers:    make(map[string]string),
+		ExtraData:  make(map[string]interface{}),
+	}
+}
+
 func (r *Response) AddExtraData(key string, value interface{}) {
 	r.ExtraData[key] = value
 }
@@ -73,6 +73,21 @@ func WrappedHandler(log logrus.FieldLogger, metrics *Metrics, handler func(ctx c
 			}
 		}
 
+		// Special case: Check for streamed content (stored as _stream in ExtraData)
+		if stream, ok := response.ExtraData["_stream"].(StreamFunc); ok {
+			w.WriteHeader(response.StatusCode)
+
+			if err := stream(w); err != nil {
+				log.WithError(err).Error("Failed to stream content")
+
+				// The status has already been sent, so abort the response to
+				// stop the client mistaking a partial body for a complete one.
+				panic(http.ErrAbortHandler)
+			}
+
+			return
+		}
+
 		// Standard flow - marshal response based on content type
 		data, err := response.MarshalAs(contentType)
 		if err != nil {
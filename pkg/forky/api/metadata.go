@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
@@ -35,6 +36,10 @@ func (h *HTTP) handleV1MetadataList(ctx context.Context, r *http.Request, p http
 
 	frames, pg, err := h.svc.ListMetadata(ctx, filter, *page)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
 		return fhttp.NewInternalServerErrorResponse(nil), err
 	}
 
@@ -76,6 +81,10 @@ func (h *HTTP) handleV1MetadataListNodes(ctx context.Context, r *http.Request, p
 
 	nodes, pg, err := h.svc.ListNodes(ctx, filter, *page)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
 		return fhttp.NewInternalServerErrorResponse(nil), err
 	}
 
@@ -119,6 +128,10 @@ func (h *HTTP) handleV1MetadataListSlots(ctx context.Context, r *http.Request, p
 
 	slots, pg, err := h.svc.ListSlots(ctx, filter, *page)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
 		return fhttp.NewInternalServerErrorResponse(nil), err
 	}
 
@@ -162,6 +175,10 @@ func (h *HTTP) handleV1MetadataListEpochs(ctx context.Context, r *http.Request,
 
 	epochs, pg, err := h.svc.ListEpochs(ctx, filter, *page)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
 		return fhttp.NewInternalServerErrorResponse(nil), err
 	}
 
@@ -205,6 +222,10 @@ func (h *HTTP) handleV1MetadataListLabels(ctx context.Context, r *http.Request,
 
 	labels, pg, err := h.svc.ListLabels(ctx, filter, *page)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidFilter) {
+			return fhttp.NewBadRequestResponse(nil), err
+		}
+
 		return fhttp.NewInternalServerErrorResponse(nil), err
 	}
 
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+	"github.com/ethpandaops/forky/pkg/forky/service"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func (h *HTTP) handleV1ReorgsList(ctx context.Context, r *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	var req fhttp.V1ReorgsListRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return fhttp.NewBadRequestResponse(nil), err
+	}
+
+	filter := req.Filter
+	if filter == nil {
+		filter = &service.ReorgFilter{}
+	}
+
+	page := req.Pagination
+	if page == nil {
+		page = service.DefaultPagination()
+	}
+
+	reorgs, pg, err := h.svc.ListReorgs(ctx, filter, *page)
+	if err != nil {
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1ReorgsListResponse{
+		Reorgs:     reorgs,
+		Pagination: pg,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("private, max-age=0, no-cache, no-store, must-revalidate")
+
+	return response, nil
+}
+
+func (h *HTTP) handleV1GetReorg(ctx context.Context, _ *http.Request, p httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	id := p.ByName("id")
+	if id == "" {
+		return fhttp.NewBadRequestResponse(nil), errors.New("id is required")
+	}
+
+	reorg, err := h.svc.GetReorg(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrReorgNotFound) {
+			return fhttp.NewNotFoundResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1GetReorgResponse{
+		Reorg: reorg,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	return response, nil
+}
+
+func (h *HTTP) handleV1GetReorgFrames(ctx context.Context, _ *http.Request, p httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	id := p.ByName("id")
+	if id == "" {
+		return fhttp.NewBadRequestResponse(nil), errors.New("id is required")
+	}
+
+	reorg, frames, err := h.svc.GetReorgFrames(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrReorgNotFound) {
+			return fhttp.NewNotFoundResponse(nil), err
+		}
+
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1GetReorgFramesResponse{
+		Reorg:  reorg,
+		Before: frames.Before,
+		After:  frames.After,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	return response, nil
+}
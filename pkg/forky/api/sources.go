@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	fhttp "github.com/ethpandaops/forky/pkg/forky/api/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+func (h *HTTP) handleV1ListSources(ctx context.Context, _ *http.Request, _ httprouter.Params, contentType fhttp.ContentType) (*fhttp.Response, error) {
+	if err := fhttp.ValidateContentType(contentType, []fhttp.ContentType{fhttp.ContentTypeJSON}); err != nil {
+		return fhttp.NewUnsupportedMediaTypeResponse(nil), err
+	}
+
+	sources, err := h.svc.ListSources(ctx)
+	if err != nil {
+		return fhttp.NewInternalServerErrorResponse(nil), err
+	}
+
+	rsp := fhttp.V1ListSourcesResponse{
+		Sources: sources,
+	}
+
+	response := fhttp.NewSuccessResponse(fhttp.ContentTypeResolvers{
+		fhttp.ContentTypeJSON: func() ([]byte, error) {
+			return json.Marshal(rsp)
+		},
+	})
+
+	response.SetCacheControl("public, max-age=5, s-maxage=5")
+
+	return response, nil
+}
@@ -0,0 +1,90 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+// Format is the format of a portable frame archive.
+type Format string
+
+const (
+	// FormatTarGz is a gzipped tarball with one JSON file per frame.
+	FormatTarGz Format = "tar.gz"
+	// FormatNDJSONGz is a gzipped stream of newline delimited JSON frames.
+	FormatNDJSONGz Format = "ndjson.gz"
+)
+
+func IsValidFormat(f Format) bool {
+	switch f {
+	case FormatTarGz, FormatNDJSONGz:
+		return true
+	default:
+		return false
+	}
+}
+
+// FormatFromFilename derives the archive format from a filename's extension.
+func FormatFromFilename(name string) (Format, error) {
+	switch {
+	case strings.HasSuffix(name, "."+string(FormatTarGz)), strings.HasSuffix(name, ".tgz"):
+		return FormatTarGz, nil
+	case strings.HasSuffix(name, "."+string(FormatNDJSONGz)):
+		return FormatNDJSONGz, nil
+	default:
+		return "", fmt.Errorf("unable to derive archive format from filename: %s", name)
+	}
+}
+
+// Extension returns the file extension (including the leading dot) for the format.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// ContentType returns the HTTP content type for the format.
+func (f Format) ContentType() string {
+	return "application/gzip"
+}
+
+// Writer writes frames to an archive.
+type Writer interface {
+	// WriteFrame adds a frame to the archive.
+	WriteFrame(frame *types.Frame) error
+	// Close finalizes the archive. It does not close the underlying writer.
+	Close() error
+}
+
+// Reader reads frames from an archive.
+type Reader interface {
+	// Next returns the next frame in the archive, or io.EOF when there are no more frames.
+	Next() (*types.Frame, error)
+	// Close releases any resources held by the reader. It does not close the underlying reader.
+	Close() error
+}
+
+// NewWriter creates a Writer for the given format.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatTarGz:
+		return NewTarWriter(w), nil
+	case FormatNDJSONGz:
+		return NewNDJSONWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown archive format: %s", format)
+	}
+}
+
+// NewReader creates a Reader for the given format.
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case FormatTarGz:
+		return NewTarReader(r)
+	case FormatNDJSONGz:
+		return NewNDJSONReader(r)
+	default:
+		return nil, fmt.Errorf("unknown archive format: %s", format)
+	}
+}
@@ -0,0 +1,74 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatTarGz, FormatNDJSONGz} {
+		t.Run(string(format), func(t *testing.T) {
+			frames := []*types.Frame{}
+
+			for i := 0; i < 5; i++ {
+				frames = append(frames, types.GenerateFakeFrame())
+			}
+
+			buf := &bytes.Buffer{}
+
+			w, err := NewWriter(buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, frame := range frames {
+				if err := w.WriteFrame(frame); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := NewReader(buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer r.Close()
+
+			for _, expected := range frames {
+				frame, err := r.Next()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, expected.Metadata.ID, frame.Metadata.ID)
+				assert.Equal(t, expected.Metadata.Node, frame.Metadata.Node)
+				assert.Equal(t, len(expected.Data.ForkChoiceNodes), len(frame.Data.ForkChoiceNodes))
+			}
+
+			_, err = r.Next()
+			assert.True(t, errors.Is(err, io.EOF))
+		})
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	format, err := FormatFromFilename("frames.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatTarGz, format)
+
+	format, err = FormatFromFilename("/tmp/frames.ndjson.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatNDJSONGz, format)
+
+	_, err = FormatFromFilename("frames.zip")
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+type ndjsonWriter struct {
+	gz *gzip.Writer
+}
+
+// NewNDJSONWriter creates a Writer that produces a gzipped newline delimited JSON stream.
+func NewNDJSONWriter(w io.Writer) Writer {
+	return &ndjsonWriter{
+		gz: gzip.NewWriter(w),
+	}
+}
+
+func (n *ndjsonWriter) WriteFrame(frame *types.Frame) error {
+	data, err := frame.AsJSON()
+	if err != nil {
+		return err
+	}
+
+	if _, err := n.gz.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.gz.Close()
+}
+
+type ndjsonReader struct {
+	gz      *gzip.Reader
+	scanner *bufio.Scanner
+}
+
+// maxNDJSONLineSize is the largest single frame we'll read from an NDJSON stream.
+const maxNDJSONLineSize = 256 * 1024 * 1024
+
+// NewNDJSONReader creates a Reader for a gzipped newline delimited JSON stream.
+func NewNDJSONReader(r io.Reader) (Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 1024*1024), maxNDJSONLineSize)
+
+	return &ndjsonReader{
+		gz:      gz,
+		scanner: scanner,
+	}, nil
+}
+
+func (n *ndjsonReader) Next() (*types.Frame, error) {
+	for n.scanner.Scan() {
+		line := n.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		frame := &types.Frame{}
+
+		if err := frame.FromJSON(line); err != nil {
+			return nil, err
+		}
+
+		return frame, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+func (n *ndjsonReader) Close() error {
+	return n.gz.Close()
+}
+
+var _ = Writer(&ndjsonWriter{})
+var _ = Reader(&ndjsonReader{})
@@ -0,0 +1,147 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/ethpandaops/forky/pkg/version"
+)
+
+const (
+	tarFramesDir    = "frames"
+	tarManifestName = "manifest.json"
+)
+
+// Manifest describes the contents of a tar archive.
+type Manifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	Version   string    `json:"version"`
+	Frames    int       `json:"frames"`
+}
+
+type tarWriter struct {
+	gz     *gzip.Writer
+	tw     *tar.Writer
+	frames int
+}
+
+// NewTarWriter creates a Writer that produces a gzipped tarball.
+func NewTarWriter(w io.Writer) Writer {
+	gz := gzip.NewWriter(w)
+
+	return &tarWriter{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+	}
+}
+
+func (t *tarWriter) WriteFrame(frame *types.Frame) error {
+	data, err := frame.AsJSON()
+	if err != nil {
+		return err
+	}
+
+	if err := t.writeFile(path.Join(tarFramesDir, frame.Metadata.ID+".json"), data, frame.Metadata.FetchedAt); err != nil {
+		return err
+	}
+
+	t.frames++
+
+	return nil
+}
+
+func (t *tarWriter) Close() error {
+	manifest, err := json.Marshal(Manifest{
+		CreatedAt: time.Now(),
+		Version:   version.Short(),
+		Frames:    t.frames,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := t.writeFile(tarManifestName, manifest, time.Now()); err != nil {
+		return err
+	}
+
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+
+	return t.gz.Close()
+}
+
+func (t *tarWriter) writeFile(name string, data []byte, modTime time.Time) error {
+	if err := t.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+
+	_, err := t.tw.Write(data)
+
+	return err
+}
+
+type tarReader struct {
+	gz *gzip.Reader
+	tr *tar.Reader
+}
+
+// NewTarReader creates a Reader for a gzipped tarball.
+func NewTarReader(r io.Reader) (Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tarReader{
+		gz: gz,
+		tr: tar.NewReader(gz),
+	}, nil
+}
+
+func (t *tarReader) Next() (*types.Frame, error) {
+	for {
+		header, err := t.tr.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg ||
+			path.Dir(header.Name) != tarFramesDir ||
+			!strings.HasSuffix(header.Name, ".json") {
+			continue
+		}
+
+		data, err := io.ReadAll(t.tr)
+		if err != nil {
+			return nil, err
+		}
+
+		frame := &types.Frame{}
+
+		if err := frame.FromJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", header.Name, err)
+		}
+
+		return frame, nil
+	}
+}
+
+func (t *tarReader) Close() error {
+	return t.gz.Close()
+}
+
+var _ = Writer(&tarWriter{})
+var _ = Reader(&tarReader{})
@@ -15,7 +15,7 @@ type Config struct {
 	Metrics    MetricsConfig `yaml:"metrics"`
 	PProfAddr  *string       `yaml:"pprof_addr" default:":6060"`
 
-	Forky *service.Config `yaml:"forky"`
+	Forky *service.Config `yaml:"forky" default:"{}"`
 
 	HTTP *api.Config `yaml:"http" default:"{}"`
 }
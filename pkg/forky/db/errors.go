@@ -0,0 +1,7 @@
+package db
+
+import "errors"
+
+var (
+	ErrFrameAlreadyIndexed = errors.New("frame already indexed")
+)
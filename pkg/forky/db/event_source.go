@@ -10,6 +10,7 @@ const (
 	BeaconNodeEventSource
 	XatuPollingEventSource
 	XatuReorgEventEventSource
+	FileReplayEventSource
 )
 
 func NewEventSourceFromString(s string) EventSource {
@@ -26,6 +27,8 @@ func NewEventSourceFromType(s types.EventSource) EventSource {
 		return XatuPollingEventSource
 	case types.XatuReorgEventEventSource:
 		return XatuReorgEventEventSource
+	case types.FileReplayEventSource:
+		return FileReplayEventSource
 	default:
 		return NilEventSource
 	}
@@ -41,11 +44,13 @@ func NewEventSource(i int) EventSource {
 		return XatuPollingEventSource
 	case 4:
 		return XatuReorgEventEventSource
+	case 5:
+		return FileReplayEventSource
 	default:
 		return NilEventSource
 	}
 }
 
 func (e EventSource) String() string {
-	return [...]string{"", "unknown", "beacon_node", "xatu_polling", "xatu_reorg_event"}[e]
+	return [...]string{"", "unknown", "beacon_node", "xatu_polling", "xatu_reorg_event", "file_replay"}[e]
 }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
 	"gorm.io/gorm"
 )
 
@@ -13,10 +14,18 @@ type FrameFilter struct {
 	Before          *time.Time
 	After           *time.Time
 	Slot            *uint64
+	MinSlot         *uint64
+	MaxSlot         *uint64
 	Epoch           *uint64
 	Labels          *[]string
 	ConsensusClient *string
 	EventSource     *int
+	Network         *string
+	// ConsensusClientVersion matches a version exactly, while the min and max
+	// versions are inclusive bounds on the release.
+	ConsensusClientVersion    *string
+	MinConsensusClientVersion *string
+	MaxConsensusClientVersion *string
 }
 
 func (f *FrameFilter) AddID(id string) {
@@ -39,6 +48,14 @@ func (f *FrameFilter) AddSlot(slot uint64) {
 	f.Slot = &slot
 }
 
+func (f *FrameFilter) AddMinSlot(slot uint64) {
+	f.MinSlot = &slot
+}
+
+func (f *FrameFilter) AddMaxSlot(slot uint64) {
+	f.MaxSlot = &slot
+}
+
 func (f *FrameFilter) AddEpoch(epoch uint64) {
 	f.Epoch = &epoch
 }
@@ -55,16 +72,38 @@ func (f *FrameFilter) AddEventSource(eventSource int) {
 	f.EventSource = &eventSource
 }
 
+func (f *FrameFilter) AddNetwork(network string) {
+	f.Network = &network
+}
+
+func (f *FrameFilter) AddConsensusClientVersion(version string) {
+	f.ConsensusClientVersion = &version
+}
+
+func (f *FrameFilter) AddMinConsensusClientVersion(version string) {
+	f.MinConsensusClientVersion = &version
+}
+
+func (f *FrameFilter) AddMaxConsensusClientVersion(version string) {
+	f.MaxConsensusClientVersion = &version
+}
+
 func (f *FrameFilter) Validate() error {
 	if f.ID == nil &&
 		f.Node == nil &&
 		f.Before == nil &&
 		f.After == nil &&
 		f.Slot == nil &&
+		f.MinSlot == nil &&
+		f.MaxSlot == nil &&
 		f.Epoch == nil &&
 		f.Labels == nil &&
 		f.ConsensusClient == nil &&
-		f.EventSource == nil {
+		f.EventSource == nil &&
+		f.Network == nil &&
+		f.ConsensusClientVersion == nil &&
+		f.MinConsensusClientVersion == nil &&
+		f.MaxConsensusClientVersion == nil {
 		return errors.New("no filter specified")
 	}
 
@@ -92,6 +131,14 @@ func (f *FrameFilter) ApplyToQuery(query *gorm.DB) (*gorm.DB, error) {
 		query = query.Where("wall_clock_slot = ?", f.Slot)
 	}
 
+	if f.MinSlot != nil {
+		query = query.Where("wall_clock_slot >= ?", f.MinSlot)
+	}
+
+	if f.MaxSlot != nil {
+		query = query.Where("wall_clock_slot <= ?", f.MaxSlot)
+	}
+
 	if f.Epoch != nil {
 		query = query.Where("wall_clock_epoch = ?", f.Epoch)
 	}
@@ -104,5 +151,31 @@ func (f *FrameFilter) ApplyToQuery(query *gorm.DB) (*gorm.DB, error) {
 		query = query.Where("consensus_client = ?", f.ConsensusClient)
 	}
 
+	if f.Network != nil {
+		query = query.Where("network = ?", f.Network)
+	}
+
+	if f.ConsensusClientVersion != nil {
+		query = query.Where("consensus_client_version = ?", f.ConsensusClientVersion)
+	}
+
+	if f.MinConsensusClientVersion != nil {
+		key, err := ethereum.VersionSortKey(*f.MinConsensusClientVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		query = query.Where("consensus_client_version_key != '' AND consensus_client_version_key >= ?", key)
+	}
+
+	if f.MaxConsensusClientVersion != nil {
+		key, err := ethereum.VersionSortKey(*f.MaxConsensusClientVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		query = query.Where("consensus_client_version_key != '' AND consensus_client_version_key <= ?", key)
+	}
+
 	return query, nil
 }
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
 	"github.com/ethpandaops/forky/pkg/forky/types"
 	"gorm.io/gorm"
 )
@@ -20,7 +21,13 @@ type FrameMetadata struct {
 	FetchedAt       time.Time            `gorm:"index"`
 	Labels          []FrameMetadataLabel `gorm:"foreignkey:FrameID;"`
 	ConsensusClient string               `gorm:"not null;default:''"`
-	EventSource     EventSource          `gorm:"not null;default:0"`
+	// ConsensusClientVersion is indexed alongside a sort key so that frames
+	// can be filtered by version range.
+	ConsensusClientVersion    string             `gorm:"index;not null;default:''"`
+	ConsensusClientVersionKey string             `gorm:"index;not null;default:''"`
+	EventSource               EventSource        `gorm:"not null;default:0"`
+	Network                   string             `gorm:"index;not null;default:''"`
+	NodeContext               *types.NodeContext `gorm:"serializer:json"`
 }
 
 type FrameMetadatas []*FrameMetadata
@@ -44,11 +51,14 @@ func (f *FrameMetadata) AsFrameMetadata() *types.FrameMetadata {
 		//nolint:gosec // ignore integer overflow conversion uint64 -> int64
 		WallClockSlot: phase0.Slot(f.WallClockSlot),
 		//nolint:gosec // ignore integer overflow conversion uint64 -> int64
-		WallClockEpoch:  phase0.Epoch(f.WallClockEpoch),
-		FetchedAt:       f.FetchedAt,
-		Labels:          l.AsStrings(),
-		ConsensusClient: f.ConsensusClient,
-		EventSource:     f.EventSource.String(),
+		WallClockEpoch:         phase0.Epoch(f.WallClockEpoch),
+		FetchedAt:              f.FetchedAt,
+		Labels:                 l.AsStrings(),
+		ConsensusClient:        f.ConsensusClient,
+		ConsensusClientVersion: f.ConsensusClientVersion,
+		EventSource:            f.EventSource.String(),
+		Network:                f.Network,
+		NodeContext:            f.NodeContext,
 	}
 }
 
@@ -64,7 +74,18 @@ func (f *FrameMetadata) FromFrameMetadata(metadata *types.FrameMetadata) *FrameM
 	f.Labels = FrameMetadataLabels{}
 
 	f.ConsensusClient = metadata.ConsensusClient
+	f.ConsensusClientVersion = metadata.ConsensusClientVersion
+	f.ConsensusClientVersionKey = ""
+
+	if metadata.ConsensusClientVersion != "" {
+		// Versions that can't be parsed are left out of version ranges.
+		if key, err := ethereum.VersionSortKey(metadata.ConsensusClientVersion); err == nil {
+			f.ConsensusClientVersionKey = key
+		}
+	}
 	f.EventSource = NewEventSourceFromType(types.EventSource(metadata.EventSource))
+	f.Network = metadata.Network
+	f.NodeContext = metadata.NodeContext
 
 	for _, label := range metadata.Labels {
 		f.Labels = append(f.Labels, FrameMetadataLabel{
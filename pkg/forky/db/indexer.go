@@ -39,9 +39,9 @@ func NewIndexer(namespace string, log logrus.FieldLogger, config IndexerConfig,
 
 		dialect := postgres.New(conf)
 
-		db, err = gorm.Open(dialect, &gorm.Config{})
+		db, err = gorm.Open(dialect, &gorm.Config{TranslateError: true})
 	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(config.DSN), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open(config.DSN), &gorm.Config{TranslateError: true})
 	default:
 		return nil, errors.New("invalid driver name: " + config.DriverName)
 	}
@@ -72,6 +72,11 @@ func NewIndexer(namespace string, log logrus.FieldLogger, config IndexerConfig,
 		return nil, perrors.Wrap(err, "failed to auto migrate frame_metadata_label")
 	}
 
+	err = db.AutoMigrate(&Reorg{})
+	if err != nil {
+		return nil, perrors.Wrap(err, "failed to auto migrate reorg")
+	}
+
 	return &Indexer{
 		db:      db,
 		log:     log.WithField("component", "indexer"),
@@ -80,6 +85,8 @@ func NewIndexer(namespace string, log logrus.FieldLogger, config IndexerConfig,
 	}, nil
 }
 
+// InsertFrameMetadata indexes a frame. It returns ErrFrameAlreadyIndexed if a
+// frame with the same ID has already been indexed.
 func (i *Indexer) InsertFrameMetadata(ctx context.Context, metadata *types.FrameMetadata) error {
 	operation := OperationInsertFrameMetadata
 	i.metrics.ObserveOperation(operation)
@@ -87,6 +94,35 @@ func (i *Indexer) InsertFrameMetadata(ctx context.Context, metadata *types.Frame
 	var f FrameMetadata
 
 	result := i.db.WithContext(ctx).Create(f.FromFrameMetadata(metadata))
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		return ErrFrameAlreadyIndexed
+	}
+
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+	}
+
+	return result.Error
+}
+
+// InsertFrameMetadatas inserts many frame metadata rows (and their labels) in a single batch.
+func (i *Indexer) InsertFrameMetadatas(ctx context.Context, metadatas []*types.FrameMetadata) error {
+	operation := OperationInsertFrameMetadatas
+	i.metrics.ObserveOperation(operation)
+
+	if len(metadatas) == 0 {
+		return nil
+	}
+
+	frames := make([]*FrameMetadata, len(metadatas))
+
+	for idx, metadata := range metadatas {
+		var f FrameMetadata
+
+		frames[idx] = f.FromFrameMetadata(metadata)
+	}
+
+	result := i.db.WithContext(ctx).CreateInBatches(frames, len(frames))
 	if result.Error != nil {
 		i.metrics.ObserveOperationError(operation)
 	}
@@ -190,6 +226,24 @@ func (i *Indexer) ListFrameMetadata(ctx context.Context, filter *FrameFilter, pa
 	return frames, nil
 }
 
+// ListFrameIDs returns the IDs of every frame in the indexer.
+func (i *Indexer) ListFrameIDs(ctx context.Context) ([]string, error) {
+	operation := OperationListFrameIDs
+
+	i.metrics.ObserveOperation(operation)
+
+	var ids []string
+
+	result := i.db.WithContext(ctx).Model(&FrameMetadata{}).Order("fetched_at ASC").Pluck("id", &ids)
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return nil, result.Error
+	}
+
+	return ids, nil
+}
+
 func (i *Indexer) CountNodesWithFrames(ctx context.Context, filter *FrameFilter) (int64, error) {
 	operation := OperationCountNodesWithFrames
 
@@ -645,3 +699,193 @@ func (i *Indexer) getFrameIDsWithLabels(ctx context.Context, labels []string, pa
 
 	return frameIDs, nil
 }
+
+// ListFrameMetadataWithLabelPrefix lists frames that have at least one label starting with the prefix.
+func (i *Indexer) ListFrameMetadataWithLabelPrefix(ctx context.Context, prefix string, page *PaginationCursor) ([]*FrameMetadata, error) {
+	operation := OperationListFrameMetadataWithLabelPrefix
+
+	i.metrics.ObserveOperation(operation)
+
+	var frames []*FrameMetadata
+
+	frameIDs := i.db.WithContext(ctx).
+		Model(&FrameMetadataLabel{}).
+		Select("frame_id").
+		Where("name LIKE ?", prefix+"%")
+
+	query := i.db.WithContext(ctx).Model(&FrameMetadata{}).Where("id IN (?)", frameIDs)
+
+	if page != nil {
+		query = page.ApplyOffsetLimit(query)
+
+		query = page.ApplyOrderBy(query)
+	}
+
+	result := query.Preload("Labels").Find(&frames)
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return nil, result.Error
+	}
+
+	return frames, nil
+}
+
+// ListFrameMetadataWithoutVersionKey lists frames without a consensus client
+// version sort key that may be able to have one derived, either from their
+// version or from the version their node reported.
+func (i *Indexer) ListFrameMetadataWithoutVersionKey(ctx context.Context, page *PaginationCursor) ([]*FrameMetadata, error) {
+	operation := OperationListFrameMetadataWithoutVersion
+
+	i.metrics.ObserveOperation(operation)
+
+	var frames []*FrameMetadata
+
+	query := i.db.WithContext(ctx).
+		Model(&FrameMetadata{}).
+		Where("consensus_client_version_key = ''").
+		Where("consensus_client_version != '' OR node_context IS NOT NULL")
+
+	if page != nil {
+		query = page.ApplyOffsetLimit(query)
+
+		query = page.ApplyOrderBy(query)
+	}
+
+	result := query.Preload("Labels").Find(&frames)
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return nil, result.Error
+	}
+
+	return frames, nil
+}
+
+// InsertReorg inserts a reorg. If a reorg with the same ID already exists any
+// frame IDs it is missing are filled in from the new reorg.
+func (i *Indexer) InsertReorg(ctx context.Context, reorg *types.Reorg) error {
+	operation := OperationInsertReorg
+
+	i.metrics.ObserveOperation(operation)
+
+	err := i.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing Reorg
+
+		result := tx.Where("id = ?", reorg.ID).Limit(1).Find(&existing)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			var r Reorg
+
+			return tx.Create(r.FromReorg(reorg)).Error
+		}
+
+		updates := map[string]interface{}{}
+
+		if existing.Network == "" && reorg.Network != "" {
+			updates["network"] = reorg.Network
+		}
+
+		if existing.BeforeFrameID == "" && reorg.BeforeFrameID != "" {
+			updates["before_frame_id"] = reorg.BeforeFrameID
+		}
+
+		if existing.AfterFrameID == "" && reorg.AfterFrameID != "" {
+			updates["after_frame_id"] = reorg.AfterFrameID
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+
+		return tx.Model(&existing).Updates(updates).Error
+	})
+	if err != nil {
+		i.metrics.ObserveOperationError(operation)
+	}
+
+	return err
+}
+
+func (i *Indexer) CountReorgs(ctx context.Context, filter *ReorgFilter) (int64, error) {
+	operation := OperationCountReorgs
+
+	i.metrics.ObserveOperation(operation)
+
+	var count int64
+
+	query := filter.ApplyToQuery(i.db.WithContext(ctx).Model(&Reorg{}))
+
+	result := query.Count(&count)
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+func (i *Indexer) ListReorgs(ctx context.Context, filter *ReorgFilter, page *PaginationCursor) ([]*Reorg, error) {
+	operation := OperationListReorgs
+
+	i.metrics.ObserveOperation(operation)
+
+	var reorgs []*Reorg
+
+	query := filter.ApplyToQuery(i.db.WithContext(ctx).Model(&Reorg{}))
+
+	if page != nil {
+		query = page.ApplyOffsetLimit(query)
+	}
+
+	if page != nil && page.OrderBy != "" {
+		query = query.Order(page.OrderBy)
+	} else {
+		query = query.Order("detected_at DESC")
+	}
+
+	result := query.Find(&reorgs)
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return nil, result.Error
+	}
+
+	return reorgs, nil
+}
+
+// SetEmptyReorgNetwork sets the network of every reorg that doesn't have one.
+func (i *Indexer) SetEmptyReorgNetwork(ctx context.Context, network string) (int64, error) {
+	operation := OperationUpdateReorgs
+
+	i.metrics.ObserveOperation(operation)
+
+	result := i.db.WithContext(ctx).Model(&Reorg{}).Where("network = ?", "").Update("network", network)
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteReorgsBefore deletes every reorg detected before the given time.
+func (i *Indexer) DeleteReorgsBefore(ctx context.Context, before time.Time) (int64, error) {
+	operation := OperationDeleteReorgs
+
+	i.metrics.ObserveOperation(operation)
+
+	result := i.db.WithContext(ctx).Unscoped().Where("detected_at < ?", before).Delete(&Reorg{})
+	if result.Error != nil {
+		i.metrics.ObserveOperationError(operation)
+
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
@@ -127,6 +127,70 @@ func TestIndexer_AddFrame(t *testing.T) {
 		err = indexer.InsertFrameMetadata(context.Background(), frame)
 		assert.NoError(t, err)
 	})
+
+	t.Run("already indexed", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frame := &types.FrameMetadata{
+			ID:              uuid.New().String(),
+			Node:            "node",
+			WallClockSlot:   phase0.Slot(42),
+			WallClockEpoch:  phase0.Epoch(21),
+			FetchedAt:       time.Now(),
+			Labels:          []string{"a"},
+			ConsensusClient: "prysm",
+			EventSource:     BeaconNodeEventSource.String(),
+		}
+
+		err = indexer.InsertFrameMetadata(context.Background(), frame)
+		assert.NoError(t, err)
+
+		err = indexer.InsertFrameMetadata(context.Background(), frame)
+		assert.ErrorIs(t, err, ErrFrameAlreadyIndexed)
+	})
+
+	t.Run("with node context", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id := uuid.New().String()
+		peerCount := 42
+		isSyncing := true
+		headSlot := phase0.Slot(100)
+
+		frame := &types.FrameMetadata{
+			ID:             id,
+			Node:           "node",
+			WallClockSlot:  phase0.Slot(42),
+			WallClockEpoch: phase0.Epoch(21),
+			FetchedAt:      time.Now(),
+			NodeContext: &types.NodeContext{
+				Version:   "Lighthouse/v5.0.0-abcdef/x86_64-linux",
+				PeerCount: &peerCount,
+				IsSyncing: &isSyncing,
+				HeadSlot:  &headSlot,
+				FinalizedCheckpoint: &phase0.Checkpoint{
+					Epoch: 2,
+					Root:  phase0.Root{0x01},
+				},
+			},
+		}
+
+		err = indexer.InsertFrameMetadata(context.Background(), frame)
+		assert.NoError(t, err)
+
+		frames, err := indexer.ListFrameMetadata(context.Background(), &FrameFilter{
+			ID: &id,
+		}, &PaginationCursor{})
+		assert.NoError(t, err)
+		assert.Len(t, frames, 1)
+		assert.Equal(t, frame.NodeContext, frames[0].AsFrameMetadata().NodeContext)
+	})
 }
 
 //nolint:gocyclo // its a test m8
@@ -230,6 +294,92 @@ func TestIndexer_ListFrames(t *testing.T) {
 		assert.Equal(t, frame.Node, frames[0].Node)
 	})
 
+	t.Run("By Network", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, network := range []string{"mainnet", "devnet-1"} {
+			err = indexer.InsertFrameMetadata(context.Background(), &types.FrameMetadata{
+				ID:             uuid.New().String(),
+				Node:           "node1",
+				WallClockSlot:  phase0.Slot(42),
+				WallClockEpoch: phase0.Epoch(21),
+				FetchedAt:      time.Now(),
+				Network:        network,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		filter := &FrameFilter{}
+		filter.AddNetwork("devnet-1")
+
+		frames, err := indexer.ListFrameMetadata(context.Background(), filter, &PaginationCursor{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, frames, 1)
+		assert.Equal(t, "devnet-1", frames[0].Network)
+	})
+
+	t.Run("By ConsensusClientVersion range", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, version := range []string{"4.6.0", "5.0.0", "5.1.0-rc1", "5.10.0", "", "nightly"} {
+			err = indexer.InsertFrameMetadata(context.Background(), &types.FrameMetadata{
+				ID:                     uuid.New().String(),
+				Node:                   "node1",
+				WallClockSlot:          phase0.Slot(42),
+				WallClockEpoch:         phase0.Epoch(21),
+				FetchedAt:              time.Now(),
+				ConsensusClient:        "lighthouse",
+				ConsensusClientVersion: version,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		filter := &FrameFilter{}
+		filter.AddMinConsensusClientVersion("v5.0.0")
+		filter.AddMaxConsensusClientVersion("5.9")
+
+		frames, err := indexer.ListFrameMetadata(context.Background(), filter, &PaginationCursor{OrderBy: "consensus_client_version ASC"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		versions := []string{}
+		for _, frame := range frames {
+			versions = append(versions, frame.ConsensusClientVersion)
+		}
+
+		assert.Equal(t, []string{"5.0.0", "5.1.0-rc1"}, versions)
+
+		filter = &FrameFilter{}
+		filter.AddConsensusClientVersion("nightly")
+
+		frames, err = indexer.ListFrameMetadata(context.Background(), filter, &PaginationCursor{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, frames, 1)
+
+		filter = &FrameFilter{}
+		filter.AddMinConsensusClientVersion("latest")
+
+		_, err = indexer.ListFrameMetadata(context.Background(), filter, &PaginationCursor{})
+		assert.Error(t, err)
+	})
+
 	t.Run("By WallClockSlot", func(t *testing.T) {
 		indexer, _, err := newMockIndexer()
 		if err != nil {
@@ -265,6 +415,45 @@ func TestIndexer_ListFrames(t *testing.T) {
 		assert.Equal(t, int64(frame.WallClockSlot), frames[0].WallClockSlot)
 	})
 
+	t.Run("By WallClockSlot range", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, slot := range []phase0.Slot{40, 41, 42, 43, 44} {
+			err = indexer.InsertFrameMetadata(context.Background(), &types.FrameMetadata{
+				ID:             uuid.New().String(),
+				Node:           "node",
+				WallClockSlot:  slot,
+				WallClockEpoch: phase0.Epoch(21),
+				FetchedAt:      time.Now(),
+				Labels:         []string{"a"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		minSlot := uint64(41)
+		maxSlot := uint64(43)
+
+		frames, err := indexer.ListFrameMetadata(context.Background(), &FrameFilter{
+			MinSlot: &minSlot,
+			MaxSlot: &maxSlot,
+		}, &PaginationCursor{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, frames, 3)
+
+		for _, frame := range frames {
+			assert.GreaterOrEqual(t, frame.WallClockSlot, int64(41))
+			assert.LessOrEqual(t, frame.WallClockSlot, int64(43))
+		}
+	})
+
 	t.Run("By WallClockEpoch", func(t *testing.T) {
 		indexer, _, err := newMockIndexer()
 		if err != nil {
@@ -818,3 +1007,186 @@ func TestIndexer_DeleteFrameMetadata(t *testing.T) {
 		assert.Len(t, nodes, 0)
 	})
 }
+
+func TestIndexer_ListFrameIDs(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := []string{}
+
+		for i := 0; i < 5; i++ {
+			frame := &types.FrameMetadata{
+				ID:        uuid.New().String(),
+				Node:      fmt.Sprintf("node%d", i),
+				FetchedAt: time.Now().Add(time.Duration(i) * time.Second),
+				Labels:    []string{"a"},
+			}
+
+			err = indexer.InsertFrameMetadata(context.Background(), frame)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids = append(ids, frame.ID)
+		}
+
+		listed, err := indexer.ListFrameIDs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, ids, listed)
+
+		err = indexer.DeleteFrameMetadata(context.Background(), ids[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		listed, err = indexer.ListFrameIDs(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, ids[1:], listed)
+	})
+}
+
+func TestIndexer_InsertFrameMetadatas(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frames := []*types.FrameMetadata{}
+
+		for i := 0; i < 5; i++ {
+			frames = append(frames, &types.FrameMetadata{
+				ID:        uuid.New().String(),
+				Node:      fmt.Sprintf("node%d", i),
+				FetchedAt: time.Now(),
+				Labels:    []string{"a", fmt.Sprintf("label%d", i)},
+			})
+		}
+
+		err = indexer.InsertFrameMetadatas(context.Background(), frames)
+		assert.NoError(t, err)
+
+		for _, frame := range frames {
+			listed, err := indexer.ListFrameMetadata(context.Background(), &FrameFilter{
+				ID: &frame.ID,
+			}, &PaginationCursor{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Len(t, listed, 1)
+			assert.ElementsMatch(t, frame.Labels, listed[0].AsFrameMetadata().Labels)
+		}
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		frame := &types.FrameMetadata{
+			ID:        uuid.New().String(),
+			Node:      "node",
+			FetchedAt: time.Now(),
+		}
+
+		err = indexer.InsertFrameMetadata(context.Background(), frame)
+		assert.NoError(t, err)
+
+		err = indexer.InsertFrameMetadatas(context.Background(), []*types.FrameMetadata{frame})
+		assert.Error(t, err)
+	})
+}
+
+func TestIndexer_Reorgs(t *testing.T) {
+	t.Run("Insert merges frame ids", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reorg := &types.Reorg{
+			ID:           types.NewReorgID("node", 100, "0x01", "0x02"),
+			Node:         "node",
+			DetectedAt:   time.Now(),
+			Slot:         100,
+			Depth:        2,
+			OldHeadBlock: "0x01",
+			NewHeadBlock: "0x02",
+			AfterFrameID: "after",
+		}
+
+		err = indexer.InsertReorg(context.Background(), reorg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		second := *reorg
+		second.AfterFrameID = ""
+		second.BeforeFrameID = "before"
+
+		err = indexer.InsertReorg(context.Background(), &second)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reorgs, err := indexer.ListReorgs(context.Background(), &ReorgFilter{}, &PaginationCursor{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, reorgs, 1)
+		assert.Equal(t, "before", reorgs[0].BeforeFrameID)
+		assert.Equal(t, "after", reorgs[0].AfterFrameID)
+	})
+
+	t.Run("Filter by depth and frame", func(t *testing.T) {
+		indexer, _, err := newMockIndexer()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for depth := uint64(1); depth <= 3; depth++ {
+			err = indexer.InsertReorg(context.Background(), &types.Reorg{
+				ID:           uuid.New().String(),
+				Node:         "node",
+				DetectedAt:   time.Now(),
+				Depth:        depth,
+				AfterFrameID: fmt.Sprintf("frame-%d", depth),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		minDepth := uint64(2)
+
+		count, err := indexer.CountReorgs(context.Background(), &ReorgFilter{MinDepth: &minDepth})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, int64(2), count)
+
+		filter := &ReorgFilter{}
+		filter.AddFrameID("frame-3")
+
+		reorgs, err := indexer.ListReorgs(context.Background(), filter, &PaginationCursor{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, reorgs, 1)
+		assert.Equal(t, int64(3), reorgs[0].Depth)
+	})
+}
@@ -3,11 +3,22 @@ package db
 type Operation string
 
 const (
-	OperationInsertFrameMetadata Operation = "insert_frame_metadata"
-	OperationDeleteFrameMetadata Operation = "delete_frame_metadata"
-	OperationCountFrameMetadata  Operation = "count_frame_metadata"
-	OperationListFrameMetadata   Operation = "list_frame_metadata"
-	OperationUpdateFrameMetadata Operation = "update_frame_metadata"
+	OperationInsertFrameMetadata  Operation = "insert_frame_metadata"
+	OperationInsertFrameMetadatas Operation = "insert_frame_metadatas"
+	OperationDeleteFrameMetadata  Operation = "delete_frame_metadata"
+	OperationCountFrameMetadata   Operation = "count_frame_metadata"
+	OperationListFrameMetadata    Operation = "list_frame_metadata"
+	OperationUpdateFrameMetadata  Operation = "update_frame_metadata"
+	OperationListFrameIDs         Operation = "list_frame_ids"
+
+	OperationListFrameMetadataWithLabelPrefix Operation = "list_frame_metadata_with_label_prefix"
+	OperationListFrameMetadataWithoutVersion  Operation = "list_frame_metadata_without_version"
+
+	OperationInsertReorg  Operation = "insert_reorg"
+	OperationCountReorgs  Operation = "count_reorgs"
+	OperationListReorgs   Operation = "list_reorgs"
+	OperationDeleteReorgs Operation = "delete_reorgs"
+	OperationUpdateReorgs Operation = "update_reorgs"
 
 	OperationCountNodesWithFrames Operation = "count_nodes_with_frames"
 	OperationsListNodesWithFrames Operation = "list_nodes_with_frames"
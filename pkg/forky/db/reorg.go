@@ -0,0 +1,84 @@
+package db
+
+import (
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"gorm.io/gorm"
+)
+
+type Reorg struct {
+	gorm.Model
+	ID              string    `gorm:"primaryKey"`
+	Network         string    `gorm:"index;not null;default:''"`
+	Node            string    `gorm:"index"`
+	ConsensusClient string    `gorm:"not null;default:''"`
+	DetectedAt      time.Time `gorm:"index"`
+	// See FrameMetadata for why these are int64.
+	Slot          int64 `gorm:"index"`
+	Epoch         int64
+	Depth         int64  `gorm:"index"`
+	OldHeadBlock  string `gorm:"index"`
+	OldHeadState  string
+	NewHeadBlock  string `gorm:"index"`
+	NewHeadState  string
+	BeforeFrameID string `gorm:"index"`
+	AfterFrameID  string `gorm:"index"`
+}
+
+type Reorgs []*Reorg
+
+func (r *Reorgs) AsReorgs() []*types.Reorg {
+	reorgs := make([]*types.Reorg, len(*r))
+
+	for i, reorg := range *r {
+		reorgs[i] = reorg.AsReorg()
+	}
+
+	return reorgs
+}
+
+func (r *Reorg) AsReorg() *types.Reorg {
+	return &types.Reorg{
+		ID:              r.ID,
+		Network:         r.Network,
+		Node:            r.Node,
+		ConsensusClient: r.ConsensusClient,
+		DetectedAt:      r.DetectedAt,
+		//nolint:gosec // ignore integer overflow conversion int64 -> uint64
+		Slot: phase0.Slot(r.Slot),
+		//nolint:gosec // ignore integer overflow conversion int64 -> uint64
+		Epoch: phase0.Epoch(r.Epoch),
+		//nolint:gosec // ignore integer overflow conversion int64 -> uint64
+		Depth:         uint64(r.Depth),
+		OldHeadBlock:  r.OldHeadBlock,
+		OldHeadState:  r.OldHeadState,
+		NewHeadBlock:  r.NewHeadBlock,
+		NewHeadState:  r.NewHeadState,
+		BeforeFrameID: r.BeforeFrameID,
+		AfterFrameID:  r.AfterFrameID,
+	}
+}
+
+func (r *Reorg) FromReorg(reorg *types.Reorg) *Reorg {
+	r.ID = reorg.ID
+	r.Network = reorg.Network
+	r.Node = reorg.Node
+	r.ConsensusClient = reorg.ConsensusClient
+	r.DetectedAt = reorg.DetectedAt
+	//nolint:gosec // ignore integer overflow conversion uint64 -> int64
+	r.Slot = int64(reorg.Slot)
+	//nolint:gosec // ignore integer overflow conversion uint64 -> int64
+	r.Epoch = int64(reorg.Epoch)
+	//nolint:gosec // ignore integer overflow conversion uint64 -> int64
+	r.Depth = int64(reorg.Depth)
+	r.OldHeadBlock = reorg.OldHeadBlock
+	r.OldHeadState = reorg.OldHeadState
+	r.NewHeadBlock = reorg.NewHeadBlock
+	r.NewHeadState = reorg.NewHeadState
+	r.BeforeFrameID = reorg.BeforeFrameID
+	r.AfterFrameID = reorg.AfterFrameID
+
+	return r
+}
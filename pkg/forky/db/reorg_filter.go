@@ -0,0 +1,92 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReorgFilter struct {
+	ID              *string
+	Network         *string
+	Node            *string
+	Before          *time.Time
+	After           *time.Time
+	Slot            *uint64
+	MinSlot         *uint64
+	MaxSlot         *uint64
+	Epoch           *uint64
+	MinDepth        *uint64
+	OldHeadBlock    *string
+	NewHeadBlock    *string
+	ConsensusClient *string
+	FrameID         *string
+}
+
+func (f *ReorgFilter) AddID(id string) {
+	f.ID = &id
+}
+
+func (f *ReorgFilter) AddFrameID(id string) {
+	f.FrameID = &id
+}
+
+func (f *ReorgFilter) ApplyToQuery(query *gorm.DB) *gorm.DB {
+	if f.ID != nil {
+		query = query.Where("id = ?", f.ID)
+	}
+
+	if f.Network != nil {
+		query = query.Where("network = ?", f.Network)
+	}
+
+	if f.Node != nil {
+		query = query.Where("node = ?", f.Node)
+	}
+
+	if f.Before != nil {
+		query = query.Where("detected_at <= ?", f.Before)
+	}
+
+	if f.After != nil {
+		query = query.Where("detected_at >= ?", f.After)
+	}
+
+	if f.Slot != nil {
+		query = query.Where("slot = ?", f.Slot)
+	}
+
+	if f.MinSlot != nil {
+		query = query.Where("slot >= ?", f.MinSlot)
+	}
+
+	if f.MaxSlot != nil {
+		query = query.Where("slot <= ?", f.MaxSlot)
+	}
+
+	if f.Epoch != nil {
+		query = query.Where("epoch = ?", f.Epoch)
+	}
+
+	if f.MinDepth != nil {
+		query = query.Where("depth >= ?", f.MinDepth)
+	}
+
+	if f.OldHeadBlock != nil {
+		query = query.Where("old_head_block = ?", f.OldHeadBlock)
+	}
+
+	if f.NewHeadBlock != nil {
+		query = query.Where("new_head_block = ?", f.NewHeadBlock)
+	}
+
+	if f.ConsensusClient != nil {
+		query = query.Where("consensus_client = ?", f.ConsensusClient)
+	}
+
+	if f.FrameID != nil {
+		query = query.Where("before_frame_id = ? OR after_frame_id = ?", f.FrameID, f.FrameID)
+	}
+
+	return query
+}
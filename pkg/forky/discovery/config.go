@@ -0,0 +1,65 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/human"
+	"github.com/ethpandaops/forky/pkg/yaml"
+)
+
+// defaultInterval is how often backends are refreshed if no interval is configured.
+const defaultInterval = time.Minute
+
+type Config struct {
+	// Name identifies the discovery backend and prefixes the names of the
+	// sources it creates.
+	Name string `yaml:"name"`
+	Type Type   `yaml:"type"`
+	// Network is the name of the network discovered sources are on. Defaults
+	// to the default network.
+	Network string `yaml:"network"`
+	// Interval is how often the backend is refreshed.
+	Interval human.Duration  `yaml:"interval"`
+	Config   yaml.RawMessage `yaml:"config"`
+	// Source is the template for the beacon_node sources created for every
+	// discovered node.
+	Source SourceTemplate `yaml:"source"`
+}
+
+type SourceTemplate struct {
+	// Name is a text/template for the source name. Defaults to
+	// "{{ .Discovery }}-{{ .Name }}".
+	Name string `yaml:"name"`
+	// Labels are text/templates added to the source's labels. Labels that
+	// render to an empty string are dropped.
+	Labels []string `yaml:"labels"`
+	// Config is the beacon_node config shared by every discovered node. The
+	// address is set per node.
+	Config yaml.RawMessage `yaml:"config"`
+}
+
+func (c *Config) Validate() error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if !IsValidType(c.Type) {
+		return fmt.Errorf("invalid discovery type: %s", c.Type)
+	}
+
+	if c.Interval.Duration < 0 {
+		return errors.New("interval must be greater than 0")
+	}
+
+	return nil
+}
+
+func (c *Config) GetInterval() time.Duration {
+	if c.Interval.Duration == 0 {
+		return defaultInterval
+	}
+
+	return c.Interval.Duration
+}
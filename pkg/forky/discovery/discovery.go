@@ -0,0 +1,78 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethpandaops/forky/pkg/yaml"
+)
+
+// Node is a beacon node found by a discovery backend.
+type Node struct {
+	// Name uniquely identifies the node within its discovery backend.
+	Name    string            `yaml:"name" json:"name"`
+	Address string            `yaml:"address" json:"address"`
+	Labels  map[string]string `yaml:"labels" json:"labels"`
+}
+
+// Provider is an interface for different discovery backends.
+type Provider interface {
+	// Type returns the type of the provider.
+	Type() Type
+	// Discover returns every node the backend currently knows about.
+	Discover(ctx context.Context) ([]Node, error)
+}
+
+func NewProvider(providerType Type, config yaml.RawMessage) (Provider, error) {
+	switch providerType {
+	case FileType:
+		conf := FileConfig{}
+
+		if err := config.Unmarshal(&conf); err != nil {
+			return nil, err
+		}
+
+		return NewFile(&conf)
+	case DNSType:
+		conf := DNSConfig{}
+
+		if err := config.Unmarshal(&conf); err != nil {
+			return nil, err
+		}
+
+		return NewDNS(&conf)
+	case HTTPType:
+		conf := HTTPConfig{}
+
+		if err := config.Unmarshal(&conf); err != nil {
+			return nil, err
+		}
+
+		return NewHTTP(&conf)
+	default:
+		return nil, fmt.Errorf("unknown discovery type: %s", providerType)
+	}
+}
+
+// validateNodes checks that every node has a unique name and an address.
+func validateNodes(nodes []Node) error {
+	seen := make(map[string]struct{}, len(nodes))
+
+	for i, node := range nodes {
+		if node.Name == "" {
+			return fmt.Errorf("node %d: name is required", i)
+		}
+
+		if node.Address == "" {
+			return fmt.Errorf("node %s: address is required", node.Name)
+		}
+
+		if _, ok := seen[node.Name]; ok {
+			return fmt.Errorf("node %s: duplicate name", node.Name)
+		}
+
+		seen[node.Name] = struct{}{}
+	}
+
+	return nil
+}
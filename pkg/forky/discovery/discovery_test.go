@@ -0,0 +1,198 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethpandaops/forky/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+type fakeProvider struct {
+	nodes []Node
+	err   error
+}
+
+func (f *fakeProvider) Type() Type { return FileType }
+
+func (f *fakeProvider) Discover(_ context.Context) ([]Node, error) {
+	return f.nodes, f.err
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes.yaml")
+
+	provider, err := NewFile(&FileConfig{Path: path})
+	assert.NoError(t, err)
+
+	_, err = provider.Discover(context.Background())
+	assert.Error(t, err)
+
+	err = os.WriteFile(path, []byte(`
+- name: lighthouse-1
+  address: http://lighthouse-1:5052
+  labels:
+    client: lighthouse
+- name: teku-1
+  address: http://teku-1:5051
+`), 0o600)
+	assert.NoError(t, err)
+
+	nodes, err := provider.Discover(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Node{
+		{Name: "lighthouse-1", Address: "http://lighthouse-1:5052", Labels: map[string]string{"client": "lighthouse"}},
+		{Name: "teku-1", Address: "http://teku-1:5051"},
+	}, nodes)
+
+	err = os.WriteFile(path, []byte(`[{"name": "a", "address": "http://a"}, {"name": "a", "address": "http://b"}]`), 0o600)
+	assert.NoError(t, err)
+
+	_, err = provider.Discover(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDNS(t *testing.T) {
+	provider, err := NewDNS(&DNSConfig{Record: "_beacon._tcp.devnet.example.com"})
+	assert.NoError(t, err)
+
+	provider.lookupSRV = func(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+		assert.Equal(t, "_beacon._tcp.devnet.example.com", name)
+
+		return "", []*net.SRV{
+			{Target: "node-1.devnet.example.com.", Port: 5052},
+			{Target: "node-2.devnet.example.com.", Port: 5052},
+			{Target: "node-1.devnet.example.com.", Port: 5052},
+		}, nil
+	}
+
+	nodes, err := provider.Discover(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Node{
+		{
+			Name:    "node-1.devnet.example.com:5052",
+			Address: "http://node-1.devnet.example.com:5052",
+			Labels:  map[string]string{"host": "node-1.devnet.example.com", "port": "5052"},
+		},
+		{
+			Name:    "node-2.devnet.example.com:5052",
+			Address: "http://node-2.devnet.example.com:5052",
+			Labels:  map[string]string{"host": "node-2.devnet.example.com", "port": "5052"},
+		},
+	}, nodes)
+
+	_, err = NewDNS(&DNSConfig{Record: "_beacon._tcp.devnet.example.com", Scheme: "ftp"})
+	assert.Error(t, err)
+}
+
+func TestHTTP(t *testing.T) {
+	t.Setenv("FORKY_TEST_DISCOVERY_TOKEN", "secret")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`[{"name": "prysm-1", "address": "http://prysm-1:3500", "labels": {"client": "prysm"}}]`))
+	}))
+	defer server.Close()
+
+	provider, err := NewHTTP(&HTTPConfig{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "Bearer ${FORKY_TEST_DISCOVERY_TOKEN}"},
+	})
+	assert.NoError(t, err)
+
+	nodes, err := provider.Discover(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Node{{Name: "prysm-1", Address: "http://prysm-1:3500", Labels: map[string]string{"client": "prysm"}}}, nodes)
+
+	provider.config.Headers = nil
+
+	_, err = provider.Discover(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDiscovery(t *testing.T) {
+	provider := &fakeProvider{
+		nodes: []Node{
+			{Name: "lighthouse-1", Address: "http://lighthouse-1:5052", Labels: map[string]string{"client": "lighthouse"}},
+			{Name: "teku-1", Address: "http://teku-1:5051"},
+		},
+	}
+
+	config := &Config{
+		Name: "devnet",
+		Type: FileType,
+		Source: SourceTemplate{
+			Labels: []string{
+				"client={{ .Labels.client }}",
+				"{{ with .Labels.client }}has_client{{ end }}",
+				"node={{ .Name }}",
+			},
+			Config: yaml.NewRawMessage([]byte(`{"polling_interval": "12s", "labels": ["devnet"]}`)),
+		},
+	}
+
+	d, err := newDiscovery(config, provider)
+	assert.NoError(t, err)
+
+	sources, err := d.Discover(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, sources, 2)
+
+	assert.Equal(t, "devnet-lighthouse-1", sources[0].Name)
+	assert.Equal(t, "devnet-teku-1", sources[1].Name)
+
+	rendered := struct {
+		Address         string   `yaml:"address"`
+		PollingInterval string   `yaml:"polling_interval"`
+		Labels          []string `yaml:"labels"`
+	}{}
+
+	assert.NoError(t, yamlv2.Unmarshal(sources[0].Config, &rendered))
+	assert.Equal(t, "http://lighthouse-1:5052", rendered.Address)
+	assert.Equal(t, "12s", rendered.PollingInterval)
+	assert.Equal(t, []string{"devnet", "client=lighthouse", "has_client", "node=lighthouse-1"}, rendered.Labels)
+
+	assert.NoError(t, yamlv2.Unmarshal(sources[1].Config, &rendered))
+	assert.Equal(t, []string{"devnet", "client=", "node=teku-1"}, rendered.Labels)
+
+	// Rendering is deterministic so unchanged nodes can be detected.
+	again, err := d.Discover(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, sources, again)
+
+	provider.err = errors.New("unreachable")
+
+	_, err = d.Discover(context.Background())
+	assert.Error(t, err)
+
+	t.Run("rejects per node config", func(t *testing.T) {
+		config.Source.Config = yaml.NewRawMessage([]byte(`{"address": "http://localhost:5052"}`))
+
+		_, err := newDiscovery(config, provider)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects duplicate source names", func(t *testing.T) {
+		config.Source.Config = yaml.RawMessage{}
+		config.Source.Name = "{{ .Discovery }}"
+		provider.err = nil
+
+		d, err := newDiscovery(config, provider)
+		assert.NoError(t, err)
+
+		_, err = d.Discover(context.Background())
+		assert.Error(t, err)
+	})
+}
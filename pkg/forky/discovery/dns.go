@@ -0,0 +1,89 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DNSConfig discovers nodes from DNS SRV records. Every target becomes a node
+// named host:port.
+type DNSConfig struct {
+	// Record is the full SRV record name, e.g. _beacon._tcp.devnet.example.com.
+	Record string `yaml:"record"`
+	// Scheme is the scheme used to build node addresses.
+	Scheme string `yaml:"scheme" default:"http"`
+}
+
+func (c *DNSConfig) Validate() error {
+	if c.Record == "" {
+		return errors.New("record is required")
+	}
+
+	if c.Scheme != "http" && c.Scheme != "https" {
+		return fmt.Errorf("invalid scheme: %s", c.Scheme)
+	}
+
+	return nil
+}
+
+type DNS struct {
+	config *DNSConfig
+
+	lookupSRV func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+func NewDNS(config *DNSConfig) (*DNS, error) {
+	if config.Scheme == "" {
+		config.Scheme = "http"
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &DNS{
+		config:    config,
+		lookupSRV: net.DefaultResolver.LookupSRV,
+	}, nil
+}
+
+func (d *DNS) Type() Type {
+	return DNSType
+}
+
+func (d *DNS) Discover(ctx context.Context) ([]Node, error) {
+	_, records, err := d.lookupSRV(ctx, "", "", d.config.Record)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]Node, 0, len(records))
+	seen := make(map[string]struct{}, len(records))
+
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		port := strconv.Itoa(int(record.Port))
+		name := net.JoinHostPort(host, port)
+
+		if _, ok := seen[name]; ok {
+			continue
+		}
+
+		seen[name] = struct{}{}
+
+		nodes = append(nodes, Node{
+			Name:    name,
+			Address: fmt.Sprintf("%s://%s", d.config.Scheme, name),
+			Labels: map[string]string{
+				"host": host,
+				"port": port,
+			},
+		})
+	}
+
+	return nodes, nil
+}
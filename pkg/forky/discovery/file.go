@@ -0,0 +1,61 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+// FileConfig discovers nodes from a YAML or JSON file containing a list of
+// nodes. The file is re-read on every refresh so it can be edited in place.
+type FileConfig struct {
+	Path string `yaml:"path"`
+}
+
+func (c *FileConfig) Validate() error {
+	if c.Path == "" {
+		return errors.New("path is required")
+	}
+
+	return nil
+}
+
+type File struct {
+	config *FileConfig
+}
+
+func NewFile(config *FileConfig) (*File, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &File{
+		config: config,
+	}, nil
+}
+
+func (f *File) Type() Type {
+	return FileType
+}
+
+func (f *File) Discover(_ context.Context) ([]Node, error) {
+	data, err := os.ReadFile(f.config.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{}
+
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", f.config.Path, err)
+	}
+
+	if err := validateNodes(nodes); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
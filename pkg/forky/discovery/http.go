@@ -0,0 +1,106 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// maxHTTPResponseSize is the largest node list the HTTP provider will read.
+const maxHTTPResponseSize = 10 * 1024 * 1024
+
+// HTTPConfig discovers nodes from an HTTP endpoint that returns a JSON list of nodes.
+type HTTPConfig struct {
+	URL string `yaml:"url"`
+	// Headers are sent with every request. Values are expanded with environment
+	// variables, e.g. "Bearer ${DISCOVERY_TOKEN}".
+	Headers map[string]string `yaml:"headers"`
+	Timeout string            `yaml:"timeout" default:"10s"`
+}
+
+func (c *HTTPConfig) Validate() error {
+	if c.URL == "" {
+		return errors.New("url is required")
+	}
+
+	if _, err := time.ParseDuration(c.Timeout); err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	}
+
+	return nil
+}
+
+type HTTP struct {
+	config *HTTPConfig
+	client *http.Client
+}
+
+func NewHTTP(config *HTTPConfig) (*HTTP, error) {
+	if config.Timeout == "" {
+		config.Timeout = "10s"
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	timeout, err := time.ParseDuration(config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTTP{
+		config: config,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (h *HTTP) Type() Type {
+	return HTTPType
+}
+
+func (h *HTTP) Discover(ctx context.Context) ([]Node, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.config.URL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	for key, value := range h.config.Headers {
+		req.Header.Set(key, os.ExpandEnv(value))
+	}
+
+	rsp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", rsp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, maxHTTPResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{}
+
+	if err := json.Unmarshal(body, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if err := validateNodes(nodes); err != nil {
+		return nil, err
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,233 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const defaultSourceNameTemplate = "{{ .Discovery }}-{{ .Name }}"
+
+// Source is a rendered beacon_node source for a discovered node.
+type Source struct {
+	Name string
+	// Config is the YAML encoded beacon_node config.
+	Config []byte
+}
+
+// templateData is what source name and label templates are rendered with.
+type templateData struct {
+	Discovery string
+	Name      string
+	Address   string
+	Labels    map[string]string
+}
+
+// Discovery turns the nodes found by a provider into beacon_node sources.
+type Discovery struct {
+	config   *Config
+	provider Provider
+
+	name       *template.Template
+	labels     []*template.Template
+	baseConfig map[string]interface{}
+	baseLabels []string
+}
+
+func NewDiscovery(config *Config) (*Discovery, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	provider, err := NewProvider(config.Type, config.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDiscovery(config, provider)
+}
+
+func newDiscovery(config *Config, provider Provider) (*Discovery, error) {
+	nameTemplate := config.Source.Name
+	if nameTemplate == "" {
+		nameTemplate = defaultSourceNameTemplate
+	}
+
+	name, err := parseTemplate("name", nameTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]*template.Template, 0, len(config.Source.Labels))
+
+	for i, label := range config.Source.Labels {
+		tmpl, err := parseTemplate(fmt.Sprintf("label %d", i), label)
+		if err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, tmpl)
+	}
+
+	baseConfig := map[string]interface{}{}
+
+	if err := config.Source.Config.Unmarshal(&baseConfig); err != nil {
+		return nil, fmt.Errorf("invalid source config: %w", err)
+	}
+
+	if _, ok := baseConfig["address"]; ok {
+		return nil, errors.New("source config must not set address as it's set per node")
+	}
+
+	if _, ok := baseConfig["endpoints"]; ok {
+		return nil, errors.New("source config must not set endpoints as the address is set per node")
+	}
+
+	baseLabels := []string{}
+
+	if raw, ok := baseConfig["labels"]; ok {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return nil, errors.New("source config labels must be a list")
+		}
+
+		for _, label := range list {
+			baseLabels = append(baseLabels, fmt.Sprint(label))
+		}
+	}
+
+	return &Discovery{
+		config:     config,
+		provider:   provider,
+		name:       name,
+		labels:     labels,
+		baseConfig: baseConfig,
+		baseLabels: baseLabels,
+	}, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+func (d *Discovery) Name() string {
+	return d.config.Name
+}
+
+func (d *Discovery) Type() Type {
+	return d.provider.Type()
+}
+
+func (d *Discovery) Interval() time.Duration {
+	return d.config.GetInterval()
+}
+
+// Network is the network discovered sources are on.
+func (d *Discovery) Network() string {
+	return d.config.Network
+}
+
+// Discover returns a beacon_node source for every node the provider currently knows about.
+func (d *Discovery) Discover(ctx context.Context) ([]Source, error) {
+	nodes, err := d.provider.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]Source, 0, len(nodes))
+	seen := make(map[string]struct{}, len(nodes))
+
+	for _, node := range nodes {
+		source, err := d.render(node)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", node.Name, err)
+		}
+
+		if _, ok := seen[source.Name]; ok {
+			return nil, fmt.Errorf("node %s: duplicate source name %s", node.Name, source.Name)
+		}
+
+		seen[source.Name] = struct{}{}
+
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
+func (d *Discovery) render(node Node) (Source, error) {
+	data := templateData{
+		Discovery: d.config.Name,
+		Name:      node.Name,
+		Address:   node.Address,
+		Labels:    node.Labels,
+	}
+
+	if data.Labels == nil {
+		data.Labels = map[string]string{}
+	}
+
+	name, err := execute(d.name, data)
+	if err != nil {
+		return Source{}, err
+	}
+
+	if name == "" {
+		return Source{}, errors.New("source name rendered to an empty string")
+	}
+
+	labels := append([]string{}, d.baseLabels...)
+
+	for _, tmpl := range d.labels {
+		label, err := execute(tmpl, data)
+		if err != nil {
+			return Source{}, err
+		}
+
+		if label == "" {
+			continue
+		}
+
+		labels = append(labels, label)
+	}
+
+	config := make(map[string]interface{}, len(d.baseConfig)+2)
+	for key, value := range d.baseConfig {
+		config[key] = value
+	}
+
+	config["address"] = node.Address
+	config["labels"] = labels
+
+	// Map keys are sorted when marshalled, so the same node always renders to
+	// the same config and can be compared to detect changes.
+	encoded, err := yaml.Marshal(config)
+	if err != nil {
+		return Source{}, err
+	}
+
+	return Source{
+		Name:   name,
+		Config: encoded,
+	}, nil
+}
+
+func execute(tmpl *template.Template, data templateData) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
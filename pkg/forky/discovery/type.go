@@ -0,0 +1,19 @@
+package discovery
+
+type Type string
+
+const (
+	UnknownType Type = "unknown"
+	FileType    Type = "file"
+	DNSType     Type = "dns"
+	HTTPType    Type = "http"
+)
+
+func IsValidType(t Type) bool {
+	switch t {
+	case FileType, DNSType, HTTPType:
+		return true
+	default:
+		return false
+	}
+}
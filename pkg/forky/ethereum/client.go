@@ -1,6 +1,8 @@
 package ethereum
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -13,6 +15,8 @@ const (
 	ClientTeku       Client = "teku"
 	ClientPrysm      Client = "prysm"
 	ClientLodestar   Client = "lodestar"
+	ClientGrandine   Client = "grandine"
+	ClientCaplin     Client = "caplin"
 )
 
 var AllClients = []Client{
@@ -22,30 +26,149 @@ var AllClients = []Client{
 	ClientTeku,
 	ClientPrysm,
 	ClientLodestar,
+	ClientGrandine,
+	ClientCaplin,
+}
+
+// clientIdentifiers maps substrings of a node's version string to the client
+// they identify. New clients only need adding here and to AllClients.
+var clientIdentifiers = []struct {
+	identifier string
+	client     Client
+}{
+	{"lighthouse", ClientLighthouse},
+	{"nimbus", ClientNimbus},
+	{"teku", ClientTeku},
+	{"prysm", ClientPrysm},
+	{"lodestar", ClientLodestar},
+	{"grandine", ClientGrandine},
+	{"caplin", ClientCaplin},
+	// Caplin is embedded in Erigon, which reports its own name.
+	{"erigon", ClientCaplin},
 }
 
 func ClientFromString(client string) Client {
 	asLower := strings.ToLower(client)
 
-	if strings.Contains(asLower, "lighthouse") {
-		return ClientLighthouse
+	for _, c := range clientIdentifiers {
+		if strings.Contains(asLower, c.identifier) {
+			return c.client
+		}
 	}
 
-	if strings.Contains(asLower, "nimbus") {
-		return ClientNimbus
+	return ClientUnknown
+}
+
+// ClientVersion is a node's version string broken into its parts. Parts that
+// aren't in the version string are left empty.
+type ClientVersion struct {
+	Client Client `json:"client"`
+	// Version is the release without a leading "v", e.g. "5.1.0" or "3.0.0-beta1".
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	OS      string `json:"os"`
+}
+
+// ParseClientVersion parses a node version string as returned by
+// /eth/v1/node/version, e.g. "Lighthouse/v5.1.0-a1b2c3d/x86_64-linux" or
+// "Prysm/v5.0.0 (linux amd64)".
+func ParseClientVersion(raw string) ClientVersion {
+	version := ClientVersion{
+		Client: ClientFromString(raw),
 	}
 
-	if strings.Contains(asLower, "teku") {
-		return ClientTeku
+	raw = strings.TrimSpace(raw)
+
+	// Prysm puts the OS in parentheses after the version.
+	if start := strings.Index(raw, "("); start != -1 {
+		if end := strings.LastIndex(raw, ")"); end > start {
+			version.OS = strings.TrimSpace(raw[start+1 : end])
+			raw = strings.TrimSpace(raw[:start] + raw[end+1:])
+		}
 	}
 
-	if strings.Contains(asLower, "prysm") {
-		return ClientPrysm
+	segments := strings.Split(raw, "/")
+	if len(segments) < 2 {
+		return version
 	}
 
-	if strings.Contains(asLower, "lodestar") {
-		return ClientLodestar
+	// The release may have the commit and a codename appended, e.g.
+	// "v24.1.0-8a5d17-stateofus".
+	parts := strings.Split(strings.TrimPrefix(segments[1], "v"), "-")
+
+	for i, part := range parts {
+		if i > 0 && isCommit(part) {
+			version.Commit = part
+
+			break
+		}
+
+		if version.Version != "" {
+			version.Version += "-"
+		}
+
+		version.Version += part
 	}
 
-	return ClientUnknown
+	for _, segment := range segments[2:] {
+		switch {
+		case segment == "":
+		case version.Commit == "" && isCommit(segment):
+			version.Commit = segment
+		case version.OS == "":
+			version.OS = segment
+		}
+	}
+
+	return version
+}
+
+// isCommit returns true if s looks like an abbreviated or full git commit hash.
+func isCommit(s string) bool {
+	if len(s) < 6 || len(s) > 40 {
+		return false
+	}
+
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VersionSortKey returns a key for the major, minor and patch of a version that
+// sorts lexically in version order, so version ranges can be queried in the
+// database. Pre-release suffixes are ignored.
+func VersionSortKey(version string) (string, error) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	if i := strings.IndexAny(version, "-+"); i != -1 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return "", fmt.Errorf("invalid version: %s", version)
+	}
+
+	key := make([]string, 3)
+
+	for i := range key {
+		number := uint64(0)
+
+		if i < len(parts) {
+			parsed, err := strconv.ParseUint(parts[i], 10, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid version: %s", version)
+			}
+
+			number = parsed
+		}
+
+		key[i] = fmt.Sprintf("%010d", number)
+	}
+
+	return strings.Join(key, "."), nil
 }
@@ -0,0 +1,76 @@
+package ethereum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientFromString(t *testing.T) {
+	tests := map[string]Client{
+		"Lighthouse/v5.1.0-a1b2c3d/x86_64-linux":    ClientLighthouse,
+		"Grandine/1.0.0-ca0e7ba/x86_64-linux":       ClientGrandine,
+		"Caplin/v3.0.0-1f3d2b3/linux-amd64":         ClientCaplin,
+		"erigon/2.60.0/linux-amd64/go1.22.4":        ClientCaplin,
+		"Prysm/v5.0.0 (linux amd64)":                ClientPrysm,
+		"somethingnew/v0.1.0/linux-amd64":           ClientUnknown,
+		"teku/v24.1.0/linux-x86_64/-eclipse-java21": ClientTeku,
+	}
+
+	for version, expected := range tests {
+		assert.Equal(t, expected, ClientFromString(version), version)
+	}
+}
+
+func TestParseClientVersion(t *testing.T) {
+	tests := map[string]ClientVersion{
+		"Lighthouse/v5.1.0-a1b2c3d/x86_64-linux": {
+			Client: ClientLighthouse, Version: "5.1.0", Commit: "a1b2c3d", OS: "x86_64-linux",
+		},
+		"Prysm/v5.0.0 (linux amd64)": {
+			Client: ClientPrysm, Version: "5.0.0", OS: "linux amd64",
+		},
+		"teku/v24.1.0/linux-x86_64/-eclipse-java21": {
+			Client: ClientTeku, Version: "24.1.0", OS: "linux-x86_64",
+		},
+		"Nimbus/v24.1.0-8a5d17-stateofus": {
+			Client: ClientNimbus, Version: "24.1.0", Commit: "8a5d17",
+		},
+		"Lodestar/v1.15.0/a6dd4f7": {
+			Client: ClientLodestar, Version: "1.15.0", Commit: "a6dd4f7",
+		},
+		"Caplin/v3.0.0-beta1-1f3d2b3/linux-amd64": {
+			Client: ClientCaplin, Version: "3.0.0-beta1", Commit: "1f3d2b3", OS: "linux-amd64",
+		},
+		"Grandine/1.0.0-ca0e7ba/x86_64-linux": {
+			Client: ClientGrandine, Version: "1.0.0", Commit: "ca0e7ba", OS: "x86_64-linux",
+		},
+		"unparseable": {
+			Client: ClientUnknown,
+		},
+	}
+
+	for version, expected := range tests {
+		assert.Equal(t, expected, ParseClientVersion(version), version)
+	}
+}
+
+func TestVersionSortKey(t *testing.T) {
+	ordered := []string{"1.9.0", "v1.10.0-rc1", "1.10.1", "5", "5.1.0", "24.1.0"}
+
+	previous := ""
+
+	for _, version := range ordered {
+		key, err := VersionSortKey(version)
+		assert.NoError(t, err)
+		assert.Greater(t, key, previous, version)
+
+		previous = key
+	}
+
+	_, err := VersionSortKey("latest")
+	assert.Error(t, err)
+
+	_, err = VersionSortKey("1.2.3.4")
+	assert.Error(t, err)
+}
@@ -1,16 +1,35 @@
 package ethereum
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 )
 
 type Config struct {
 	Network NetworkConfig `yaml:"network"`
+	// LabelFramesWithFork labels new frames with the fork that was active at
+	// their wall clock slot, e.g. "ethereum_fork_electra".
+	LabelFramesWithFork bool `yaml:"label_frames_with_fork" default:"false"`
 }
 
 type NetworkConfig struct {
+	// Name is the network name. Well known networks (see Presets) don't need a
+	// spec or fork schedule, but anything configured overrides the preset.
 	Name string     `yaml:"name"`
 	Spec SpecConfig `yaml:"spec"`
+	// Forks overrides the activation epoch of forks, keyed by fork name.
+	Forks ForkSchedule `yaml:"forks"`
+	// SpecDiscovery derives the spec from a beacon node instead of Spec.
+	SpecDiscovery SpecDiscoveryConfig `yaml:"spec_discovery"`
+}
+
+type SpecDiscoveryConfig struct {
+	// Enabled derives the spec from a beacon node rather than the config.
+	Enabled bool `yaml:"enabled"`
+	// Address is a dedicated beacon node to derive the spec from. If empty, the
+	// spec is derived from the first beacon_node source to bootstrap.
+	Address string `yaml:"address"`
 }
 
 type SpecConfig struct {
@@ -32,13 +51,61 @@ func (c *NetworkConfig) Validate() error {
 		return errors.New("name is required")
 	}
 
-	if err := c.Spec.Validate(); err != nil {
+	for fork := range c.Forks {
+		if !fork.Valid() {
+			return fmt.Errorf("unknown fork: %s", fork)
+		}
+	}
+
+	if c.SpecDiscovery.Enabled {
+		return nil
+	}
+
+	spec := c.GetSpec()
+
+	if err := spec.Validate(); err != nil {
 		return errors.Wrap(err, "invalid spec config")
 	}
 
 	return nil
 }
 
+// GetSpec returns the network's preset spec, if it has one, with anything
+// configured taking precedence.
+func (c *NetworkConfig) GetSpec() SpecConfig {
+	spec := c.Spec
+
+	preset, ok := PresetFor(c.Name)
+	if !ok {
+		return spec
+	}
+
+	if spec.SecondsPerSlot == 0 {
+		spec.SecondsPerSlot = preset.Spec.SecondsPerSlot
+	}
+
+	if spec.SlotsPerEpoch == 0 {
+		spec.SlotsPerEpoch = preset.Spec.SlotsPerEpoch
+	}
+
+	if spec.GenesisTime == 0 {
+		spec.GenesisTime = preset.Spec.GenesisTime
+	}
+
+	return spec
+}
+
+// GetForks returns the network's preset fork schedule, if it has one, with the
+// configured forks taking precedence.
+func (c *NetworkConfig) GetForks() ForkSchedule {
+	preset, ok := PresetFor(c.Name)
+	if !ok {
+		return ForkSchedule{}.Merge(c.Forks)
+	}
+
+	return preset.Forks.Merge(c.Forks)
+}
+
 func (c *SpecConfig) Validate() error {
 	if c.SecondsPerSlot == 0 {
 		return errors.New("seconds_per_slot is required")
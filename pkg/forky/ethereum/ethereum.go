@@ -1,20 +1,36 @@
 package ethereum
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/http"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/cenkalti/backoff/v4"
 	"github.com/ethpandaops/ethwallclock"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 	"github.com/sirupsen/logrus"
 )
 
 type BeaconChain struct {
-	log       logrus.FieldLogger
-	wallclock *ethwallclock.EthereumBeaconChain
-	config    *Config
+	log logrus.FieldLogger
 
+	config *Config
+
+	// mu protects everything below, which isn't known until the spec has been
+	// derived if spec discovery is enabled.
+	mu          sync.RWMutex
+	wallclock   *ethwallclock.EthereumBeaconChain
+	spec        SpecConfig
+	forks       ForkSchedule
 	genesisTime time.Time
+
+	// reference is the network every beacon node must be on. It's taken from
+	// the first beacon node to be verified.
+	reference *NetworkParameters
 }
 
 func NewBeaconChain(log logrus.FieldLogger, config *Config) (*BeaconChain, error) {
@@ -22,33 +38,167 @@ func NewBeaconChain(log logrus.FieldLogger, config *Config) (*BeaconChain, error
 		return nil, err
 	}
 
-	secondsPerSlot, err := time.ParseDuration(fmt.Sprintf("%vs", config.Network.Spec.SecondsPerSlot))
+	b := &BeaconChain{
+		log:    log.WithField("component", "ethereum/beaconchain"),
+		config: config,
+		forks:  config.Network.GetForks(),
+	}
+
+	if !config.Network.SpecDiscovery.Enabled {
+		if err := b.setSpec(config.Network.GetSpec()); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// setSpec sets the spec and creates the wallclock. Must be called while holding
+// mu, or before the beacon chain is shared.
+func (b *BeaconChain) setSpec(spec SpecConfig) error {
+	secondsPerSlot, err := time.ParseDuration(fmt.Sprintf("%vs", spec.SecondsPerSlot))
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to parse seconds per slot")
+		return errors.Wrap(err, "failed to parse seconds per slot")
 	}
 
 	//nolint:gosec // ignore integer overflow conversion uint64 -> int64
-	genesisTime := time.Unix(int64(config.Network.Spec.GenesisTime), 0)
+	genesisTime := time.Unix(int64(spec.GenesisTime), 0)
 
-	wc := ethwallclock.NewEthereumBeaconChain(genesisTime, secondsPerSlot, config.Network.Spec.SlotsPerEpoch)
+	b.wallclock = ethwallclock.NewEthereumBeaconChain(genesisTime, secondsPerSlot, spec.SlotsPerEpoch)
+	b.spec = spec
+	b.genesisTime = genesisTime
 
-	return &BeaconChain{
-		log:         log.WithField("component", "ethereum/beaconchain"),
-		wallclock:   wc,
-		config:      config,
-		genesisTime: genesisTime,
-	}, nil
+	return nil
 }
 
-func (b *BeaconChain) Start() error {
+// Start derives the spec from the dedicated spec discovery node, if one is
+// configured. It returns straight away and retries in the background until the
+// spec is known.
+func (b *BeaconChain) Start(ctx context.Context) error {
 	b.log.WithFields(logrus.Fields{
-		"network": b.config.Network.Name,
+		"network":        b.config.Network.Name,
+		"spec_discovery": b.config.Network.SpecDiscovery.Enabled,
 	}).Info("starting ethereum beacon chain")
 
+	if !b.config.Network.SpecDiscovery.Enabled || b.config.Network.SpecDiscovery.Address == "" {
+		return nil
+	}
+
+	go func() {
+		back := backoff.NewExponentialBackOff()
+
+		back.MaxInterval = time.Minute
+		back.MaxElapsedTime = 0
+
+		for {
+			err := b.discoverSpec(ctx)
+			if err == nil {
+				return
+			}
+
+			sleepFor := back.NextBackOff()
+
+			b.log.
+				WithError(err).
+				WithField("next_attempt_in", sleepFor.String()).
+				Error("Failed to derive network spec from beacon node")
+
+			select {
+			case <-time.After(sleepFor):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (b *BeaconChain) discoverSpec(ctx context.Context) error {
+	client, err := http.New(ctx,
+		http.WithAddress(b.config.Network.SpecDiscovery.Address),
+		http.WithLogLevel(zerolog.WarnLevel),
+	)
+	if err != nil {
+		return err
+	}
+
+	params, err := FetchNetworkParameters(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.adopt(params)
+}
+
+// adopt makes the network the reference network and derives the spec from it
+// if spec discovery is enabled. Must be called while holding mu.
+func (b *BeaconChain) adopt(params *NetworkParameters) error {
+	if b.config.Network.SpecDiscovery.Enabled {
+		if err := b.setSpec(params.Spec()); err != nil {
+			return err
+		}
+
+		// The node's fork schedule is authoritative, but anything configured
+		// still takes precedence.
+		if len(params.Forks) > 0 {
+			b.forks = params.Forks.Merge(b.config.Network.Forks)
+		}
+
+		b.log.WithFields(logrus.Fields{
+			"config_name":      params.Name,
+			"seconds_per_slot": b.spec.SecondsPerSlot,
+			"slots_per_epoch":  b.spec.SlotsPerEpoch,
+			"genesis_time":     b.spec.GenesisTime,
+		}).Info("Derived network spec from beacon node")
+	} else if spec := params.Spec(); spec != b.spec {
+		return fmt.Errorf("%w: spec %+v does not match the configured spec %+v", ErrNetworkMismatch, spec, b.spec)
+	}
+
+	if params.Name != "" && params.Name != b.config.Network.Name {
+		b.log.
+			WithField("config_name", params.Name).
+			Warn("Beacon node's CONFIG_NAME does not match the configured network name")
+	}
+
+	b.reference = params
+
 	return nil
 }
 
+// VerifyNetwork checks that a beacon node is on the same network as every other
+// beacon node. The first beacon node to be verified defines the network, unless
+// a dedicated spec discovery node is configured.
+func (b *BeaconChain) VerifyNetwork(params *NetworkParameters) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.reference != nil {
+		return b.reference.Verify(params)
+	}
+
+	if b.config.Network.SpecDiscovery.Enabled && b.config.Network.SpecDiscovery.Address != "" {
+		return ErrNetworkNotReady
+	}
+
+	return b.adopt(params)
+}
+
+// Ready returns true once the spec is known.
+func (b *BeaconChain) Ready() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.wallclock != nil
+}
+
 func (b *BeaconChain) Wallclock() *ethwallclock.EthereumBeaconChain {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	return b.wallclock
 }
 
@@ -61,19 +211,62 @@ func (b *BeaconChain) Stop() error {
 }
 
 func (b *BeaconChain) SlotsPerEpoch() uint64 {
-	return b.config.Network.Spec.SlotsPerEpoch
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.spec.SlotsPerEpoch
 }
 
 func (b *BeaconChain) SecondsPerSlot() uint64 {
-	return b.config.Network.Spec.SecondsPerSlot
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.spec.SecondsPerSlot
 }
 
 func (b *BeaconChain) GenesisTime() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	return b.genesisTime
 }
 
 func (b *BeaconChain) Spec() *SpecConfig {
-	return &b.config.Network.Spec
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	spec := b.spec
+
+	return &spec
+}
+
+// Forks returns the fork schedule in activation order.
+func (b *BeaconChain) Forks() []ScheduledFork {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.forks.Scheduled()
+}
+
+// ForkAtEpoch returns the fork that is active at the epoch.
+func (b *BeaconChain) ForkAtEpoch(epoch phase0.Epoch) Fork {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.forks.ActiveAt(epoch)
+}
+
+// ForkAtSlot returns the fork that is active at the slot. It returns false if
+// the spec isn't known yet.
+func (b *BeaconChain) ForkAtSlot(slot phase0.Slot) (Fork, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.spec.SlotsPerEpoch == 0 {
+		return "", false
+	}
+
+	return b.forks.ActiveAt(phase0.Epoch(uint64(slot) / b.spec.SlotsPerEpoch)), true
 }
 
 func (b *BeaconChain) NetworkName() string {
@@ -1,8 +1,11 @@
 package ethereum
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethpandaops/ethwallclock"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -84,3 +87,153 @@ func TestBeaconChain_SecondsPerSlot_ReturnsSecondsPerSlot(t *testing.T) {
 	secondsPerSlot := beaconchain.SecondsPerSlot()
 	assert.Equal(t, uint64(12), secondsPerSlot)
 }
+
+func testNetworkParameters() *NetworkParameters {
+	return &NetworkParameters{
+		Name:               "testnet",
+		GenesisTime:        time.Unix(1590832934, 0),
+		GenesisRoot:        phase0.Root{0x01},
+		GenesisForkVersion: phase0.Version{0x00, 0x00, 0x00, 0x01},
+		SecondsPerSlot:     12 * time.Second,
+		SlotsPerEpoch:      32,
+	}
+}
+
+func TestBeaconChain_SpecDiscovery_DerivesSpec(t *testing.T) {
+	config := &Config{
+		Network: NetworkConfig{
+			Name:          "TestNet",
+			SpecDiscovery: SpecDiscoveryConfig{Enabled: true},
+		},
+	}
+	log := logrus.New()
+	beaconchain, err := NewBeaconChain(log, config)
+	assert.NoError(t, err)
+	assert.False(t, beaconchain.Ready())
+	assert.Nil(t, beaconchain.Wallclock())
+
+	assert.NoError(t, beaconchain.VerifyNetwork(testNetworkParameters()))
+	assert.True(t, beaconchain.Ready())
+	assert.Equal(t, uint64(12), beaconchain.SecondsPerSlot())
+	assert.Equal(t, uint64(32), beaconchain.SlotsPerEpoch())
+	assert.Equal(t, time.Unix(1590832934, 0), beaconchain.GenesisTime())
+}
+
+func TestBeaconChain_SpecDiscovery_WaitsForDedicatedNode(t *testing.T) {
+	config := &Config{
+		Network: NetworkConfig{
+			Name:          "TestNet",
+			SpecDiscovery: SpecDiscoveryConfig{Enabled: true, Address: "http://localhost:5052"},
+		},
+	}
+	log := logrus.New()
+	beaconchain, _ := NewBeaconChain(log, config)
+	err := beaconchain.VerifyNetwork(testNetworkParameters())
+	assert.True(t, errors.Is(err, ErrNetworkNotReady))
+	assert.False(t, beaconchain.Ready())
+}
+
+func TestBeaconChain_VerifyNetwork_RejectsOtherNetworks(t *testing.T) {
+	config := &Config{
+		Network: NetworkConfig{
+			Name: "TestNet",
+			Spec: SpecConfig{
+				SlotsPerEpoch:  32,
+				SecondsPerSlot: 12,
+				GenesisTime:    1590832934,
+			},
+		},
+	}
+	log := logrus.New()
+	beaconchain, _ := NewBeaconChain(log, config)
+	assert.NoError(t, beaconchain.VerifyNetwork(testNetworkParameters()))
+
+	otherRoot := testNetworkParameters()
+	otherRoot.GenesisRoot = phase0.Root{0x02}
+	assert.True(t, errors.Is(beaconchain.VerifyNetwork(otherRoot), ErrNetworkMismatch))
+
+	otherForkVersion := testNetworkParameters()
+	otherForkVersion.GenesisForkVersion = phase0.Version{0x00, 0x00, 0x00, 0x02}
+	assert.True(t, errors.Is(beaconchain.VerifyNetwork(otherForkVersion), ErrNetworkMismatch))
+
+	// Nodes that disagree with the configured spec are rejected too.
+	beaconchain, _ = NewBeaconChain(log, config)
+	otherSpec := testNetworkParameters()
+	otherSpec.SecondsPerSlot = 6 * time.Second
+	assert.True(t, errors.Is(beaconchain.VerifyNetwork(otherSpec), ErrNetworkMismatch))
+}
+
+func TestNewBeaconChain_Preset_FillsSpec(t *testing.T) {
+	config := &Config{
+		Network: NetworkConfig{
+			Name: "Sepolia",
+			Spec: SpecConfig{
+				SecondsPerSlot: 6,
+			},
+		},
+	}
+	log := logrus.New()
+	beaconchain, err := NewBeaconChain(log, config)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), beaconchain.SecondsPerSlot())
+	assert.Equal(t, uint64(32), beaconchain.SlotsPerEpoch())
+	assert.Equal(t, time.Unix(1655733600, 0), beaconchain.GenesisTime())
+}
+
+func TestBeaconChain_ForkAtSlot_UsesSchedule(t *testing.T) {
+	config := &Config{
+		Network: NetworkConfig{
+			Name: "hoodi",
+			Forks: ForkSchedule{
+				ForkFulu: 4096,
+			},
+		},
+	}
+	log := logrus.New()
+	beaconchain, err := NewBeaconChain(log, config)
+	assert.NoError(t, err)
+
+	tests := map[phase0.Slot]Fork{
+		0:             ForkDeneb,
+		2048*32 - 1:   ForkDeneb,
+		2048 * 32:     ForkElectra,
+		4096*32 - 1:   ForkElectra,
+		4096 * 32:     ForkFulu,
+		1_000_000_000: ForkFulu,
+	}
+
+	for slot, expected := range tests {
+		fork, ok := beaconchain.ForkAtSlot(slot)
+		assert.True(t, ok)
+		assert.Equal(t, expected, fork, slot)
+	}
+
+	forks := beaconchain.Forks()
+	assert.Equal(t, ScheduledFork{Name: ForkPhase0, Epoch: 0}, forks[0])
+	assert.Equal(t, ScheduledFork{Name: ForkFulu, Epoch: 4096}, forks[len(forks)-1])
+}
+
+func TestNetworkConfig_Validate_RejectsUnknownForks(t *testing.T) {
+	config := &NetworkConfig{
+		Name:  "mainnet",
+		Forks: ForkSchedule{"shanghai": 1},
+	}
+	assert.Error(t, config.Validate())
+
+	// Networks without a preset still need a spec.
+	config = &NetworkConfig{Name: "devnet"}
+	assert.Error(t, config.Validate())
+}
+
+func TestForkScheduleFromSpec(t *testing.T) {
+	schedule := forkScheduleFromSpec(map[string]any{
+		"ALTAIR_FORK_EPOCH":  uint64(10),
+		"ELECTRA_FORK_EPOCH": uint64(20),
+		"FULU_FORK_EPOCH":    uint64(farFutureEpoch),
+		"GLOAS_FORK_EPOCH":   uint64(30),
+		"SLOTS_PER_EPOCH":    uint64(32),
+	})
+
+	assert.Equal(t, ForkSchedule{ForkAltair: 10, ForkElectra: 20, ForkFulu: farFutureEpoch}, schedule)
+	assert.Equal(t, ForkElectra, schedule.ActiveAt(1_000_000))
+}
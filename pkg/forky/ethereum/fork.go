@@ -0,0 +1,145 @@
+package ethereum
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+type Fork string
+
+const (
+	ForkPhase0    Fork = "phase0"
+	ForkAltair    Fork = "altair"
+	ForkBellatrix Fork = "bellatrix"
+	ForkCapella   Fork = "capella"
+	ForkDeneb     Fork = "deneb"
+	ForkElectra   Fork = "electra"
+	ForkFulu      Fork = "fulu"
+)
+
+// AllForks are all known forks in activation order.
+var AllForks = []Fork{
+	ForkPhase0,
+	ForkAltair,
+	ForkBellatrix,
+	ForkCapella,
+	ForkDeneb,
+	ForkElectra,
+	ForkFulu,
+}
+
+// Valid returns true if the fork is known.
+func (f Fork) Valid() bool {
+	for _, fork := range AllForks {
+		if f == fork {
+			return true
+		}
+	}
+
+	return false
+}
+
+// farFutureEpoch is the epoch the spec uses for forks that aren't scheduled.
+const farFutureEpoch = phase0.Epoch(^uint64(0))
+
+// ForkSchedule is the activation epoch of each fork, keyed by fork name.
+// Forks that aren't in the schedule aren't scheduled.
+type ForkSchedule map[Fork]phase0.Epoch
+
+// ScheduledFork is a fork and the epoch it activates at.
+type ScheduledFork struct {
+	Name  Fork         `json:"name"`
+	Epoch phase0.Epoch `json:"epoch"`
+}
+
+// Merge returns a copy of the schedule with the overrides applied.
+func (s ForkSchedule) Merge(overrides ForkSchedule) ForkSchedule {
+	merged := make(ForkSchedule, len(s)+len(overrides))
+
+	for fork, epoch := range s {
+		merged[fork] = epoch
+	}
+
+	for fork, epoch := range overrides {
+		merged[fork] = epoch
+	}
+
+	return merged
+}
+
+// Scheduled returns the scheduled forks in activation order.
+func (s ForkSchedule) Scheduled() []ScheduledFork {
+	forks := make([]ScheduledFork, 0, len(s)+1)
+
+	// Phase0 is always active from genesis.
+	forks = append(forks, ScheduledFork{Name: ForkPhase0, Epoch: 0})
+
+	for fork, epoch := range s {
+		if fork == ForkPhase0 || epoch == farFutureEpoch {
+			continue
+		}
+
+		forks = append(forks, ScheduledFork{Name: fork, Epoch: epoch})
+	}
+
+	order := make(map[Fork]int, len(AllForks))
+	for i, fork := range AllForks {
+		order[fork] = i
+	}
+
+	sort.SliceStable(forks, func(i, j int) bool {
+		if forks[i].Epoch != forks[j].Epoch {
+			return forks[i].Epoch < forks[j].Epoch
+		}
+
+		// Forks activated at the same epoch are ordered by when they were
+		// introduced, so the latest one is last.
+		return order[forks[i].Name] < order[forks[j].Name]
+	})
+
+	return forks
+}
+
+// ActiveAt returns the fork that is active at the epoch.
+func (s ForkSchedule) ActiveAt(epoch phase0.Epoch) Fork {
+	active := ForkPhase0
+
+	for _, fork := range s.Scheduled() {
+		if fork.Epoch > epoch {
+			break
+		}
+
+		active = fork.Name
+	}
+
+	return active
+}
+
+// forkScheduleFromSpec parses the <FORK>_FORK_EPOCH values of a beacon node's spec.
+func forkScheduleFromSpec(spec map[string]any) ForkSchedule {
+	schedule := ForkSchedule{}
+
+	for key, value := range spec {
+		name, ok := strings.CutSuffix(key, "_FORK_EPOCH")
+		if !ok {
+			continue
+		}
+
+		epoch, ok := value.(uint64)
+		if !ok {
+			continue
+		}
+
+		// Forks we don't know about can't be ordered, so are ignored.
+		fork := Fork(strings.ToLower(name))
+		if !fork.Valid() {
+			continue
+		}
+
+		schedule[fork] = phase0.Epoch(epoch)
+	}
+
+	return schedule
+}
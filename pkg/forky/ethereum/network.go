@@ -0,0 +1,120 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	api "github.com/attestantio/go-eth2-client/api"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	// ErrNetworkNotReady is returned until the network spec has been derived
+	// from a beacon node.
+	ErrNetworkNotReady = errors.New("network spec is not known yet")
+	// ErrNetworkMismatch is returned when a beacon node is on a different
+	// network to the one forky is configured for.
+	ErrNetworkMismatch = errors.New("beacon node is on a different network")
+)
+
+// NetworkParameters identify the network a beacon node is on.
+type NetworkParameters struct {
+	// Name is the node's CONFIG_NAME, if it has one.
+	Name               string
+	GenesisTime        time.Time
+	GenesisRoot        phase0.Root
+	GenesisForkVersion phase0.Version
+	SecondsPerSlot     time.Duration
+	SlotsPerEpoch      uint64
+	// Forks is the fork schedule advertised by the node.
+	Forks ForkSchedule
+}
+
+// FetchNetworkParameters fetches the genesis and spec of the network a beacon
+// node is on.
+func FetchNetworkParameters(ctx context.Context, client eth2client.Service) (*NetworkParameters, error) {
+	genesisProvider, ok := client.(eth2client.GenesisProvider)
+	if !ok {
+		return nil, errors.New("client does not support genesis provider")
+	}
+
+	rsp, err := genesisProvider.Genesis(ctx, &api.GenesisOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch genesis: %w", err)
+	}
+
+	if rsp == nil || rsp.Data == nil {
+		return nil, errors.New("received nil response when fetching genesis")
+	}
+
+	specProvider, ok := client.(eth2client.SpecProvider)
+	if !ok {
+		return nil, errors.New("client does not support spec provider")
+	}
+
+	specRsp, err := specProvider.Spec(ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch spec: %w", err)
+	}
+
+	if specRsp == nil || specRsp.Data == nil {
+		return nil, errors.New("received nil response when fetching spec")
+	}
+
+	spec := specRsp.Data
+
+	secondsPerSlot, ok := spec["SECONDS_PER_SLOT"].(time.Duration)
+	if !ok {
+		return nil, errors.New("failed to fetch SECONDS_PER_SLOT")
+	}
+
+	slotsPerEpoch, ok := spec["SLOTS_PER_EPOCH"].(uint64)
+	if !ok {
+		return nil, errors.New("failed to fetch SLOTS_PER_EPOCH")
+	}
+
+	params := &NetworkParameters{
+		GenesisTime:        rsp.Data.GenesisTime,
+		GenesisRoot:        rsp.Data.GenesisValidatorsRoot,
+		GenesisForkVersion: rsp.Data.GenesisForkVersion,
+		SecondsPerSlot:     secondsPerSlot,
+		SlotsPerEpoch:      slotsPerEpoch,
+		Forks:              forkScheduleFromSpec(spec),
+	}
+
+	if name, ok := spec["CONFIG_NAME"].(string); ok {
+		params.Name = name
+	}
+
+	return params, nil
+}
+
+// Spec returns the parameters as a spec config.
+func (p *NetworkParameters) Spec() SpecConfig {
+	return SpecConfig{
+		SecondsPerSlot: uint64(p.SecondsPerSlot.Seconds()),
+		SlotsPerEpoch:  p.SlotsPerEpoch,
+		//nolint:gosec // genesis is never before 1970
+		GenesisTime: uint64(p.GenesisTime.Unix()),
+	}
+}
+
+// Verify checks that other describes the same network.
+func (p *NetworkParameters) Verify(other *NetworkParameters) error {
+	if p.GenesisRoot != other.GenesisRoot {
+		return fmt.Errorf("%w: genesis validators root %#x does not match %#x", ErrNetworkMismatch, other.GenesisRoot, p.GenesisRoot)
+	}
+
+	if p.GenesisForkVersion != other.GenesisForkVersion {
+		return fmt.Errorf("%w: genesis fork version %#x does not match %#x", ErrNetworkMismatch, other.GenesisForkVersion, p.GenesisForkVersion)
+	}
+
+	if expected, actual := p.Spec(), other.Spec(); expected != actual {
+		return fmt.Errorf("%w: spec %+v does not match %+v", ErrNetworkMismatch, actual, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package ethereum
+
+import "strings"
+
+// Preset is the spec and fork schedule of a well known network.
+type Preset struct {
+	Spec  SpecConfig
+	Forks ForkSchedule
+}
+
+// Presets are the built-in networks, keyed by network name.
+var Presets = map[string]Preset{
+	"mainnet": {
+		Spec: SpecConfig{
+			SecondsPerSlot: 12,
+			SlotsPerEpoch:  32,
+			GenesisTime:    1606824023,
+		},
+		Forks: ForkSchedule{
+			ForkAltair:    74240,
+			ForkBellatrix: 144896,
+			ForkCapella:   194048,
+			ForkDeneb:     269568,
+			ForkElectra:   364032,
+			ForkFulu:      411392,
+		},
+	},
+	"sepolia": {
+		Spec: SpecConfig{
+			SecondsPerSlot: 12,
+			SlotsPerEpoch:  32,
+			GenesisTime:    1655733600,
+		},
+		Forks: ForkSchedule{
+			ForkAltair:    50,
+			ForkBellatrix: 100,
+			ForkCapella:   56832,
+			ForkDeneb:     132608,
+			ForkElectra:   222464,
+			ForkFulu:      272640,
+		},
+	},
+	"holesky": {
+		Spec: SpecConfig{
+			SecondsPerSlot: 12,
+			SlotsPerEpoch:  32,
+			GenesisTime:    1695902400,
+		},
+		Forks: ForkSchedule{
+			ForkAltair:    0,
+			ForkBellatrix: 0,
+			ForkCapella:   256,
+			ForkDeneb:     29696,
+			ForkElectra:   115968,
+			ForkFulu:      165120,
+		},
+	},
+	"hoodi": {
+		Spec: SpecConfig{
+			SecondsPerSlot: 12,
+			SlotsPerEpoch:  32,
+			GenesisTime:    1742213400,
+		},
+		Forks: ForkSchedule{
+			ForkAltair:    0,
+			ForkBellatrix: 0,
+			ForkCapella:   0,
+			ForkDeneb:     0,
+			ForkElectra:   2048,
+			ForkFulu:      50688,
+		},
+	},
+}
+
+// PresetFor returns the preset for the network, if there is one.
+func PresetFor(network string) (Preset, bool) {
+	preset, ok := Presets[strings.ToLower(network)]
+
+	return preset, ok
+}
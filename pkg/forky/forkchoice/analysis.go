@@ -0,0 +1,311 @@
+// Package forkchoice evaluates fork choice dumps independently of the node
+// that produced them.
+package forkchoice
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+// IssueType identifies a kind of inconsistency in a fork choice dump.
+type IssueType string
+
+const (
+	// IssueHeadMismatch means the head computed from the dump isn't the head
+	// the node reported.
+	IssueHeadMismatch IssueType = "head_mismatch"
+	// IssueJustifiedRootMissing means the justified checkpoint's block isn't
+	// in the dump.
+	IssueJustifiedRootMissing IssueType = "justified_root_missing"
+	// IssueFinalizedRootMissing means the finalized checkpoint's block isn't
+	// in the dump.
+	IssueFinalizedRootMissing IssueType = "finalized_root_missing"
+	// IssueDuplicateBlockRoot means a block root appears more than once.
+	IssueDuplicateBlockRoot IssueType = "duplicate_block_root"
+	// IssueWeightBelowChildren means a node weighs less than its children
+	// combined, which can't happen as a node's weight includes its descendants.
+	IssueWeightBelowChildren IssueType = "weight_below_children"
+	// IssueChildNotAfterParent means a node isn't at a later slot than its parent.
+	IssueChildNotAfterParent IssueType = "child_not_after_parent"
+)
+
+// HeadSource is where a reported head came from.
+type HeadSource string
+
+const (
+	// HeadSourceSnapshot means the node reported its head in the dump's
+	// extra_data, so it's from the same snapshot as the dump.
+	HeadSourceSnapshot HeadSource = "snapshot"
+	// HeadSourceNodeContext means the head was fetched separately from the
+	// dump, so the node may have moved on in between.
+	HeadSourceNodeContext HeadSource = "node_context"
+)
+
+// Issue is an inconsistency found in a fork choice dump.
+type Issue struct {
+	Type      IssueType    `json:"type"`
+	Message   string       `json:"message"`
+	BlockRoot *phase0.Root `json:"block_root,omitempty"`
+}
+
+// Head is a block chosen as head.
+type Head struct {
+	BlockRoot phase0.Root `json:"block_root"`
+	Slot      phase0.Slot `json:"slot"`
+	Weight    uint64      `json:"weight"`
+}
+
+// Analysis is the result of evaluating a frame's fork choice dump.
+type Analysis struct {
+	FrameID string `json:"frame_id"`
+	// SlotsPerEpoch is the epoch length the analysis was computed with. It's
+	// zero if it wasn't known, in which case unrealized justification and
+	// proposer boost were ignored.
+	SlotsPerEpoch uint64 `json:"slots_per_epoch"`
+	// ComputedHead is the head found by running LMD-GHOST over the dump. It's
+	// nil if the head couldn't be computed.
+	ComputedHead *Head `json:"computed_head,omitempty"`
+	// ReportedHead is the head the node reported when the frame was fetched,
+	// if known.
+	ReportedHead       *phase0.Root `json:"reported_head,omitempty"`
+	ReportedHeadSource HeadSource   `json:"reported_head_source,omitempty"`
+	// HeadMatches is nil if either head isn't known.
+	HeadMatches *bool   `json:"head_matches,omitempty"`
+	Issues      []Issue `json:"issues"`
+	// Warnings are differences that may not be inconsistencies, e.g. a head
+	// mismatch against a head that wasn't fetched with the dump.
+	Warnings []Issue `json:"warnings"`
+}
+
+// Consistent returns true if no issues were found.
+func (a *Analysis) Consistent() bool {
+	return len(a.Issues) == 0
+}
+
+// Analyze independently recomputes the head of a frame's fork choice dump and
+// compares it with the head the node reported. slotsPerEpoch is the network's
+// epoch length, or zero if it isn't known.
+//
+// A mismatch is only an issue if the node reported its head in the dump itself
+// and the head doesn't depend on the estimated proposer boost. Otherwise it's
+// a warning.
+func Analyze(frame *types.Frame, slotsPerEpoch uint64) (*Analysis, error) {
+	if frame == nil || frame.Data == nil {
+		return nil, ErrNoForkChoice
+	}
+
+	tree, err := NewTree(frame.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis := &Analysis{
+		FrameID:       frame.Metadata.ID,
+		SlotsPerEpoch: slotsPerEpoch,
+		Issues:        []Issue{},
+		Warnings:      []Issue{},
+	}
+
+	if reported, source := reportedHeadRoot(frame); reported != nil {
+		analysis.ReportedHead = reported
+		analysis.ReportedHeadSource = source
+	}
+
+	analysis.Issues = append(analysis.Issues, structuralIssues(tree)...)
+
+	opts := headOptions(frame, slotsPerEpoch)
+
+	head, err := tree.Head(opts)
+	if err != nil {
+		root := frame.Data.JustifiedCheckpoint.Root
+
+		analysis.Issues = append(analysis.Issues, Issue{
+			Type:      IssueJustifiedRootMissing,
+			Message:   err.Error(),
+			BlockRoot: &root,
+		})
+
+		return analysis, nil
+	}
+
+	analysis.ComputedHead = &Head{
+		BlockRoot: head.BlockRoot,
+		Slot:      head.Slot,
+		Weight:    head.Weight,
+	}
+
+	if analysis.ReportedHead == nil {
+		return analysis, nil
+	}
+
+	matches := *analysis.ReportedHead == head.BlockRoot
+	analysis.HeadMatches = &matches
+
+	if matches {
+		return analysis, nil
+	}
+
+	reported := *analysis.ReportedHead
+
+	issue := Issue{
+		Type:      IssueHeadMismatch,
+		Message:   fmt.Sprintf("node reported head %s but LMD-GHOST selects %s", reported, head.BlockRoot),
+		BlockRoot: &reported,
+	}
+
+	unboosted := opts
+	unboosted.ProposerBoostRoot = nil
+
+	switch {
+	case analysis.ReportedHeadSource != HeadSourceSnapshot:
+		issue.Message += ", though the reported head wasn't fetched with the fork choice"
+
+		analysis.Warnings = append(analysis.Warnings, issue)
+	case dependsOnBoost(tree, head, unboosted):
+		issue.Message += ", though the selection depends on the estimated proposer boost"
+
+		analysis.Warnings = append(analysis.Warnings, issue)
+	default:
+		analysis.Issues = append(analysis.Issues, issue)
+	}
+
+	return analysis, nil
+}
+
+// dependsOnBoost returns true if the head is different without proposer boost.
+func dependsOnBoost(tree *Tree, head *v1.ForkChoiceNode, unboosted HeadOptions) bool {
+	without, err := tree.Head(unboosted)
+
+	return err == nil && without.BlockRoot != head.BlockRoot
+}
+
+// snapshotExtraData is the part of a dump's top level extra_data used to
+// evaluate its head. Not every client provides it.
+type snapshotExtraData struct {
+	HeadRoot          string `json:"head_root"`
+	ProposerBoostRoot string `json:"proposer_boost_root"`
+}
+
+func parseSnapshotExtraData(frame *types.Frame) snapshotExtraData {
+	extra := snapshotExtraData{}
+
+	if len(frame.ExtraData) > 0 {
+		// Clients that don't provide these fields are treated as if they
+		// provided nothing.
+		_ = json.Unmarshal(frame.ExtraData, &extra)
+	}
+
+	return extra
+}
+
+// parseRoot parses a hex encoded root. Zero roots, which clients use for "none",
+// are returned as nil.
+func parseRoot(value string) *phase0.Root {
+	decoded, err := hex.DecodeString(strings.TrimPrefix(value, "0x"))
+	if err != nil || len(decoded) != len(phase0.Root{}) {
+		return nil
+	}
+
+	var root phase0.Root
+
+	copy(root[:], decoded)
+
+	if root == (phase0.Root{}) {
+		return nil
+	}
+
+	return &root
+}
+
+// reportedHeadRoot returns the head the node reported, preferring the head in
+// the dump's extra_data as it's from the same snapshot.
+func reportedHeadRoot(frame *types.Frame) (*phase0.Root, HeadSource) {
+	if root := parseRoot(parseSnapshotExtraData(frame).HeadRoot); root != nil {
+		return root, HeadSourceSnapshot
+	}
+
+	if frame.Metadata.NodeContext != nil && frame.Metadata.NodeContext.HeadRoot != nil {
+		root := *frame.Metadata.NodeContext.HeadRoot
+
+		return &root, HeadSourceNodeContext
+	}
+
+	return nil, ""
+}
+
+// headOptions returns the options for computing the head of the frame.
+func headOptions(frame *types.Frame, slotsPerEpoch uint64) HeadOptions {
+	return HeadOptions{
+		CurrentEpoch:      frame.Metadata.WallClockEpoch,
+		SlotsPerEpoch:     slotsPerEpoch,
+		ProposerBoostRoot: parseRoot(parseSnapshotExtraData(frame).ProposerBoostRoot),
+	}
+}
+
+// structuralIssues finds inconsistencies in the shape of the dump itself.
+func structuralIssues(tree *Tree) []Issue {
+	issues := []Issue{}
+
+	for _, root := range tree.duplicates {
+		root := root
+
+		issues = append(issues, Issue{
+			Type:      IssueDuplicateBlockRoot,
+			Message:   fmt.Sprintf("block %s appears more than once", root),
+			BlockRoot: &root,
+		})
+	}
+
+	if finalized := tree.forkChoice.FinalizedCheckpoint.Root; tree.Node(finalized) == nil {
+		issues = append(issues, Issue{
+			Type:      IssueFinalizedRootMissing,
+			Message:   fmt.Sprintf("finalized checkpoint block %s not found in fork choice", finalized),
+			BlockRoot: &finalized,
+		})
+	}
+
+	for _, node := range tree.forkChoice.ForkChoiceNodes {
+		if node == nil || tree.Node(node.BlockRoot) != node {
+			continue
+		}
+
+		issues = append(issues, nodeIssues(tree, node)...)
+	}
+
+	return issues
+}
+
+func nodeIssues(tree *Tree, node *v1.ForkChoiceNode) []Issue {
+	issues := []Issue{}
+	root := node.BlockRoot
+
+	if parent := tree.Node(node.ParentRoot); parent != nil && node.Slot <= parent.Slot {
+		issues = append(issues, Issue{
+			Type:      IssueChildNotAfterParent,
+			Message:   fmt.Sprintf("block %s at slot %d is not after its parent at slot %d", root, node.Slot, parent.Slot),
+			BlockRoot: &root,
+		})
+	}
+
+	childrenWeight := uint64(0)
+
+	for _, child := range tree.Children(root) {
+		childrenWeight += child.Weight
+	}
+
+	if childrenWeight > node.Weight {
+		issues = append(issues, Issue{
+			Type:      IssueWeightBelowChildren,
+			Message:   fmt.Sprintf("block %s has weight %d but its children have %d", root, node.Weight, childrenWeight),
+			BlockRoot: &root,
+		})
+	}
+
+	return issues
+}
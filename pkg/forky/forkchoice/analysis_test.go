@@ -0,0 +1,253 @@
+package forkchoice
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func root(b byte) phase0.Root {
+	return phase0.Root{b}
+}
+
+func node(slot phase0.Slot, blockRoot, parentRoot byte, weight uint64) *v1.ForkChoiceNode {
+	return &v1.ForkChoiceNode{
+		Slot:       slot,
+		BlockRoot:  root(blockRoot),
+		ParentRoot: root(parentRoot),
+		Weight:     weight,
+		Validity:   v1.ForkChoiceNodeValidityValid,
+	}
+}
+
+func frame(head *phase0.Root, nodes ...*v1.ForkChoiceNode) *types.Frame {
+	return &types.Frame{
+		Metadata: types.FrameMetadata{
+			ID: "test",
+			NodeContext: &types.NodeContext{
+				HeadRoot: head,
+			},
+		},
+		Data: &v1.ForkChoice{
+			JustifiedCheckpoint: phase0.Checkpoint{Root: root(1)},
+			FinalizedCheckpoint: phase0.Checkpoint{Root: root(1)},
+			ForkChoiceNodes:     nodes,
+		},
+	}
+}
+
+func issueTypes(analysis *Analysis) []IssueType {
+	types := []IssueType{}
+
+	for _, issue := range analysis.Issues {
+		types = append(types, issue.Type)
+	}
+
+	return types
+}
+
+func TestAnalyzeHeavierBranch(t *testing.T) {
+	reported := root(4)
+
+	analysis, err := Analyze(frame(&reported,
+		node(1, 1, 0, 30),
+		node(2, 2, 1, 10),
+		node(3, 3, 1, 20),
+		node(4, 4, 3, 20),
+	), 32)
+	require.NoError(t, err)
+
+	require.NotNil(t, analysis.ComputedHead)
+	assert.Equal(t, root(4), analysis.ComputedHead.BlockRoot)
+	require.NotNil(t, analysis.HeadMatches)
+	assert.True(t, *analysis.HeadMatches)
+	assert.True(t, analysis.Consistent())
+}
+
+func TestAnalyzeTieBreak(t *testing.T) {
+	analysis, err := Analyze(frame(nil,
+		node(1, 1, 0, 20),
+		node(2, 2, 1, 10),
+		node(2, 3, 1, 10),
+	), 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, root(3), analysis.ComputedHead.BlockRoot)
+	assert.Nil(t, analysis.HeadMatches)
+}
+
+func TestAnalyzeSkipsInvalidBranches(t *testing.T) {
+	invalid := node(2, 2, 1, 20)
+	invalid.Validity = v1.ForkChoiceNodeValidityInvalid
+
+	analysis, err := Analyze(frame(nil,
+		node(1, 1, 0, 30),
+		invalid,
+		node(2, 3, 1, 10),
+	), 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, root(3), analysis.ComputedHead.BlockRoot)
+}
+
+func TestAnalyzeSkipsUnviableBranches(t *testing.T) {
+	f := frame(nil,
+		node(64, 1, 0, 30),
+		node(65, 2, 1, 20),
+		node(65, 3, 1, 10),
+	)
+	f.Data.JustifiedCheckpoint.Epoch = 2
+	f.Metadata.WallClockEpoch = 10
+
+	f.Data.ForkChoiceNodes[1].JustifiedEpoch = 1
+	f.Data.ForkChoiceNodes[2].JustifiedEpoch = 2
+
+	analysis, err := Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, root(3), analysis.ComputedHead.BlockRoot)
+
+	// Leaves justified in the last two epochs stay viable.
+	f.Metadata.WallClockEpoch = 3
+
+	analysis, err = Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, root(2), analysis.ComputedHead.BlockRoot)
+
+	// Blocks from previous epochs vote with their unrealized justification.
+	f.Metadata.WallClockEpoch = 10
+	f.Data.ForkChoiceNodes[1].ExtraData = map[string]any{"unrealized_justified_epoch": "2"}
+
+	analysis, err = Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, root(2), analysis.ComputedHead.BlockRoot)
+}
+
+func TestAnalyzeChecksFinalizationByAncestry(t *testing.T) {
+	f := frame(nil,
+		node(64, 1, 0, 30),
+		node(65, 2, 1, 20),
+		node(65, 3, 1, 10),
+	)
+	f.Data.FinalizedCheckpoint.Epoch = 2
+	f.Metadata.WallClockEpoch = 3
+
+	// The leaf's own finalized epoch is behind the store's, but it descends
+	// from the finalized block.
+	f.Data.ForkChoiceNodes[1].FinalizedEpoch = 1
+	f.Data.ForkChoiceNodes[2].FinalizedEpoch = 2
+
+	analysis, err := Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, root(2), analysis.ComputedHead.BlockRoot)
+}
+
+func TestAnalyzeHeadMismatch(t *testing.T) {
+	reported := root(2)
+
+	f := frame(&reported,
+		node(1, 1, 0, 30),
+		node(2, 2, 1, 10),
+		node(2, 3, 1, 20),
+	)
+
+	// The node's head was fetched separately from its fork choice, so it may
+	// have moved on in between.
+	analysis, err := Analyze(f, 32)
+	require.NoError(t, err)
+
+	require.NotNil(t, analysis.HeadMatches)
+	assert.False(t, *analysis.HeadMatches)
+	assert.Equal(t, HeadSourceNodeContext, analysis.ReportedHeadSource)
+	assert.Equal(t, uint64(32), analysis.SlotsPerEpoch)
+	assert.True(t, analysis.Consistent())
+	require.Len(t, analysis.Warnings, 1)
+	assert.Equal(t, IssueHeadMismatch, analysis.Warnings[0].Type)
+
+	// A head reported in the same snapshot is preferred.
+	f.ExtraData = []byte(fmt.Sprintf(`{"head_root":%q}`, reported.String()))
+	f.Metadata.NodeContext.HeadRoot = nil
+
+	analysis, err = Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, HeadSourceSnapshot, analysis.ReportedHeadSource)
+	assert.Equal(t, []IssueType{IssueHeadMismatch}, issueTypes(analysis))
+	assert.Empty(t, analysis.Warnings)
+}
+
+func TestAnalyzeProposerBoost(t *testing.T) {
+	boosted := root(2)
+
+	// With 32 slots per epoch, the boost is 40% of 3200/32.
+	f := frame(nil,
+		node(1, 1, 0, 3200),
+		node(2, 2, 1, 100),
+		node(2, 3, 1, 110),
+	)
+	f.ExtraData = []byte(fmt.Sprintf(`{"head_root":%q,"proposer_boost_root":%q}`, boosted.String(), boosted.String()))
+
+	analysis, err := Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.Equal(t, boosted, analysis.ComputedHead.BlockRoot)
+	assert.True(t, *analysis.HeadMatches)
+
+	// A mismatch that depends on the estimated boost is only a warning.
+	other := root(3)
+	f.ExtraData = []byte(fmt.Sprintf(`{"head_root":%q,"proposer_boost_root":%q}`, other.String(), boosted.String()))
+
+	analysis, err = Analyze(f, 32)
+	require.NoError(t, err)
+
+	assert.False(t, *analysis.HeadMatches)
+	assert.True(t, analysis.Consistent())
+	require.Len(t, analysis.Warnings, 1)
+
+	// Without the slots per epoch the boost can't be estimated.
+	analysis, err = Analyze(f, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, other, analysis.ComputedHead.BlockRoot)
+}
+
+func TestAnalyzeStructuralIssues(t *testing.T) {
+	analysis, err := Analyze(frame(nil,
+		node(2, 1, 0, 10),
+		node(2, 2, 1, 20),
+		node(3, 2, 1, 20),
+	), 32)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []IssueType{
+		IssueDuplicateBlockRoot,
+		IssueChildNotAfterParent,
+		IssueWeightBelowChildren,
+	}, issueTypes(analysis))
+}
+
+func TestAnalyzeMissingJustifiedRoot(t *testing.T) {
+	analysis, err := Analyze(frame(nil,
+		node(2, 2, 1, 10),
+	), 32)
+	require.NoError(t, err)
+
+	assert.Nil(t, analysis.ComputedHead)
+	assert.ElementsMatch(t, []IssueType{
+		IssueFinalizedRootMissing,
+		IssueJustifiedRootMissing,
+	}, issueTypes(analysis))
+}
+
+func TestAnalyzeNoForkChoice(t *testing.T) {
+	_, err := Analyze(&types.Frame{}, 32)
+	assert.ErrorIs(t, err, ErrNoForkChoice)
+}
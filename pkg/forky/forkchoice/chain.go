@@ -0,0 +1,274 @@
+package forkchoice
+
+import (
+	"bytes"
+	"sort"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+// CanonicalBlock is the block a majority of nodes have in their canonical
+// chain at a slot.
+type CanonicalBlock struct {
+	Slot      phase0.Slot `json:"slot"`
+	BlockRoot phase0.Root `json:"block_root"`
+	// Nodes are the nodes that have the block in their canonical chain.
+	Nodes []string `json:"nodes"`
+}
+
+// OrphanedBlock is a block that was seen in a fork choice dump but isn't in
+// the canonical chain.
+type OrphanedBlock struct {
+	Slot       phase0.Slot `json:"slot"`
+	BlockRoot  phase0.Root `json:"block_root"`
+	ParentRoot phase0.Root `json:"parent_root"`
+	// SeenBy are the nodes with the block in at least one of their frames.
+	SeenBy []string `json:"seen_by"`
+	// MaxWeight is the highest weight any node gave the block.
+	MaxWeight uint64 `json:"max_weight"`
+}
+
+// ChainReport is the canonical chain over a slot range, as agreed by the
+// latest frame of each node.
+type ChainReport struct {
+	FromSlot phase0.Slot `json:"from_slot"`
+	ToSlot   phase0.Slot `json:"to_slot"`
+	// Frames are the IDs of the latest frame of each node, which the
+	// canonical chain is derived from.
+	Frames      []string         `json:"frames"`
+	Canonical   []CanonicalBlock `json:"canonical"`
+	MissedSlots []phase0.Slot    `json:"missed_slots"`
+	Orphaned    []OrphanedBlock  `json:"orphaned"`
+}
+
+// NewChainReport walks back from the head of the latest frame of each node to
+// find the canonical block at every slot in the range. Nodes vote on each slot
+// their chain covers, either for a block or for the slot being missed, and the
+// majority wins. Blocks in any of the frames that lose are reported as
+// orphaned. Slots that no node's chain covers are left out of the report.
+// slotsPerEpoch is the network's epoch length, or zero if it isn't known, and
+// is used to compute the head of nodes that didn't report one.
+func NewChainReport(frames []*types.Frame, fromSlot, toSlot phase0.Slot, slotsPerEpoch uint64) *ChainReport {
+	report := &ChainReport{
+		FromSlot:    fromSlot,
+		ToSlot:      toSlot,
+		Frames:      []string{},
+		Canonical:   []CanonicalBlock{},
+		MissedSlots: []phase0.Slot{},
+		Orphaned:    []OrphanedBlock{},
+	}
+
+	trees := make(map[*types.Frame]*Tree, len(frames))
+
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+
+		tree, err := NewTree(frame.Data)
+		if err != nil {
+			continue
+		}
+
+		trees[frame] = tree
+	}
+
+	// A missed slot is voted for with the zero root.
+	votes := make(map[phase0.Slot]map[phase0.Root][]string)
+
+	for _, frame := range latestFrames(trees) {
+		report.Frames = append(report.Frames, frame.Metadata.ID)
+
+		tree := trees[frame]
+
+		head := reportedHead(frame, tree, slotsPerEpoch)
+		if head == nil {
+			continue
+		}
+
+		// The chain covers the slots from its oldest known block to its head.
+		chain := make(map[phase0.Slot]phase0.Root)
+		oldest := head.Slot
+
+		for node := head; ; {
+			chain[node.Slot] = node.BlockRoot
+			oldest = node.Slot
+
+			// Parents must be at earlier slots, which also stops malformed
+			// dumps with cycles from being walked forever.
+			parent := tree.Node(node.ParentRoot)
+			if parent == nil || parent.Slot >= node.Slot {
+				break
+			}
+
+			node = parent
+		}
+
+		for slot := max(oldest, fromSlot); slot <= min(head.Slot, toSlot); slot++ {
+			if votes[slot] == nil {
+				votes[slot] = make(map[phase0.Root][]string)
+			}
+
+			root := chain[slot]
+			votes[slot][root] = append(votes[slot][root], frame.Metadata.Node)
+		}
+	}
+
+	canonical := make(map[phase0.Root]bool)
+
+	for _, slot := range sortedSlots(votes) {
+		root, nodes := majority(votes[slot])
+
+		if root == (phase0.Root{}) {
+			report.MissedSlots = append(report.MissedSlots, slot)
+
+			continue
+		}
+
+		canonical[root] = true
+
+		report.Canonical = append(report.Canonical, CanonicalBlock{
+			Slot:      slot,
+			BlockRoot: root,
+			Nodes:     nodes,
+		})
+	}
+
+	report.Orphaned = orphanedBlocks(trees, votes, canonical)
+
+	return report
+}
+
+// latestFrames returns the frame with the latest wall clock slot for each node,
+// sorted by node.
+func latestFrames(trees map[*types.Frame]*Tree) []*types.Frame {
+	latest := make(map[string]*types.Frame)
+
+	for frame := range trees {
+		current, exists := latest[frame.Metadata.Node]
+		if !exists ||
+			frame.Metadata.WallClockSlot > current.Metadata.WallClockSlot ||
+			(frame.Metadata.WallClockSlot == current.Metadata.WallClockSlot && frame.Metadata.FetchedAt.After(current.Metadata.FetchedAt)) {
+			latest[frame.Metadata.Node] = frame
+		}
+	}
+
+	frames := make([]*types.Frame, 0, len(latest))
+	for _, frame := range latest {
+		frames = append(frames, frame)
+	}
+
+	sort.Slice(frames, func(i, j int) bool {
+		return frames[i].Metadata.Node < frames[j].Metadata.Node
+	})
+
+	return frames
+}
+
+// reportedHead returns the head the node reported, falling back to the head
+// computed with LMD-GHOST if the node didn't report one that's in the dump.
+func reportedHead(frame *types.Frame, tree *Tree, slotsPerEpoch uint64) *v1.ForkChoiceNode {
+	if root, _ := reportedHeadRoot(frame); root != nil {
+		if head := tree.Node(*root); head != nil {
+			return head
+		}
+	}
+
+	head, err := tree.Head(headOptions(frame, slotsPerEpoch))
+	if err != nil {
+		return nil
+	}
+
+	return head
+}
+
+// majority returns the root with the most votes. Ties go to a block over a
+// missed slot, then to the highest root.
+func majority(votes map[phase0.Root][]string) (phase0.Root, []string) {
+	var (
+		best  phase0.Root
+		nodes []string
+	)
+
+	for root, voters := range votes {
+		switch {
+		case nodes == nil,
+			len(voters) > len(nodes),
+			len(voters) == len(nodes) && bytes.Compare(root[:], best[:]) > 0:
+			best = root
+			nodes = voters
+		}
+	}
+
+	sorted := append([]string{}, nodes...)
+	sort.Strings(sorted)
+
+	return best, sorted
+}
+
+func orphanedBlocks(trees map[*types.Frame]*Tree, votes map[phase0.Slot]map[phase0.Root][]string, canonical map[phase0.Root]bool) []OrphanedBlock {
+	orphaned := make(map[phase0.Root]*OrphanedBlock)
+	seenBy := make(map[phase0.Root]map[string]bool)
+
+	for frame, tree := range trees {
+		for _, node := range tree.nodes {
+			// Only blocks at slots the canonical chain covers can be orphaned.
+			if votes[node.Slot] == nil || canonical[node.BlockRoot] {
+				continue
+			}
+
+			block, exists := orphaned[node.BlockRoot]
+			if !exists {
+				block = &OrphanedBlock{
+					Slot:       node.Slot,
+					BlockRoot:  node.BlockRoot,
+					ParentRoot: node.ParentRoot,
+				}
+
+				orphaned[node.BlockRoot] = block
+				seenBy[node.BlockRoot] = make(map[string]bool)
+			}
+
+			block.MaxWeight = max(block.MaxWeight, node.Weight)
+			seenBy[node.BlockRoot][frame.Metadata.Node] = true
+		}
+	}
+
+	blocks := make([]OrphanedBlock, 0, len(orphaned))
+
+	for root, block := range orphaned {
+		block.SeenBy = make([]string, 0, len(seenBy[root]))
+		for node := range seenBy[root] {
+			block.SeenBy = append(block.SeenBy, node)
+		}
+
+		sort.Strings(block.SeenBy)
+
+		blocks = append(blocks, *block)
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].Slot != blocks[j].Slot {
+			return blocks[i].Slot < blocks[j].Slot
+		}
+
+		return bytes.Compare(blocks[i].BlockRoot[:], blocks[j].BlockRoot[:]) < 0
+	})
+
+	return blocks
+}
+
+func sortedSlots[V any](m map[phase0.Slot]V) []phase0.Slot {
+	slots := make([]phase0.Slot, 0, len(m))
+	for slot := range m {
+		slots = append(slots, slot)
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i] < slots[j]
+	})
+
+	return slots
+}
@@ -0,0 +1,78 @@
+package forkchoice
+
+import (
+	"testing"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func nodeFrame(id, name string, wallClockSlot phase0.Slot, head byte, nodes ...*v1.ForkChoiceNode) *types.Frame {
+	headRoot := root(head)
+
+	f := frame(&headRoot, nodes...)
+	f.Metadata.ID = id
+	f.Metadata.Node = name
+	f.Metadata.WallClockSlot = wallClockSlot
+
+	return f
+}
+
+func TestChainReport(t *testing.T) {
+	// 1 <- 2 <- 4 <- 5 is canonical and 3 forks off 2.
+	tree := func() []*v1.ForkChoiceNode {
+		return []*v1.ForkChoiceNode{
+			node(1, 1, 0, 50),
+			node(2, 2, 1, 50),
+			node(3, 3, 2, 5),
+			node(4, 4, 2, 40),
+			node(5, 5, 4, 40),
+		}
+	}
+
+	frames := []*types.Frame{
+		// An older frame from node-a is ignored for the canonical chain, but
+		// still counts towards who saw orphaned blocks.
+		nodeFrame("a-old", "node-a", 3, 3, node(1, 1, 0, 50), node(2, 2, 1, 50), node(3, 3, 2, 30)),
+		nodeFrame("a", "node-a", 7, 5, tree()...),
+		nodeFrame("b", "node-b", 7, 5, tree()...),
+		// node-c is stuck on the fork.
+		nodeFrame("c", "node-c", 7, 3, tree()...),
+	}
+
+	report := NewChainReport(frames, 2, 7, 0)
+
+	assert.Equal(t, []string{"a", "b", "c"}, report.Frames)
+
+	canonical := map[phase0.Slot]phase0.Root{}
+	for _, block := range report.Canonical {
+		canonical[block.Slot] = block.BlockRoot
+	}
+
+	assert.Equal(t, map[phase0.Slot]phase0.Root{
+		2: root(2),
+		4: root(4),
+		5: root(5),
+	}, canonical)
+	assert.Equal(t, []string{"node-a", "node-b"}, report.Canonical[1].Nodes)
+
+	// Slot 3 is only in node-c's chain, so the majority have it missed.
+	assert.Equal(t, []phase0.Slot{3}, report.MissedSlots)
+
+	assert.Len(t, report.Orphaned, 1)
+	assert.Equal(t, root(3), report.Orphaned[0].BlockRoot)
+	assert.Equal(t, uint64(30), report.Orphaned[0].MaxWeight)
+	assert.Equal(t, []string{"node-a", "node-b", "node-c"}, report.Orphaned[0].SeenBy)
+}
+
+func TestChainReportOutsideRange(t *testing.T) {
+	report := NewChainReport([]*types.Frame{
+		nodeFrame("a", "node-a", 2, 2, node(1, 1, 0, 10), node(2, 2, 1, 10)),
+	}, 5, 10, 0)
+
+	assert.Empty(t, report.Canonical)
+	assert.Empty(t, report.MissedSlots)
+	assert.Empty(t, report.Orphaned)
+}
@@ -0,0 +1,286 @@
+package forkchoice
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+var (
+	// ErrNoForkChoice is returned when a frame has no fork choice data.
+	ErrNoForkChoice = errors.New("frame has no fork choice data")
+	// ErrJustifiedRootNotFound is returned when the justified checkpoint's
+	// block isn't in the fork choice dump, so the head can't be computed.
+	ErrJustifiedRootNotFound = errors.New("justified checkpoint block not found in fork choice")
+)
+
+// Tree is a fork choice dump indexed by block root.
+type Tree struct {
+	forkChoice *v1.ForkChoice
+
+	nodes    map[phase0.Root]*v1.ForkChoiceNode
+	children map[phase0.Root][]*v1.ForkChoiceNode
+
+	// duplicates are block roots that appear more than once in the dump.
+	duplicates []phase0.Root
+}
+
+// NewTree indexes a fork choice dump. Later nodes with the same block root as
+// an earlier node are ignored.
+func NewTree(forkChoice *v1.ForkChoice) (*Tree, error) {
+	if forkChoice == nil {
+		return nil, ErrNoForkChoice
+	}
+
+	t := &Tree{
+		forkChoice: forkChoice,
+		nodes:      make(map[phase0.Root]*v1.ForkChoiceNode, len(forkChoice.ForkChoiceNodes)),
+		children:   make(map[phase0.Root][]*v1.ForkChoiceNode, len(forkChoice.ForkChoiceNodes)),
+	}
+
+	for _, node := range forkChoice.ForkChoiceNodes {
+		if node == nil {
+			continue
+		}
+
+		if _, exists := t.nodes[node.BlockRoot]; exists {
+			t.duplicates = append(t.duplicates, node.BlockRoot)
+
+			continue
+		}
+
+		t.nodes[node.BlockRoot] = node
+		t.children[node.ParentRoot] = append(t.children[node.ParentRoot], node)
+	}
+
+	return t, nil
+}
+
+// Node returns the node with the block root, or nil if it isn't in the dump.
+func (t *Tree) Node(root phase0.Root) *v1.ForkChoiceNode {
+	return t.nodes[root]
+}
+
+// Children returns the children of the block root.
+func (t *Tree) Children(root phase0.Root) []*v1.ForkChoiceNode {
+	return t.children[root]
+}
+
+// proposerScoreBoost is the percentage of a committee's weight given to a
+// timely block, as in the spec's PROPOSER_SCORE_BOOST.
+const proposerScoreBoost = 40
+
+// HeadOptions is the part of a node's store that isn't in the fork choice
+// dump itself.
+type HeadOptions struct {
+	// CurrentEpoch is the wall clock epoch the dump was taken in.
+	CurrentEpoch phase0.Epoch
+	// SlotsPerEpoch is the network's epoch length. If it's zero, unrealized
+	// justification and proposer boost are ignored.
+	SlotsPerEpoch uint64
+	// ProposerBoostRoot is the block that had proposer boost, if any.
+	ProposerBoostRoot *phase0.Root
+}
+
+// Head runs LMD-GHOST from the justified checkpoint: starting at the justified
+// block it repeatedly walks to the heaviest child that leads to a viable head,
+// breaking ties in favour of the lexicographically highest block root.
+//
+// Dumps don't include the total active balance, so proposer boost is estimated
+// from the justified block's weight.
+func (t *Tree) Head(opts HeadOptions) (*v1.ForkChoiceNode, error) {
+	head := t.nodes[t.forkChoice.JustifiedCheckpoint.Root]
+	if head == nil {
+		return nil, ErrJustifiedRootNotFound
+	}
+
+	viable := t.viableBranches(head, opts)
+	boosted := t.boostedBranch(opts.ProposerBoostRoot)
+	boost := uint64(0)
+
+	if opts.SlotsPerEpoch > 0 {
+		boost = head.Weight / opts.SlotsPerEpoch * proposerScoreBoost / 100
+	}
+
+	weight := func(node *v1.ForkChoiceNode) uint64 {
+		if boosted[node.BlockRoot] {
+			return node.Weight + boost
+		}
+
+		return node.Weight
+	}
+
+	for {
+		var best *v1.ForkChoiceNode
+
+		for _, child := range t.children[head.BlockRoot] {
+			if !viable[child.BlockRoot] {
+				continue
+			}
+
+			if best == nil ||
+				weight(child) > weight(best) ||
+				(weight(child) == weight(best) && bytes.Compare(child.BlockRoot[:], best.BlockRoot[:]) > 0) {
+				best = child
+			}
+		}
+
+		if best == nil {
+			return head, nil
+		}
+
+		head = best
+	}
+}
+
+// boostedBranch returns the boosted block and its ancestors, which all carry
+// its proposer boost.
+func (t *Tree) boostedBranch(root *phase0.Root) map[phase0.Root]bool {
+	boosted := make(map[phase0.Root]bool)
+
+	if root == nil {
+		return boosted
+	}
+
+	for node := t.nodes[*root]; node != nil && !boosted[node.BlockRoot]; node = t.nodes[node.ParentRoot] {
+		boosted[node.BlockRoot] = true
+	}
+
+	return boosted
+}
+
+// isAncestor returns true if ancestor is root or one of its ancestors.
+func (t *Tree) isAncestor(ancestor, root phase0.Root) bool {
+	seen := make(map[phase0.Root]bool)
+
+	for node := t.nodes[root]; node != nil && !seen[node.BlockRoot]; node = t.nodes[node.ParentRoot] {
+		if node.BlockRoot == ancestor {
+			return true
+		}
+
+		seen[node.BlockRoot] = true
+	}
+
+	return false
+}
+
+// viableBranches returns the block roots under root that lead to at least one
+// viable head, as in the spec's filter_block_tree.
+func (t *Tree) viableBranches(root *v1.ForkChoiceNode, opts HeadOptions) map[phase0.Root]bool {
+	viable := make(map[phase0.Root]bool, len(t.nodes))
+
+	// Walk the tree depth first without recursion, as chains can be long
+	// during periods of non-finality.
+	type visit struct {
+		node     *v1.ForkChoiceNode
+		expanded bool
+	}
+
+	stack := []visit{{node: root}}
+	seen := make(map[phase0.Root]bool, len(t.nodes))
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := current.node
+		children := t.children[node.BlockRoot]
+
+		if !current.expanded {
+			if seen[node.BlockRoot] {
+				continue
+			}
+
+			seen[node.BlockRoot] = true
+
+			if len(children) > 0 {
+				stack = append(stack, visit{node: node, expanded: true})
+
+				for _, child := range children {
+					stack = append(stack, visit{node: child})
+				}
+
+				continue
+			}
+		}
+
+		if node.Validity == v1.ForkChoiceNodeValidityInvalid {
+			continue
+		}
+
+		if len(children) == 0 {
+			viable[node.BlockRoot] = t.leafIsViable(node, opts)
+
+			continue
+		}
+
+		for _, child := range children {
+			if viable[child.BlockRoot] {
+				viable[node.BlockRoot] = true
+
+				break
+			}
+		}
+	}
+
+	return viable
+}
+
+// leafIsViable returns true if a leaf's voting source agrees with the store's
+// justified checkpoint and the leaf descends from the finalized checkpoint.
+// Leaves whose voting source is within the last two epochs are also viable, as
+// in the spec since Deneb.
+func (t *Tree) leafIsViable(node *v1.ForkChoiceNode, opts HeadOptions) bool {
+	justified := t.forkChoice.JustifiedCheckpoint.Epoch
+	finalized := t.forkChoice.FinalizedCheckpoint
+
+	source := votingSource(node, opts)
+
+	correctJustified := justified == 0 ||
+		source == justified ||
+		source+2 >= opts.CurrentEpoch
+
+	// Ancestry can't be checked if the finalized block isn't in the dump,
+	// which is reported as an issue in its own right.
+	correctFinalized := finalized.Epoch == 0 ||
+		t.nodes[finalized.Root] == nil ||
+		t.isAncestor(finalized.Root, node.BlockRoot)
+
+	return correctJustified && correctFinalized
+}
+
+// votingSource returns the epoch of the justified checkpoint a block's votes
+// count towards, as in the spec's get_voting_source. Blocks from previous
+// epochs use their unrealized justification, if the node reported it.
+func votingSource(node *v1.ForkChoiceNode, opts HeadOptions) phase0.Epoch {
+	if opts.SlotsPerEpoch == 0 || phase0.Epoch(uint64(node.Slot)/opts.SlotsPerEpoch) >= opts.CurrentEpoch {
+		return node.JustifiedEpoch
+	}
+
+	if epoch, ok := extraDataEpoch(node.ExtraData, "unrealized_justified_epoch"); ok {
+		return epoch
+	}
+
+	return node.JustifiedEpoch
+}
+
+// extraDataEpoch returns an epoch from a node's extra data. Clients encode
+// numbers as strings, as elsewhere in the beacon API.
+func extraDataEpoch(extraData map[string]any, key string) (phase0.Epoch, bool) {
+	switch value := extraData[key].(type) {
+	case string:
+		epoch, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+
+		return phase0.Epoch(epoch), true
+	case float64:
+		return phase0.Epoch(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,140 @@
+package forkchoice
+
+import (
+	"sort"
+	"time"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+// WeightPoint is a block's weight in a single frame.
+type WeightPoint struct {
+	FrameID       string      `json:"frame_id"`
+	Node          string      `json:"node"`
+	FetchedAt     time.Time   `json:"fetched_at"`
+	WallClockSlot phase0.Slot `json:"wall_clock_slot"`
+	Weight        uint64      `json:"weight"`
+	// ParentWeight is the total weight of the block and its siblings, i.e.
+	// the weight competing under their parent.
+	ParentWeight uint64 `json:"parent_weight"`
+	// Share is the block's share of ParentWeight, from 0 to 1. It's nil if
+	// nothing under the parent has any weight.
+	Share *float64 `json:"share,omitempty"`
+	// Siblings is the number of other blocks with the same parent.
+	Siblings int `json:"siblings"`
+}
+
+// WeightSeries is how a block's weight changed across frames.
+type WeightSeries struct {
+	BlockRoot  phase0.Root   `json:"block_root"`
+	Slot       *phase0.Slot  `json:"slot,omitempty"`
+	ParentRoot *phase0.Root  `json:"parent_root,omitempty"`
+	Points     []WeightPoint `json:"points"`
+	// SkippedFrames is the number of frames that were skipped because their
+	// fork choice tree couldn't be built.
+	SkippedFrames int `json:"skipped_frames"`
+	// Truncated is true if there were more frames than could be searched, in
+	// which case the points stop short of the end of the range.
+	Truncated bool `json:"truncated"`
+}
+
+// blockWeight returns the block's weight in the frame. The block is nil if the
+// frame doesn't contain it.
+func blockWeight(frame *types.Frame, root phase0.Root) (*WeightPoint, *v1.ForkChoiceNode, error) {
+	if frame == nil {
+		return nil, nil, nil
+	}
+
+	tree, err := NewTree(frame.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block := tree.Node(root)
+	if block == nil {
+		return nil, nil, nil
+	}
+
+	point := &WeightPoint{
+		FrameID:       frame.Metadata.ID,
+		Node:          frame.Metadata.Node,
+		FetchedAt:     frame.Metadata.FetchedAt,
+		WallClockSlot: frame.Metadata.WallClockSlot,
+		Weight:        block.Weight,
+	}
+
+	for _, sibling := range tree.Children(block.ParentRoot) {
+		point.ParentWeight += sibling.Weight
+
+		if sibling.BlockRoot != root {
+			point.Siblings++
+		}
+	}
+
+	if point.ParentWeight > 0 {
+		share := float64(block.Weight) / float64(point.ParentWeight)
+		point.Share = &share
+	}
+
+	return point, block, nil
+}
+
+// NewWeightSeries returns the block's weight in every frame that contains it,
+// in the order the frames were fetched. Frames whose tree can't be built are
+// counted in SkippedFrames.
+func NewWeightSeries(frames []*types.Frame, root phase0.Root) *WeightSeries {
+	series := NewEmptyWeightSeries(root)
+
+	for _, frame := range frames {
+		series.Add(frame)
+	}
+
+	return series
+}
+
+// NewEmptyWeightSeries returns a series for the block with no points, for
+// frames to be added to one at a time.
+func NewEmptyWeightSeries(root phase0.Root) *WeightSeries {
+	return &WeightSeries{
+		BlockRoot: root,
+		Points:    []WeightPoint{},
+	}
+}
+
+// Add adds the block's weight in the frame to the series, keeping the points
+// in the order the frames were fetched. The frame isn't retained, so callers
+// can add frames as they're loaded without holding them all in memory.
+func (s *WeightSeries) Add(frame *types.Frame) {
+	point, block, err := blockWeight(frame, s.BlockRoot)
+	if err != nil {
+		s.SkippedFrames++
+
+		return
+	}
+
+	if block == nil {
+		return
+	}
+
+	if s.Slot == nil {
+		slot, parent := block.Slot, block.ParentRoot
+		s.Slot = &slot
+		s.ParentRoot = &parent
+	}
+
+	// Points go after any fetched at the same time by a node that sorts
+	// before or the same as this one, so equal points stay in insertion order.
+	i := sort.Search(len(s.Points), func(i int) bool {
+		if !s.Points[i].FetchedAt.Equal(point.FetchedAt) {
+			return s.Points[i].FetchedAt.After(point.FetchedAt)
+		}
+
+		return s.Points[i].Node > point.Node
+	})
+
+	s.Points = append(s.Points, WeightPoint{})
+	copy(s.Points[i+1:], s.Points[i:])
+	s.Points[i] = *point
+}
@@ -0,0 +1,51 @@
+package forkchoice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightSeries(t *testing.T) {
+	now := time.Now()
+
+	early := nodeFrame("early", "node-a", 2, 2, node(1, 1, 0, 10), node(2, 2, 1, 5), node(2, 3, 1, 5))
+	early.Metadata.FetchedAt = now
+
+	late := nodeFrame("late", "node-a", 3, 2, node(1, 1, 0, 40), node(2, 2, 1, 30), node(2, 3, 1, 10))
+	late.Metadata.FetchedAt = now.Add(12 * time.Second)
+
+	unweighted := nodeFrame("unweighted", "node-b", 2, 2, node(1, 1, 0, 0), node(2, 2, 1, 0))
+	unweighted.Metadata.FetchedAt = now
+
+	without := nodeFrame("without", "node-b", 3, 1, node(1, 1, 0, 10))
+
+	broken := nodeFrame("broken", "node-c", 3, 1, node(1, 1, 0, 10))
+	broken.Data = nil
+
+	series := NewWeightSeries([]*types.Frame{late, without, unweighted, early, broken}, root(2))
+
+	require.NotNil(t, series.Slot)
+	assert.EqualValues(t, 2, *series.Slot)
+	assert.Equal(t, root(1), *series.ParentRoot)
+
+	assert.Equal(t, 1, series.SkippedFrames)
+
+	require.Len(t, series.Points, 3)
+	assert.Equal(t, "early", series.Points[0].FrameID)
+	assert.Equal(t, "unweighted", series.Points[1].FrameID)
+	assert.Equal(t, "late", series.Points[2].FrameID)
+
+	assert.Equal(t, uint64(10), series.Points[0].ParentWeight)
+	assert.Equal(t, 1, series.Points[0].Siblings)
+	assert.InDelta(t, 0.5, *series.Points[0].Share, 0.0001)
+
+	assert.Nil(t, series.Points[1].Share)
+	assert.Equal(t, 0, series.Points[1].Siblings)
+
+	assert.Equal(t, uint64(30), series.Points[2].Weight)
+	assert.InDelta(t, 0.75, *series.Points[2].Share, 0.0001)
+}
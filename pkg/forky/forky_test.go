@@ -4,11 +4,18 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
 	"github.com/ethpandaops/forky/pkg/forky/service"
 	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/ethpandaops/forky/pkg/yaml"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
@@ -107,6 +114,54 @@ func TestForkChoiceServer(t *testing.T) {
 		assert.Equal(t, frame.Metadata.ID, f.Metadata.ID)
 	})
 
+	t.Run("Add a redelivered frame", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		frame := types.GenerateFakeFrame()
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+		assert.NoError(t, err)
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+		assert.NoError(t, err)
+
+		metadata, _, err := s.svc.ListMetadata(context.Background(), &service.FrameFilter{Node: &frame.Metadata.Node}, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Len(t, metadata, 1)
+	})
+
+	t.Run("Add and get a raw frame", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		raw := []byte(`{"justified_checkpoint": {"epoch": "1"}, "weight": 00001}`)
+
+		f := types.GenerateFakeFrame()
+		f.RawPayload = raw
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", f)
+		assert.NoError(t, err)
+
+		withoutRaw := types.GenerateFakeFrame()
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", withoutRaw)
+		assert.NoError(t, err)
+
+		data, err := s.svc.GetRawFrame(context.Background(), f.Metadata.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, raw, data)
+
+		_, err = s.svc.GetRawFrame(context.Background(), withoutRaw.Metadata.ID)
+		assert.ErrorIs(t, err, service.ErrRawFrameNotFound)
+
+		err = s.svc.DeleteFrame(context.Background(), f.Metadata.ID)
+		assert.NoError(t, err)
+
+		_, err = s.svc.GetRawFrame(context.Background(), f.Metadata.ID)
+		assert.ErrorIs(t, err, service.ErrRawFrameNotFound)
+	})
+
 	t.Run("Add and list a frame", func(t *testing.T) {
 		s, err := newTestServer("")
 		assert.NoError(t, err)
@@ -263,4 +318,848 @@ func TestForkChoiceServer(t *testing.T) {
 		assert.Equal(t, page.Total, int64(0))
 		assert.Equal(t, len(newFrames), 0)
 	})
+
+	t.Run("Consistency check", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		go func() {
+			err = s.Start(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		framesToCreate := 10
+		for i := 0; i < framesToCreate; i++ {
+			f := types.GenerateFakeFrame()
+			err = s.svc.AddNewFrame(context.Background(), "fake", f)
+			assert.NoError(t, err)
+		}
+
+		report, err := s.svc.CheckConsistency(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, report.Consistent())
+		assert.Equal(t, framesToCreate, report.StoredFrames)
+		assert.Equal(t, framesToCreate, report.IndexedFrames)
+	})
+
+	t.Run("Repair an inconsistent store and indexer", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s, err := newTestServer(fmt.Sprintf(`
+listen_addr: ":%d"
+pprof_addr: ":%d"
+metrics:
+  enabled: false
+
+forky:
+  ethereum:
+    network:
+      name: "mainnet"
+      spec:
+        seconds_per_slot: 12
+        slots_per_epoch: 32
+        genesis_time: 1609459200
+  store:
+    type: "fs"
+    config:
+      base_dir: "%s"
+      format: "json"
+  indexer:
+    driver_name: "sqlite"
+    dsn: "file:%d?mode=memory&cache=shared"
+`, 5560+testDBCounter, 6060+testDBCounter, dir, testDBCounter))
+		assert.NoError(t, err)
+
+		// writeOrphan stores a frame without indexing it.
+		writeOrphan := func(frame *types.Frame, name string) {
+			data, err := frame.AsJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(filepath.Join(dir, name+".json"), data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		consistent := types.GenerateFakeFrame()
+		err = s.svc.AddNewFrame(context.Background(), "fake", consistent)
+		assert.NoError(t, err)
+
+		orphan := types.GenerateFakeFrame()
+		writeOrphan(orphan, orphan.Metadata.ID)
+
+		// A frame whose file name doesn't match its ID can't be reindexed.
+		mismatched := types.GenerateFakeFrame()
+		writeOrphan(mismatched, "mismatched")
+
+		// Indexed, but missing from the store.
+		missing := types.GenerateFakeFrame()
+		err = s.svc.AddNewFrame(context.Background(), "fake", missing)
+		assert.NoError(t, err)
+		assert.NoError(t, os.Remove(filepath.Join(dir, missing.Metadata.ID+".json")))
+
+		report, err := s.svc.CheckConsistency(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, report.Consistent())
+		assert.Equal(t, 3, report.StoredFrames)
+		assert.Equal(t, 2, report.IndexedFrames)
+		assert.ElementsMatch(t, []string{orphan.Metadata.ID, "mismatched"}, report.OrphanedFrames)
+		assert.Equal(t, []string{missing.Metadata.ID}, report.OrphanedMetadata)
+
+		result, err := s.svc.RepairConsistency(context.Background(), report, service.RepairOptions{
+			OrphanedFrames:         service.OrphanedFrameActionReindex,
+			DeleteOrphanedMetadata: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ReindexedFrames)
+		assert.Equal(t, 1, result.DeletedMetadata)
+		assert.Equal(t, 1, result.Failed)
+
+		metadata, _, err := s.svc.ListMetadata(context.Background(), &service.FrameFilter{Node: &orphan.Metadata.Node}, service.PaginationCursor{Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, metadata, 1)
+
+		_, err = s.svc.GetFrame(context.Background(), missing.Metadata.ID)
+		assert.Error(t, err)
+
+		report, err = s.svc.CheckConsistency(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mismatched"}, report.OrphanedFrames)
+		assert.Empty(t, report.OrphanedMetadata)
+
+		// Orphaned frames that can't be reindexed can be deleted instead.
+		result, err = s.svc.RepairConsistency(context.Background(), report, service.RepairOptions{
+			OrphanedFrames: service.OrphanedFrameActionDelete,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.DeletedFrames)
+		assert.Equal(t, 0, result.Failed)
+		assert.NoFileExists(t, filepath.Join(dir, "mismatched.json"))
+
+		report, err = s.svc.CheckConsistency(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, report.Consistent())
+		assert.Equal(t, 2, report.StoredFrames)
+		assert.Equal(t, 2, report.IndexedFrames)
+	})
+	t.Run("Migrate label-encoded reorgs", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		oldHead := "0x01"
+		newHead := "0x02"
+
+		for _, timing := range []string{"before", "after"} {
+			f := types.GenerateFakeFrame()
+			f.Metadata.Node = "sentry"
+			f.Metadata.EventSource = types.XatuReorgEventEventSource.String()
+			f.Metadata.Labels = []string{
+				"xatu_reorg_event_slot=100",
+				"xatu_reorg_event_epoch=3",
+				"xatu_reorg_event_old_head_block=" + oldHead,
+				"xatu_reorg_event_old_head_state=0x03",
+				"xatu_reorg_event_new_head_block=" + newHead,
+				"xatu_reorg_event_new_head_state=0x04",
+				"xatu_reorg_event_depth=2",
+				"xatu_reorg_frame_timing=" + timing,
+				"keep_me",
+			}
+
+			err = s.svc.AddNewFrame(context.Background(), "fake", f)
+			assert.NoError(t, err)
+		}
+
+		migrated, err := s.svc.BackfillReorgs(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, migrated)
+
+		reorgs, _, err := s.svc.ListReorgs(context.Background(), &service.ReorgFilter{}, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Len(t, reorgs, 1)
+
+		reorg := reorgs[0]
+		assert.Equal(t, "sentry", reorg.Node)
+		assert.Equal(t, uint64(2), reorg.Depth)
+		assert.Equal(t, oldHead, reorg.OldHeadBlock)
+		assert.NotEmpty(t, reorg.BeforeFrameID)
+		assert.NotEmpty(t, reorg.AfterFrameID)
+
+		_, frames, err := s.svc.GetReorgFrames(context.Background(), reorg.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, frames.Before)
+		assert.NotNil(t, frames.After)
+
+		// The reorg labels are gone, everything else is kept.
+		labels, _, err := s.svc.ListLabels(context.Background(), &service.FrameFilter{}, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"keep_me"}, labels)
+
+		migrated, err = s.svc.BackfillReorgs(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, migrated)
+	})
+	t.Run("Manage sources at runtime", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		go func() {
+			err = s.Start(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		config := yaml.NewRawMessage([]byte(`{"address": "127.0.0.1:0"}`))
+
+		created, err := s.svc.UpsertSource(context.Background(), "runtime", "xatu_http", "", config)
+		assert.NoError(t, err)
+		assert.True(t, created)
+
+		sources, err := s.svc.ListSources(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, sources, 1)
+		assert.Equal(t, "runtime", sources[0].Name)
+
+		created, err = s.svc.UpsertSource(context.Background(), "runtime", "xatu_http", "", config)
+		assert.NoError(t, err)
+		assert.False(t, created)
+
+		_, err = s.svc.UpsertSource(context.Background(), "invalid", "carrier_pigeon", "", config)
+		assert.ErrorIs(t, err, service.ErrInvalidSource)
+
+		err = s.svc.RemoveSource(context.Background(), "runtime")
+		assert.NoError(t, err)
+
+		err = s.svc.RemoveSource(context.Background(), "runtime")
+		assert.ErrorIs(t, err, service.ErrSourceNotFound)
+
+		sources, err = s.svc.ListSources(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, sources)
+	})
+	t.Run("Tag source frames with the source's network", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		go func() {
+			err = s.Start(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		dir := t.TempDir()
+
+		// Frames replayed from another deployment carry its network.
+		frame := types.GenerateFakeFrame()
+		frame.Metadata.Network = "elsewhere"
+
+		data, err := frame.AsJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, frame.Metadata.ID+".json"), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = s.svc.UpsertSource(context.Background(), "replay", "file_replay", "",
+			yaml.NewRawMessage([]byte(fmt.Sprintf(`{"path": %q, "mode": "all_at_once"}`, dir))))
+		assert.NoError(t, err)
+
+		network := "mainnet"
+
+		assert.Eventually(t, func() bool {
+			frames, _, err := s.svc.ListMetadata(context.Background(), &service.FrameFilter{Network: &network}, *service.DefaultPagination())
+
+			return err == nil && len(frames) == 1
+		}, 5*time.Second, 50*time.Millisecond)
+
+		other := "elsewhere"
+
+		frames, _, err := s.svc.ListMetadata(context.Background(), &service.FrameFilter{Network: &other}, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Empty(t, frames)
+	})
+	t.Run("Replace sources at runtime", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		go func() {
+			err = s.Start(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		time.Sleep(1 * time.Second)
+
+		// Find a free address for the source to hold.
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		address := lis.Addr().String()
+
+		assert.NoError(t, lis.Close())
+
+		config := yaml.NewRawMessage([]byte(fmt.Sprintf(`{"address": %q}`, address)))
+
+		created, err := s.svc.UpsertSource(context.Background(), "runtime", "xatu_http", "", config)
+		assert.NoError(t, err)
+		assert.True(t, created)
+
+		// The replacement needs the address the existing source holds.
+		created, err = s.svc.UpsertSource(context.Background(), "runtime", "xatu_grpc", "", config)
+		assert.NoError(t, err)
+		assert.False(t, created)
+
+		sources, err := s.svc.ListSources(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, sources, 1)
+		assert.Equal(t, "xatu_grpc", sources[0].Type)
+
+		// A replacement that can't start leaves the existing source running.
+		taken, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		defer taken.Close()
+
+		_, err = s.svc.UpsertSource(context.Background(), "runtime", "xatu_http", "",
+			yaml.NewRawMessage([]byte(fmt.Sprintf(`{"address": %q}`, taken.Addr().String()))))
+		assert.Error(t, err)
+
+		sources, err = s.svc.ListSources(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, sources, 1)
+		assert.Equal(t, "xatu_grpc", sources[0].Type)
+
+		conn, err := net.Dial("tcp", address)
+		if assert.NoError(t, err) {
+			conn.Close()
+		}
+	})
+	t.Run("Discover sources from a file", func(t *testing.T) {
+		nodes := filepath.Join(t.TempDir(), "nodes.yaml")
+
+		err := os.WriteFile(nodes, []byte(`
+- name: lighthouse-1
+  address: http://127.0.0.1:1
+  labels:
+    client: lighthouse
+- name: teku-1
+  address: http://127.0.0.1:2
+  labels:
+    client: teku
+`), 0o600)
+		assert.NoError(t, err)
+
+		s, err := newTestServer(fmt.Sprintf(`
+listen_addr: ":%d"
+pprof_addr: ":%d"
+metrics:
+  enabled: false
+
+forky:
+  ethereum:
+    network:
+      name: "mainnet"
+      spec:
+        seconds_per_slot: 12
+        slots_per_epoch: 32
+        genesis_time: 1609459200
+  store:
+    type: "memory"
+  indexer:
+    driver_name: "sqlite"
+    dsn: "file:%d?mode=memory&cache=shared"
+  discovery:
+    - name: "devnet"
+      type: "file"
+      interval: 100ms
+      config:
+        path: "%s"
+      source:
+        labels:
+          - "client={{ .Labels.client }}"
+        config:
+          polling_interval: 12s
+`, 5560+testDBCounter, 6060+testDBCounter, testDBCounter, nodes))
+		assert.NoError(t, err)
+
+		go func() {
+			err = s.Start(context.Background())
+			assert.NoError(t, err)
+		}()
+
+		sourceNames := func() []string {
+			sources, err := s.svc.ListSources(context.Background())
+			assert.NoError(t, err)
+
+			names := []string{}
+			for _, source := range sources {
+				names = append(names, source.Name)
+			}
+
+			return names
+		}
+
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"devnet-lighthouse-1", "devnet-teku-1"}, sourceNames())
+		}, 5*time.Second, 50*time.Millisecond)
+
+		err = os.WriteFile(nodes, []byte(`
+- name: teku-1
+  address: http://127.0.0.1:2
+  labels:
+    client: teku
+`), 0o600)
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return assert.ObjectsAreEqual([]string{"devnet-teku-1"}, sourceNames())
+		}, 5*time.Second, 50*time.Millisecond)
+
+		// Sources are kept if the backend becomes unavailable.
+		err = os.Remove(nodes)
+		assert.NoError(t, err)
+
+		time.Sleep(300 * time.Millisecond)
+
+		assert.Equal(t, []string{"devnet-teku-1"}, sourceNames())
+	})
+
+	t.Run("Label frames with their fork", func(t *testing.T) {
+		// Mainnet's spec and fork schedule come from its preset.
+		s, err := newTestServer(fmt.Sprintf(`
+listen_addr: ":%d"
+pprof_addr: ":%d"
+metrics:
+  enabled: false
+
+forky:
+  ethereum:
+    label_frames_with_fork: true
+    network:
+      name: "mainnet"
+  store:
+    type: "memory"
+  indexer:
+    driver_name: "sqlite"
+    dsn: "file:%d?mode=memory&cache=shared"
+`, 5560+testDBCounter, 6060+testDBCounter, testDBCounter))
+		assert.NoError(t, err)
+
+		capella := types.GenerateFakeFrame()
+		capella.Metadata.WallClockSlot = 194048 * 32
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", capella)
+		assert.NoError(t, err)
+
+		frame, err := s.svc.GetFrame(context.Background(), capella.Metadata.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test", "ethereum_fork_capella"}, frame.Metadata.Labels)
+
+		bellatrix := types.GenerateFakeFrame()
+		bellatrix.Metadata.WallClockSlot = 194048*32 - 1
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", bellatrix)
+		assert.NoError(t, err)
+
+		frame, err = s.svc.GetFrame(context.Background(), bellatrix.Metadata.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test", "ethereum_fork_bellatrix"}, frame.Metadata.Labels)
+
+		fork, err := s.svc.GetEthereumForkAtSlot(context.Background(), "", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, ethereum.ForkPhase0, fork)
+	})
+
+	t.Run("Label frames with their fork per network", func(t *testing.T) {
+		s, err := newTestServer(fmt.Sprintf(`
+listen_addr: ":%d"
+pprof_addr: ":%d"
+metrics:
+  enabled: false
+
+forky:
+  ethereum:
+    network:
+      name: "mainnet"
+  networks:
+    - ethereum:
+        label_frames_with_fork: true
+        network:
+          name: "sepolia"
+  store:
+    type: "memory"
+  indexer:
+    driver_name: "sqlite"
+    dsn: "file:%d?mode=memory&cache=shared"
+`, 5560+testDBCounter, 6060+testDBCounter, testDBCounter))
+		assert.NoError(t, err)
+
+		mainnet := types.GenerateFakeFrame()
+		mainnet.Metadata.Network = "mainnet"
+		mainnet.Metadata.WallClockSlot = 194048 * 32
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", mainnet)
+		assert.NoError(t, err)
+
+		frame, err := s.svc.GetFrame(context.Background(), mainnet.Metadata.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test"}, frame.Metadata.Labels)
+
+		sepolia := types.GenerateFakeFrame()
+		sepolia.Metadata.Network = "sepolia"
+		sepolia.Metadata.WallClockSlot = 0
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", sepolia)
+		assert.NoError(t, err)
+
+		frame, err = s.svc.GetFrame(context.Background(), sepolia.Metadata.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"test", "ethereum_fork_phase0"}, frame.Metadata.Labels)
+	})
+
+	t.Run("Filter reorgs by network", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		for i, network := range []string{"mainnet", "sepolia", ""} {
+			err = s.svc.AddNewReorg(context.Background(), "fake", &types.Reorg{
+				ID:         fmt.Sprintf("reorg-%d", i),
+				Network:    network,
+				Node:       "node-a",
+				DetectedAt: time.Now(),
+				Slot:       phase0.Slot(100 + i),
+			})
+			assert.NoError(t, err)
+		}
+
+		// Reorgs recorded before networks were are put on the default network.
+		err = s.svc.BackfillNetwork(context.Background())
+		assert.NoError(t, err)
+
+		network := "mainnet"
+
+		reorgs, _, err := s.svc.ListReorgs(context.Background(), &service.ReorgFilter{Network: &network}, *service.DefaultPagination())
+		assert.NoError(t, err)
+
+		ids := []string{}
+		for _, reorg := range reorgs {
+			ids = append(ids, reorg.ID)
+		}
+
+		assert.ElementsMatch(t, []string{"reorg-0", "reorg-2"}, ids)
+	})
+
+	t.Run("Serve multiple networks", func(t *testing.T) {
+		s, err := newTestServer(fmt.Sprintf(`
+listen_addr: ":%d"
+pprof_addr: ":%d"
+metrics:
+  enabled: false
+
+forky:
+  retention_period: 24h
+  ethereum:
+    network:
+      name: "mainnet"
+  networks:
+    - ethereum:
+        network:
+          name: "devnet-1"
+          spec:
+            seconds_per_slot: 6
+            slots_per_epoch: 8
+            genesis_time: 1700000000
+      retention_period: 1h
+  store:
+    type: "memory"
+  indexer:
+    driver_name: "sqlite"
+    dsn: "file:%d?mode=memory&cache=shared"
+`, 5560+testDBCounter, 6060+testDBCounter, testDBCounter))
+		assert.NoError(t, err)
+
+		assert.Equal(t, []string{"mainnet", "devnet-1"}, s.svc.ListEthereumNetworks(context.Background()))
+
+		secondsPerSlot, err := s.svc.GetEthereumSpecSecondsPerSlot(context.Background(), "devnet-1")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(6), secondsPerSlot)
+
+		secondsPerSlot, err = s.svc.GetEthereumSpecSecondsPerSlot(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(12), secondsPerSlot)
+
+		_, _, err = s.svc.GetEthereumNow(context.Background(), "devnet-2")
+		assert.ErrorIs(t, err, service.ErrNetworkNotFound)
+
+		// Old devnet frames are deleted sooner than old mainnet frames.
+		devnet := types.GenerateFakeFrame()
+		devnet.Metadata.Network = "devnet-1"
+		devnet.Metadata.FetchedAt = time.Now().Add(-2 * time.Hour)
+
+		mainnet := types.GenerateFakeFrame()
+		mainnet.Metadata.Network = "mainnet"
+		mainnet.Metadata.FetchedAt = time.Now().Add(-2 * time.Hour)
+
+		for _, frame := range []*types.Frame{devnet, mainnet} {
+			err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+			assert.NoError(t, err)
+		}
+
+		network := "devnet-1"
+
+		frames, _, err := s.svc.ListMetadata(context.Background(), &service.FrameFilter{Network: &network}, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Len(t, frames, 1)
+		assert.Equal(t, devnet.Metadata.ID, frames[0].ID)
+		assert.Equal(t, "devnet-1", frames[0].Network)
+
+		err = s.svc.DeleteOldFrames(context.Background())
+		assert.NoError(t, err)
+
+		_, err = s.svc.GetFrame(context.Background(), devnet.Metadata.ID)
+		assert.ErrorIs(t, err, service.ErrFrameNotFound)
+
+		_, err = s.svc.GetFrame(context.Background(), mainnet.Metadata.ID)
+		assert.NoError(t, err)
+	})
+	t.Run("Analyze a frame", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		frame := types.GenerateFakeFrame()
+		err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+		assert.NoError(t, err)
+
+		analysis, err := s.svc.GetFrameAnalysis(context.Background(), frame.Metadata.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, frame.Metadata.ID, analysis.FrameID)
+
+		_, err = s.svc.GetFrameAnalysis(context.Background(), "missing")
+		assert.ErrorIs(t, err, service.ErrFrameNotFound)
+	})
+	t.Run("Chain report", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		frame := types.GenerateFakeFrame()
+		err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+		assert.NoError(t, err)
+
+		slot := frame.Metadata.WallClockSlot
+
+		report, err := s.svc.GetChainReport(context.Background(), nil, slot, slot)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{frame.Metadata.ID}, report.Frames)
+
+		_, err = s.svc.GetChainReport(context.Background(), nil, slot+1, slot)
+		assert.ErrorIs(t, err, service.ErrInvalidSlotRange)
+
+		_, err = s.svc.GetChainReport(context.Background(), nil, 0, service.MaxChainReportSlots)
+		assert.ErrorIs(t, err, service.ErrInvalidSlotRange)
+	})
+	t.Run("Chain report uses the frame covering the end of the range", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		addFrame := func(node string, slot phase0.Slot) *types.Frame {
+			frame := types.GenerateFakeFrame()
+			frame.Metadata.Node = node
+			frame.Metadata.WallClockSlot = slot
+
+			err := s.svc.AddNewFrame(context.Background(), "fake", frame)
+			assert.NoError(t, err)
+
+			return frame
+		}
+
+		addFrame("node-a", 1005)
+		after := addFrame("node-a", 1020)
+		addFrame("node-a", 1030)
+		within := addFrame("node-b", 1008)
+		addFrame("node-c", 990)
+
+		report, err := s.svc.GetChainReport(context.Background(), nil, 1000, 1010)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{after.Metadata.ID, within.Metadata.ID}, report.Frames)
+	})
+	t.Run("Monitor finality lag", func(t *testing.T) {
+		s, err := newTestServer(fmt.Sprintf(`
+listen_addr: ":%d"
+pprof_addr: ":%d"
+metrics:
+  enabled: false
+
+forky:
+  ethereum:
+    network:
+      name: "mainnet"
+  finality_monitor:
+    enabled: true
+    threshold: 1
+  store:
+    type: "memory"
+  indexer:
+    driver_name: "sqlite"
+    dsn: "file:%d?mode=memory&cache=shared"
+`, 5560+testDBCounter, 6060+testDBCounter, testDBCounter))
+		assert.NoError(t, err)
+
+		addFrameAt := func(node string, wallClockEpoch, justified, finalized phase0.Epoch) {
+			frame := types.GenerateFakeFrame()
+			frame.Metadata.Node = node
+			frame.Metadata.WallClockEpoch = wallClockEpoch
+			frame.Data.JustifiedCheckpoint.Epoch = justified
+			frame.Data.FinalizedCheckpoint.Epoch = finalized
+
+			err := s.svc.AddNewFrame(context.Background(), "fake", frame)
+			assert.NoError(t, err)
+		}
+
+		addFrame := func(node string, justified, finalized phase0.Epoch) {
+			addFrameAt(node, 100, justified, finalized)
+		}
+
+		addFrame("node-a", 99, 98)
+		addFrame("node-b", 99, 98)
+		addFrame("node-c", 98, 90)
+
+		events, err := s.svc.CheckFinalityLag(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, "node-c", events[0].Node)
+		assert.Equal(t, service.CheckpointFinalized, events[0].Checkpoint)
+		assert.Equal(t, phase0.Epoch(98), events[0].MajorityEpoch)
+		assert.False(t, events[0].Recovered)
+
+		nodes, _, err := s.svc.GetFinalityStatus(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Len(t, nodes, 3)
+		assert.Equal(t, uint64(10), nodes[2].FinalityLag)
+		assert.True(t, nodes[2].FinalityLagging)
+		assert.False(t, nodes[2].JustificationLagging)
+
+		// An older frame arriving late doesn't replace the node's latest.
+		addFrameAt("node-a", 50, 10, 5)
+
+		nodes, _, err = s.svc.GetFinalityStatus(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, phase0.Epoch(98), nodes[0].FinalizedEpoch)
+		assert.Equal(t, uint64(2), nodes[0].FinalityLag)
+
+		// Nothing changes, so no new events are raised.
+		events, err = s.svc.CheckFinalityLag(context.Background())
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+
+		addFrame("node-c", 99, 98)
+
+		events, err = s.svc.CheckFinalityLag(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+		assert.True(t, events[0].Recovered)
+
+		_, history, err := s.svc.GetFinalityStatus(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Len(t, history, 2)
+
+		_, _, err = s.svc.GetFinalityStatus(context.Background(), "unknown")
+		assert.ErrorIs(t, err, service.ErrNetworkNotFound)
+	})
+	t.Run("Block weights", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		frame := types.GenerateFakeFrame()
+		frame.Data.ForkChoiceNodes[0].Slot = 100
+
+		err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+		assert.NoError(t, err)
+
+		block := frame.Data.ForkChoiceNodes[0]
+		slot := uint64(frame.Metadata.WallClockSlot)
+
+		series, err := s.svc.GetBlockWeights(context.Background(), block.BlockRoot, &service.FrameFilter{Slot: &slot})
+		assert.NoError(t, err)
+		assert.Len(t, series.Points, 1)
+		assert.Equal(t, frame.Metadata.ID, series.Points[0].FrameID)
+		assert.Equal(t, block.Weight, series.Points[0].Weight)
+
+		// Without a slot range, the block's slot is found from the latest
+		// frames and only one frame per node per slot is used.
+		after := &types.Frame{Data: frame.Data, Metadata: frame.Metadata}
+		after.Metadata.ID = uuid.New().String()
+		after.Metadata.WallClockSlot = block.Slot + 1
+		after.Metadata.FetchedAt = frame.Metadata.FetchedAt.Add(time.Second)
+
+		again := &types.Frame{Data: after.Data, Metadata: after.Metadata}
+		again.Metadata.ID = uuid.New().String()
+		again.Metadata.FetchedAt = after.Metadata.FetchedAt.Add(time.Second)
+
+		for _, fr := range []*types.Frame{after, again} {
+			err = s.svc.AddNewFrame(context.Background(), "fake", fr)
+			assert.NoError(t, err)
+		}
+
+		series, err = s.svc.GetBlockWeights(context.Background(), block.BlockRoot, nil)
+		assert.NoError(t, err)
+
+		ids := []string{}
+		for _, point := range series.Points {
+			ids = append(ids, point.FrameID)
+		}
+
+		assert.Contains(t, ids, after.Metadata.ID)
+		assert.NotContains(t, ids, again.Metadata.ID)
+		assert.False(t, series.Truncated)
+
+		// Blocks that aren't in the latest frames need a slot range.
+		_, err = s.svc.GetBlockWeights(context.Background(), phase0.Root{0x01}, &service.FrameFilter{})
+		assert.ErrorIs(t, err, service.ErrInvalidFilter)
+	})
+	t.Run("Backfill consensus client versions", func(t *testing.T) {
+		s, err := newTestServer("")
+		assert.NoError(t, err)
+
+		// Frames indexed before versions were only have the node's version string.
+		legacy := types.GenerateFakeFrame()
+		legacy.Metadata.NodeContext = &types.NodeContext{Version: "Lighthouse/v5.1.0-a1b2c3d/x86_64-linux"}
+
+		unknown := types.GenerateFakeFrame()
+		unknown.Metadata.NodeContext = &types.NodeContext{Version: "somethingnew"}
+
+		for _, frame := range []*types.Frame{legacy, unknown} {
+			err = s.svc.AddNewFrame(context.Background(), "fake", frame)
+			assert.NoError(t, err)
+		}
+
+		minVersion := "5.0.0"
+		filter := &service.FrameFilter{MinConsensusClientVersion: &minVersion}
+
+		frames, _, err := s.svc.ListMetadata(context.Background(), filter, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Empty(t, frames)
+
+		err = s.svc.BackfillConsensusClientVersion(context.Background())
+		assert.NoError(t, err)
+
+		frames, _, err = s.svc.ListMetadata(context.Background(), filter, *service.DefaultPagination())
+		assert.NoError(t, err)
+		assert.Len(t, frames, 1)
+		assert.Equal(t, legacy.Metadata.ID, frames[0].ID)
+		assert.Equal(t, "5.1.0", frames[0].ConsensusClientVersion)
+
+		invalid := "latest"
+
+		_, _, err = s.svc.ListMetadata(context.Background(), &service.FrameFilter{MaxConsensusClientVersion: &invalid}, *service.DefaultPagination())
+		assert.ErrorIs(t, err, service.ErrInvalidFilter)
+	})
 }
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/ethpandaops/forky/pkg/forky/archive"
+	"github.com/ethpandaops/forky/pkg/forky/store"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportResult describes the outcome of an export.
+type ExportResult struct {
+	// Listed is the number of frames that matched the filter.
+	Listed int `json:"listed"`
+	// Exported is the number of frames written to the archive.
+	Exported int `json:"exported"`
+	// Missing is the number of indexed frames that could not be found in the store.
+	Missing int `json:"missing"`
+}
+
+// ImportResult describes the outcome of an import.
+type ImportResult struct {
+	// Imported is the number of frames stored and indexed.
+	Imported int `json:"imported"`
+	// Skipped is the number of frames that were already indexed.
+	Skipped int `json:"skipped"`
+	// Failed is the number of frames that could not be imported.
+	Failed int `json:"failed"`
+}
+
+// ExportFrames writes every frame matching the filter within the page to the archive.
+// Frames that are indexed but missing from the store are skipped.
+func (f *ForkChoice) ExportFrames(ctx context.Context, filter *FrameFilter, page PaginationCursor, w archive.Writer) (*ExportResult, error) {
+	operation := OperationExportFrames
+
+	f.metrics.ObserveOperation(operation)
+
+	if filter == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, ErrInvalidFilter
+	}
+
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	metadata, err := f.indexer.ListFrameMetadata(ctx, filter.AsDBFilter(), page.AsDBPageCursor())
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).Error("failed to list metadata for export")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	result := &ExportResult{
+		Listed: len(metadata),
+	}
+
+	for _, md := range metadata {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		frame, err := f.store.GetFrame(ctx, md.ID)
+		if err != nil {
+			if errors.Is(err, store.ErrFrameNotFound) {
+				f.log.WithField("id", md.ID).Warn("Frame is indexed but missing from the store, skipping export")
+
+				result.Missing++
+
+				continue
+			}
+
+			f.metrics.ObserveOperationError(operation)
+
+			return result, err
+		}
+
+		if err := w.WriteFrame(frame); err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			return result, err
+		}
+
+		result.Exported++
+	}
+
+	return result, nil
+}
+
+// ImportFrames stores and indexes every frame in the archive. Frames that are
+// already indexed are skipped, so an import can safely be run more than once.
+func (f *ForkChoice) ImportFrames(ctx context.Context, r archive.Reader) (*ImportResult, error) {
+	operation := OperationImportFrames
+
+	f.metrics.ObserveOperation(operation)
+
+	result := &ImportResult{}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		frame, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			f.metrics.ObserveOperationError(operation)
+
+			return result, err
+		}
+
+		if err := frame.Validate(); err != nil {
+			f.log.WithError(err).Warn("Skipping invalid frame in archive")
+
+			result.Failed++
+
+			continue
+		}
+
+		logCtx := f.log.WithFields(logrus.Fields{
+			"id":   frame.Metadata.ID,
+			"node": frame.Metadata.Node,
+		})
+
+		indexed, err := f.isIndexed(ctx, frame.Metadata.ID)
+		if err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			return result, err
+		}
+
+		if indexed {
+			result.Skipped++
+
+			continue
+		}
+
+		// The frame may already be in the store from a previous, interrupted import.
+		if err := f.store.SaveFrame(ctx, frame); err != nil && !errors.Is(err, store.ErrFrameAlreadyStored) {
+			logCtx.WithError(err).Error("Failed to store imported frame")
+
+			result.Failed++
+
+			continue
+		}
+
+		if err := f.indexer.InsertFrameMetadata(ctx, &frame.Metadata); err != nil {
+			logCtx.WithError(err).Error("Failed to index imported frame")
+
+			result.Failed++
+
+			continue
+		}
+
+		result.Imported++
+	}
+
+	if result.Failed > 0 {
+		f.metrics.ObserveOperationError(operation)
+	}
+
+	return result, nil
+}
@@ -3,13 +3,25 @@ package service
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
+	"github.com/ethpandaops/forky/pkg/forky/types"
 	"github.com/pkg/errors"
 )
 
+const (
+	// Reorgs used to be encoded as labels on their frames. These are the labels
+	// that BackfillReorgs converts into reorg records.
+	legacyReorgLabelPrefix  = "xatu_reorg_event_"
+	legacyReorgTimingLabel  = "xatu_reorg_frame_timing"
+	legacyReorgTimingBefore = "before"
+)
+
 func (f *ForkChoice) BackfillConsensusClient(ctx context.Context) error {
 	// Get all frames that don't have a consensus client
 	empty := ""
@@ -63,6 +75,107 @@ func (f *ForkChoice) BackfillConsensusClient(ctx context.Context) error {
 	return nil
 }
 
+// BackfillNetwork puts frames and reorgs from before networks were recorded on
+// the default network.
+func (f *ForkChoice) BackfillNetwork(ctx context.Context) error {
+	empty := ""
+
+	filter := &FrameFilter{
+		Network: &empty,
+	}
+
+	frames, err := f.indexer.ListFrameMetadata(ctx, filter.AsDBFilter(), &db.PaginationCursor{
+		Limit:   1000,
+		Offset:  0,
+		OrderBy: "fetched_at DESC",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, frame := range frames {
+		frame.Network = f.networkNames[0]
+
+		f.log.
+			WithField("frame_id", frame.ID).
+			WithField("network", frame.Network).
+			Debug("Backfilling network")
+
+		if err := f.indexer.UpdateFrameMetadata(ctx, frame); err != nil {
+			f.log.WithError(err).WithField("frame_id", frame.ID).Error("Failed to update frame metadata")
+
+			continue
+		}
+	}
+
+	f.log.Debugf("Updated network on %v frames", len(frames))
+
+	reorgs, err := f.indexer.SetEmptyReorgNetwork(ctx, f.networkNames[0])
+	if err != nil {
+		return err
+	}
+
+	f.log.Debugf("Updated network on %v reorgs", reorgs)
+
+	return nil
+}
+
+// BackfillConsensusClientVersion fills in the consensus client version and its
+// sort key on frames indexed before versions were, so that they match version
+// filters. Frames whose version can't be parsed are left as they are.
+func (f *ForkChoice) BackfillConsensusClientVersion(ctx context.Context) error {
+	// Frames that are updated drop out of the results, so only skip past the
+	// ones that can't be.
+	offset := 0
+	updated := 0
+
+	for {
+		frames, err := f.indexer.ListFrameMetadataWithoutVersionKey(ctx, &db.PaginationCursor{
+			Limit:   1000,
+			Offset:  offset,
+			OrderBy: "fetched_at DESC",
+		})
+		if err != nil {
+			return err
+		}
+
+		if len(frames) == 0 {
+			break
+		}
+
+		for _, frame := range frames {
+			version := frame.ConsensusClientVersion
+			if version == "" && frame.NodeContext != nil {
+				version = ethereum.ParseClientVersion(frame.NodeContext.Version).Version
+			}
+
+			key, err := ethereum.VersionSortKey(version)
+			if version == "" || err != nil {
+				offset++
+
+				continue
+			}
+
+			frame.ConsensusClientVersion = version
+			frame.ConsensusClientVersionKey = key
+
+			if err := f.indexer.UpdateFrameMetadata(ctx, frame); err != nil {
+				f.log.WithError(err).WithField("frame_id", frame.ID).Error("Failed to update frame metadata")
+
+				offset++
+
+				continue
+			}
+
+			updated++
+		}
+	}
+
+	f.log.Debugf("Updated consensus client version on %v frames", updated)
+
+	return nil
+}
+
 func (f *ForkChoice) BackfillEventSource(ctx context.Context) error {
 	// Get all frames that don't have an event source
 	empty := ""
@@ -169,3 +282,105 @@ func (f *ForkChoice) DeleteUselessLabels(ctx context.Context) error {
 
 	return nil
 }
+
+// BackfillReorgs converts a batch of label-encoded reorgs into reorg records and
+// deletes the labels. It returns the number of frames that were migrated.
+func (f *ForkChoice) BackfillReorgs(ctx context.Context) (int, error) {
+	frames, err := f.indexer.ListFrameMetadataWithLabelPrefix(ctx, legacyReorgLabelPrefix, &db.PaginationCursor{
+		Limit:   1000,
+		Offset:  0,
+		OrderBy: "fetched_at ASC",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	labelIDs := []uint{}
+
+	for _, frame := range frames {
+		values := map[string]string{}
+
+		for _, label := range frame.Labels {
+			name, value, ok := strings.Cut(label.Name, "=")
+			if !ok {
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(name, legacyReorgLabelPrefix):
+				values[strings.TrimPrefix(name, legacyReorgLabelPrefix)] = value
+			case name == legacyReorgTimingLabel:
+				values[legacyReorgTimingLabel] = value
+			default:
+				continue
+			}
+
+			labelIDs = append(labelIDs, label.ID)
+		}
+
+		reorg, err := reorgFromLegacyLabels(frame, values)
+		if err != nil {
+			// The labels are unusable so there's nothing to migrate, but we still
+			// delete them so the frame isn't picked up again.
+			f.log.WithError(err).WithField("frame_id", frame.ID).Warn("Failed to parse label-encoded reorg")
+
+			continue
+		}
+
+		if err := f.indexer.InsertReorg(ctx, reorg); err != nil {
+			return 0, errors.Wrap(err, "failed to insert reorg")
+		}
+	}
+
+	if len(labelIDs) > 0 {
+		if err := f.indexer.DeleteFrameMetadataLabels(ctx, labelIDs); err != nil {
+			return 0, errors.Wrap(err, "failed to delete label-encoded reorgs")
+		}
+	}
+
+	if len(frames) > 0 {
+		f.log.Infof("Migrated label-encoded reorgs on %v frames", len(frames))
+	}
+
+	return len(frames), nil
+}
+
+func reorgFromLegacyLabels(frame *db.FrameMetadata, values map[string]string) (*types.Reorg, error) {
+	slot, err := strconv.ParseUint(values["slot"], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid slot")
+	}
+
+	epoch, err := strconv.ParseUint(values["epoch"], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid epoch")
+	}
+
+	depth, err := strconv.ParseUint(values["depth"], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid depth")
+	}
+
+	reorg := &types.Reorg{
+		ID:              types.NewReorgID(frame.Node, phase0.Slot(slot), values["old_head_block"], values["new_head_block"]),
+		Network:         frame.Network,
+		Node:            frame.Node,
+		ConsensusClient: frame.ConsensusClient,
+		DetectedAt:      frame.FetchedAt,
+		Slot:            phase0.Slot(slot),
+		Epoch:           phase0.Epoch(epoch),
+		Depth:           depth,
+		OldHeadBlock:    values["old_head_block"],
+		OldHeadState:    values["old_head_state"],
+		NewHeadBlock:    values["new_head_block"],
+		NewHeadState:    values["new_head_state"],
+	}
+
+	if values[legacyReorgTimingLabel] == legacyReorgTimingBefore {
+		reorg.BeforeFrameID = frame.ID
+	} else {
+		reorg.AfterFrameID = frame.ID
+	}
+
+	return reorg, nil
+}
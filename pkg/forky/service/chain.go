@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/forkchoice"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+const (
+	// MaxChainReportSlots is the widest slot range a chain report can cover.
+	MaxChainReportSlots = 7200
+	// MaxChainReportNodes is the most nodes a chain report will compare.
+	MaxChainReportNodes = 1000
+	// MaxChainReportScanFrames is the most frames from within the range that
+	// are scanned for orphaned blocks, on top of each node's own frame.
+	MaxChainReportScanFrames = 100
+)
+
+// GetChainReport derives the canonical chain, missed slots and orphaned blocks
+// between two slots (inclusive) from the frames matching the filter.
+//
+// Each node's chain is taken from its first frame fetched at or after the end
+// of the range, which covers the whole range, or its latest frame within the
+// range if it has none. Orphaned blocks are also looked for in the most recent
+// frames fetched within the range.
+func (f *ForkChoice) GetChainReport(ctx context.Context, filter *FrameFilter, fromSlot, toSlot phase0.Slot) (*forkchoice.ChainReport, error) {
+	operation := OperationGetChainReport
+
+	f.metrics.ObserveOperation(operation)
+
+	if filter == nil {
+		filter = &FrameFilter{}
+	}
+
+	if toSlot < fromSlot || toSlot-fromSlot >= MaxChainReportSlots {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, fmt.Errorf("%w: the range must be at most %d slots", ErrInvalidSlotRange, MaxChainReportSlots)
+	}
+
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	from := uint64(fromSlot)
+	to := uint64(toSlot)
+
+	// Nodes with frames after the range are included, since those frames
+	// cover it too.
+	covering := *filter
+	covering.MinSlot = &from
+	covering.MaxSlot = nil
+
+	nodes, err := f.indexer.ListNodesWithFrames(ctx, covering.AsDBFilter(), &db.PaginationCursor{Limit: MaxChainReportNodes})
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).Error("failed to list nodes for chain report")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	ids := []string{}
+	seen := make(map[string]bool)
+
+	add := func(metadata []*db.FrameMetadata) {
+		for _, md := range metadata {
+			if !seen[md.ID] {
+				seen[md.ID] = true
+				ids = append(ids, md.ID)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		md, err := f.chainReportFrame(ctx, filter, node, from, to)
+		if err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			f.log.WithError(err).Error("failed to find frame for chain report")
+
+			return nil, ErrUnknownServerErrorOccurred
+		}
+
+		if md != nil {
+			add([]*db.FrameMetadata{md})
+		}
+	}
+
+	ranged := *filter
+	ranged.MinSlot = &from
+	ranged.MaxSlot = &to
+
+	scanned, err := f.indexer.ListFrameMetadata(ctx, ranged.AsDBFilter(), &db.PaginationCursor{
+		Limit:   MaxChainReportScanFrames,
+		OrderBy: "fetched_at DESC",
+	})
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).Error("failed to list metadata for chain report")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	add(scanned)
+
+	frames := make([]*types.Frame, 0, len(ids))
+
+	for _, id := range ids {
+		frame, err := f.store.GetFrame(ctx, id)
+		if err != nil {
+			// The index can briefly run ahead of the store, so skip frames
+			// that can't be loaded rather than failing the whole report.
+			f.log.WithError(err).WithField("id", id).Warn("failed to get frame for chain report")
+
+			continue
+		}
+
+		frames = append(frames, frame)
+	}
+
+	network := ""
+	if filter.Network != nil {
+		network = *filter.Network
+	}
+
+	return forkchoice.NewChainReport(frames, fromSlot, toSlot, f.slotsPerEpoch(network)), nil
+}
+
+// chainReportFrame returns the node's first frame fetched at or after the end of
+// the range, falling back to its latest frame within the range. It returns nil
+// if the node has neither.
+func (f *ForkChoice) chainReportFrame(ctx context.Context, filter *FrameFilter, node string, from, to uint64) (*db.FrameMetadata, error) {
+	after := *filter
+	after.Node = &node
+	after.MinSlot = &to
+	after.MaxSlot = nil
+
+	metadata, err := f.indexer.ListFrameMetadata(ctx, after.AsDBFilter(), &db.PaginationCursor{
+		Limit:   1,
+		OrderBy: "wall_clock_slot ASC, fetched_at ASC",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadata) > 0 {
+		return metadata[0], nil
+	}
+
+	within := *filter
+	within.Node = &node
+	within.MinSlot = &from
+	within.MaxSlot = &to
+
+	metadata, err = f.indexer.ListFrameMetadata(ctx, within.AsDBFilter(), &db.PaginationCursor{
+		Limit:   1,
+		OrderBy: "wall_clock_slot DESC, fetched_at DESC",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadata) > 0 {
+		return metadata[0], nil
+	}
+
+	return nil, nil
+}
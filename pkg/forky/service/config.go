@@ -1,7 +1,11 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/discovery"
 	"github.com/ethpandaops/forky/pkg/forky/ethereum"
 	"github.com/ethpandaops/forky/pkg/forky/human"
 	"github.com/ethpandaops/forky/pkg/forky/source"
@@ -11,6 +15,9 @@ import (
 type Config struct {
 	Sources []source.Config `yaml:"sources"`
 
+	// Discovery creates beacon_node sources from discovery backends.
+	Discovery []discovery.Config `yaml:"discovery"`
+
 	Store store.Config `yaml:"store"`
 
 	Indexer db.IndexerConfig `yaml:"indexer"`
@@ -18,4 +25,76 @@ type Config struct {
 	RetentionPeriod human.Duration `yaml:"retention_period" default:"24h"`
 
 	Ethereum ethereum.Config `yaml:"ethereum"`
+
+	// Networks are served alongside the Ethereum network, so that one instance
+	// can serve several networks. Sources pick their network by name.
+	Networks []NetworkConfig `yaml:"networks"`
+
+	ConsistencyCheck ConsistencyCheckConfig `yaml:"consistency_check"`
+
+	FinalityMonitor FinalityMonitorConfig `yaml:"finality_monitor"`
+}
+
+type NetworkConfig struct {
+	Ethereum ethereum.Config `yaml:"ethereum"`
+	// RetentionPeriod overrides the retention period for the network's frames.
+	// Defaults to the instance's retention period.
+	RetentionPeriod human.Duration `yaml:"retention_period"`
+}
+
+type ConsistencyCheckConfig struct {
+	// Enabled runs the consistency check periodically in the background.
+	Enabled bool `yaml:"enabled" default:"false"`
+	// Interval is how often the consistency check runs.
+	Interval human.Duration `yaml:"interval" default:"6h"`
+	// Repair fixes any inconsistencies that are found.
+	Repair bool `yaml:"repair" default:"false"`
+	// OrphanedFrames is the action to take on frames that exist in the store but not in the indexer.
+	OrphanedFrames OrphanedFrameAction `yaml:"orphaned_frames" default:"reindex"`
+}
+
+func (c *ConsistencyCheckConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval.Duration <= 0 {
+		return errors.New("consistency_check.interval must be greater than 0")
+	}
+
+	if !IsValidOrphanedFrameAction(c.OrphanedFrames) {
+		return fmt.Errorf("invalid consistency_check.orphaned_frames action: %s", c.OrphanedFrames)
+	}
+
+	return nil
+}
+
+type FinalityMonitorConfig struct {
+	// Enabled tracks how far each node's checkpoints trail the wall clock and
+	// the rest of its network.
+	Enabled bool `yaml:"enabled" default:"false"`
+	// Interval is how often nodes are compared with their network's majority.
+	Interval human.Duration `yaml:"interval" default:"1m"`
+	// Threshold is how many epochs a node's checkpoint can trail the majority
+	// before the node is considered to be lagging.
+	Threshold uint64 `yaml:"threshold" default:"1"`
+	// StaleAfter drops nodes that haven't had a frame for this long from the
+	// comparison.
+	StaleAfter human.Duration `yaml:"stale_after" default:"10m"`
+}
+
+func (c *FinalityMonitorConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Interval.Duration <= 0 {
+		return errors.New("finality_monitor.interval must be greater than 0")
+	}
+
+	if c.StaleAfter.Duration <= 0 {
+		return errors.New("finality_monitor.stale_after must be greater than 0")
+	}
+
+	return nil
 }
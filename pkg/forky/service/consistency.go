@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/store"
+	"github.com/sirupsen/logrus"
+)
+
+// OrphanedFrameAction is the action to take on a frame that exists in the store but not in the indexer.
+type OrphanedFrameAction string
+
+const (
+	OrphanedFrameActionNone    OrphanedFrameAction = "none"
+	OrphanedFrameActionReindex OrphanedFrameAction = "reindex"
+	OrphanedFrameActionDelete  OrphanedFrameAction = "delete"
+)
+
+func IsValidOrphanedFrameAction(action OrphanedFrameAction) bool {
+	switch action {
+	case OrphanedFrameActionNone, OrphanedFrameActionReindex, OrphanedFrameActionDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// ConsistencyReport describes the differences between the store and the indexer.
+type ConsistencyReport struct {
+	// StoredFrames is the number of frames found in the store.
+	StoredFrames int `json:"stored_frames"`
+	// IndexedFrames is the number of frames found in the indexer.
+	IndexedFrames int `json:"indexed_frames"`
+	// OrphanedFrames are frames that exist in the store but have no metadata in the indexer.
+	OrphanedFrames []string `json:"orphaned_frames"`
+	// OrphanedMetadata are metadata rows in the indexer that point at frames missing from the store.
+	OrphanedMetadata []string `json:"orphaned_metadata"`
+}
+
+// Consistent returns true if the store and the indexer agree.
+func (r *ConsistencyReport) Consistent() bool {
+	return len(r.OrphanedFrames) == 0 && len(r.OrphanedMetadata) == 0
+}
+
+// RepairOptions controls how inconsistencies are repaired.
+type RepairOptions struct {
+	// OrphanedFrames is the action to take on frames that exist in the store but not in the indexer.
+	OrphanedFrames OrphanedFrameAction
+	// DeleteOrphanedMetadata removes metadata that points at frames missing from the store.
+	DeleteOrphanedMetadata bool
+}
+
+// RepairResult summarises the outcome of a repair.
+type RepairResult struct {
+	ReindexedFrames int `json:"reindexed_frames"`
+	DeletedFrames   int `json:"deleted_frames"`
+	DeletedMetadata int `json:"deleted_metadata"`
+	Failed          int `json:"failed"`
+}
+
+// CheckConsistency compares the frames held in the store against the metadata held in the indexer.
+func (f *ForkChoice) CheckConsistency(ctx context.Context) (*ConsistencyReport, error) {
+	operation := OperationCheckConsistency
+
+	f.metrics.ObserveOperation(operation)
+
+	// The indexer is listed before the store. Frames are always written to the store
+	// before they're indexed so this order avoids most false positives from in-flight frames.
+	indexed, err := f.indexer.ListFrameIDs(ctx)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	stored, err := f.store.ListFrames(ctx)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	report := &ConsistencyReport{
+		StoredFrames:     len(stored),
+		IndexedFrames:    len(indexed),
+		OrphanedFrames:   []string{},
+		OrphanedMetadata: []string{},
+	}
+
+	indexedSet := make(map[string]struct{}, len(indexed))
+	for _, id := range indexed {
+		indexedSet[id] = struct{}{}
+	}
+
+	storedSet := make(map[string]struct{}, len(stored))
+	for _, id := range stored {
+		storedSet[id] = struct{}{}
+	}
+
+	for _, id := range stored {
+		if _, ok := indexedSet[id]; ok {
+			continue
+		}
+
+		// The frame may have been indexed since we listed the indexer.
+		indexedSince, err := f.isIndexed(ctx, id)
+		if err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			return nil, err
+		}
+
+		if !indexedSince {
+			report.OrphanedFrames = append(report.OrphanedFrames, id)
+		}
+	}
+
+	for _, id := range indexed {
+		if _, ok := storedSet[id]; ok {
+			continue
+		}
+
+		// The frame may have been purged since we listed the indexer.
+		indexedStill, err := f.isIndexed(ctx, id)
+		if err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			return nil, err
+		}
+
+		if indexedStill {
+			report.OrphanedMetadata = append(report.OrphanedMetadata, id)
+		}
+	}
+
+	sort.Strings(report.OrphanedFrames)
+	sort.Strings(report.OrphanedMetadata)
+
+	return report, nil
+}
+
+// RepairConsistency fixes the inconsistencies found by CheckConsistency.
+func (f *ForkChoice) RepairConsistency(ctx context.Context, report *ConsistencyReport, opts RepairOptions) (*RepairResult, error) {
+	operation := OperationRepairConsistency
+
+	f.metrics.ObserveOperation(operation)
+
+	if report == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, errors.New("report is nil")
+	}
+
+	result := &RepairResult{}
+
+	for _, id := range report.OrphanedFrames {
+		logCtx := f.log.WithField("frame_id", id).WithField("action", opts.OrphanedFrames)
+
+		switch opts.OrphanedFrames {
+		case OrphanedFrameActionReindex:
+			frame, err := f.store.GetFrame(ctx, id)
+			if err != nil {
+				logCtx.WithError(err).Error("Failed to fetch orphaned frame")
+
+				result.Failed++
+
+				continue
+			}
+
+			if frame.Metadata.ID != id {
+				logCtx.WithField("metadata_id", frame.Metadata.ID).Error("Orphaned frame has mismatched metadata ID")
+
+				result.Failed++
+
+				continue
+			}
+
+			if err := f.indexer.InsertFrameMetadata(ctx, &frame.Metadata); err != nil {
+				logCtx.WithError(err).Error("Failed to reindex orphaned frame")
+
+				result.Failed++
+
+				continue
+			}
+
+			result.ReindexedFrames++
+		case OrphanedFrameActionDelete:
+			if err := f.store.DeleteFrame(ctx, id); err != nil && !errors.Is(err, store.ErrFrameNotFound) {
+				logCtx.WithError(err).Error("Failed to delete orphaned frame")
+
+				result.Failed++
+
+				continue
+			}
+
+			result.DeletedFrames++
+		default:
+			continue
+		}
+
+		logCtx.Debug("Repaired orphaned frame")
+	}
+
+	if opts.DeleteOrphanedMetadata {
+		for _, id := range report.OrphanedMetadata {
+			if err := f.indexer.DeleteFrameMetadata(ctx, id); err != nil {
+				f.log.WithError(err).WithField("frame_id", id).Error("Failed to delete orphaned metadata")
+
+				result.Failed++
+
+				continue
+			}
+
+			result.DeletedMetadata++
+		}
+	}
+
+	if result.Failed > 0 {
+		f.metrics.ObserveOperationError(operation)
+	}
+
+	return result, nil
+}
+
+func (f *ForkChoice) isIndexed(ctx context.Context, id string) (bool, error) {
+	filter := &FrameFilter{}
+
+	dbFilter := filter.AsDBFilter()
+	dbFilter.AddID(id)
+
+	count, err := f.indexer.CountFrameMetadata(ctx, dbFilter)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (f *ForkChoice) runConsistencyCheck(ctx context.Context) error {
+	report, err := f.CheckConsistency(ctx)
+	if err != nil {
+		return err
+	}
+
+	logCtx := f.log.WithFields(logrus.Fields{
+		"stored_frames":     report.StoredFrames,
+		"indexed_frames":    report.IndexedFrames,
+		"orphaned_frames":   len(report.OrphanedFrames),
+		"orphaned_metadata": len(report.OrphanedMetadata),
+	})
+
+	if report.Consistent() {
+		logCtx.Debug("Store and indexer are consistent")
+
+		return nil
+	}
+
+	if !f.config.ConsistencyCheck.Repair {
+		logCtx.Warn("Store and indexer are inconsistent")
+
+		return nil
+	}
+
+	result, err := f.RepairConsistency(ctx, report, RepairOptions{
+		OrphanedFrames:         f.config.ConsistencyCheck.OrphanedFrames,
+		DeleteOrphanedMetadata: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	logCtx.WithFields(logrus.Fields{
+		"reindexed_frames": result.ReindexedFrames,
+		"deleted_frames":   result.DeletedFrames,
+		"deleted_metadata": result.DeletedMetadata,
+		"failed":           result.Failed,
+	}).Info("Repaired store and indexer inconsistencies")
+
+	return nil
+}
+
+func (f *ForkChoice) pollForInconsistencies(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(f.config.ConsistencyCheck.Interval.Duration):
+		case <-ctx.Done():
+			return
+		}
+
+		if err := f.runConsistencyCheck(ctx); err != nil {
+			f.log.WithError(err).Error("Failed to check store and indexer consistency")
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/discovery"
+	"github.com/ethpandaops/forky/pkg/forky/source"
+	"github.com/ethpandaops/forky/pkg/yaml"
+)
+
+// discoveredSources tracks the sources a discovery backend has created. It's
+// only accessed by the discovery's own poller.
+type discoveredSources struct {
+	discovery *discovery.Discovery
+
+	// sources maps the name of every source created by the discovery to the
+	// config it was created with.
+	sources map[string]string
+}
+
+func (f *ForkChoice) pollForDiscovery(ctx context.Context, d *discoveredSources) {
+	for {
+		if err := f.reconcileDiscovery(ctx, d); err != nil {
+			f.log.
+				WithError(err).
+				WithField("discovery", d.discovery.Name()).
+				Error("Failed to discover sources")
+		}
+
+		select {
+		case <-time.After(d.discovery.Interval()):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// reconcileDiscovery creates a beacon_node source for every discovered node,
+// replaces sources whose config has changed and removes sources whose node has
+// disappeared. Sources are left as they are if the backend can't be reached.
+func (f *ForkChoice) reconcileDiscovery(ctx context.Context, d *discoveredSources) error {
+	operation := OperationReconcileDiscovery
+
+	f.metrics.ObserveOperation(operation)
+
+	discovered, err := d.discovery.Discover(ctx)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return err
+	}
+
+	log := f.log.WithField("discovery", d.discovery.Name())
+
+	wanted := make(map[string]struct{}, len(discovered))
+
+	for _, s := range discovered {
+		wanted[s.Name] = struct{}{}
+
+		previous, owned := d.sources[s.Name]
+		if owned && previous == string(s.Config) {
+			continue
+		}
+
+		if !owned && f.hasSource(s.Name) {
+			log.
+				WithField("source", s.Name).
+				Warn("Discovered source has the same name as an existing source, ignoring")
+
+			continue
+		}
+
+		if _, err := f.UpsertSource(ctx, s.Name, source.BeaconNodeType, d.discovery.Network(), yaml.NewRawMessage(s.Config)); err != nil {
+			// Left untracked, or with its previous config, so it's retried on
+			// the next refresh.
+			log.WithError(err).WithField("source", s.Name).Error("Failed to create discovered source")
+
+			continue
+		}
+
+		d.sources[s.Name] = string(s.Config)
+	}
+
+	for name := range d.sources {
+		if _, ok := wanted[name]; ok {
+			continue
+		}
+
+		if err := f.RemoveSource(ctx, name); err != nil && !errors.Is(err, ErrSourceNotFound) {
+			log.WithError(err).WithField("source", name).Error("Failed to remove source that is no longer discovered")
+		}
+
+		delete(d.sources, name)
+	}
+
+	return nil
+}
+
+func (f *ForkChoice) hasSource(name string) bool {
+	f.sourcesMu.RLock()
+	defer f.sourcesMu.RUnlock()
+
+	_, ok := f.sources[name]
+
+	return ok
+}
@@ -7,4 +7,14 @@ var (
 	ErrInvalidFilter              = errors.New("invalid filter")
 	ErrUnknownServerErrorOccurred = errors.New("unknown server error occurred")
 	ErrFrameNotFound              = errors.New("frame not found")
+	ErrRawFrameNotFound           = errors.New("raw frame not found")
+	ErrReorgNotFound              = errors.New("reorg not found")
+	ErrSourceNotFound             = errors.New("source not found")
+	ErrInvalidSource              = errors.New("invalid source")
+	ErrNotStarted                 = errors.New("service has not been started")
+	ErrNetworkNotReady            = errors.New("network spec has not been discovered yet")
+	ErrNetworkNotFound            = errors.New("network not found")
+	ErrInvalidSlotRange           = errors.New("invalid slot range")
+	ErrTooManyFrames              = errors.New("too many frames")
+	ErrFinalityMonitorDisabled    = errors.New("finality monitor is disabled")
 )
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/sirupsen/logrus"
+)
+
+// maxFinalityLagEvents is how many of the most recent finality lag events are
+// kept.
+const maxFinalityLagEvents = 100
+
+// Checkpoint is a checkpoint tracked by the finality monitor.
+type Checkpoint string
+
+const (
+	CheckpointJustified Checkpoint = "justified"
+	CheckpointFinalized Checkpoint = "finalized"
+)
+
+var Checkpoints = []Checkpoint{
+	CheckpointJustified,
+	CheckpointFinalized,
+}
+
+// NodeFinality is the justification and finality of a node's latest frame.
+type NodeFinality struct {
+	Network        string       `json:"network"`
+	Node           string       `json:"node"`
+	FrameID        string       `json:"frame_id"`
+	ObservedAt     time.Time    `json:"observed_at"`
+	WallClockEpoch phase0.Epoch `json:"wall_clock_epoch"`
+	JustifiedEpoch phase0.Epoch `json:"justified_epoch"`
+	FinalizedEpoch phase0.Epoch `json:"finalized_epoch"`
+	// JustificationLag and FinalityLag are the number of epochs the
+	// checkpoints trail the wall clock.
+	JustificationLag uint64 `json:"justification_lag"`
+	FinalityLag      uint64 `json:"finality_lag"`
+	// JustificationLagging and FinalityLagging are true if the checkpoints
+	// trail the majority of the network as of the last check.
+	JustificationLagging bool `json:"justification_lagging"`
+	FinalityLagging      bool `json:"finality_lagging"`
+}
+
+// FinalityLagEvent is raised when a node starts or stops trailing the majority
+// of its network.
+type FinalityLagEvent struct {
+	Network       string       `json:"network"`
+	Node          string       `json:"node"`
+	Checkpoint    Checkpoint   `json:"checkpoint"`
+	NodeEpoch     phase0.Epoch `json:"node_epoch"`
+	MajorityEpoch phase0.Epoch `json:"majority_epoch"`
+	DetectedAt    time.Time    `json:"detected_at"`
+	// Recovered is true if the node has caught back up with the majority.
+	Recovered bool `json:"recovered"`
+}
+
+type finalityKey struct {
+	network string
+	node    string
+}
+
+type finalityMonitor struct {
+	config  *FinalityMonitorConfig
+	log     logrus.FieldLogger
+	metrics *Metrics
+
+	mu     sync.Mutex
+	nodes  map[finalityKey]*NodeFinality
+	events []FinalityLagEvent
+}
+
+func newFinalityMonitor(config *FinalityMonitorConfig, log logrus.FieldLogger, metrics *Metrics) *finalityMonitor {
+	return &finalityMonitor{
+		config:  config,
+		log:     log.WithField("component", "finality_monitor"),
+		metrics: metrics,
+		nodes:   make(map[finalityKey]*NodeFinality),
+		events:  []FinalityLagEvent{},
+	}
+}
+
+// observe records the checkpoints of a frame if it's the node's latest.
+func (m *finalityMonitor) observe(frame *types.Frame) {
+	if frame.Data == nil {
+		return
+	}
+
+	md := frame.Metadata
+	key := finalityKey{network: md.Network, node: md.Node}
+
+	status := &NodeFinality{
+		Network:          md.Network,
+		Node:             md.Node,
+		FrameID:          md.ID,
+		ObservedAt:       md.FetchedAt,
+		WallClockEpoch:   md.WallClockEpoch,
+		JustifiedEpoch:   frame.Data.JustifiedCheckpoint.Epoch,
+		FinalizedEpoch:   frame.Data.FinalizedCheckpoint.Epoch,
+		JustificationLag: epochsBehind(md.WallClockEpoch, frame.Data.JustifiedCheckpoint.Epoch),
+		FinalityLag:      epochsBehind(md.WallClockEpoch, frame.Data.FinalizedCheckpoint.Epoch),
+	}
+
+	m.metrics.ObserveFrameFinalityLag(status)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Frames can arrive out of order, e.g. when replaying files, and stale
+	// frames mustn't overwrite the node's lag.
+	if current, exists := m.nodes[key]; exists {
+		if current.WallClockEpoch > status.WallClockEpoch {
+			return
+		}
+
+		status.JustificationLagging = current.JustificationLagging
+		status.FinalityLagging = current.FinalityLagging
+	}
+
+	m.nodes[key] = status
+
+	// The gauges are updated while holding the lock so that they can't race
+	// with check deleting the node's series.
+	m.metrics.ObserveNodeFinalityLag(status)
+}
+
+// check compares every node with the majority of its network, raising events
+// for nodes that start or stop lagging.
+func (m *finalityMonitor) check(now time.Time) []FinalityLagEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	networks := make(map[string][]*NodeFinality)
+
+	for key, status := range m.nodes {
+		if now.Sub(status.ObservedAt) > m.config.StaleAfter.Duration {
+			delete(m.nodes, key)
+			m.metrics.DeleteFinalityLag(key.network, key.node)
+
+			continue
+		}
+
+		networks[key.network] = append(networks[key.network], status)
+	}
+
+	events := []FinalityLagEvent{}
+
+	for _, nodes := range networks {
+		justified := make([]phase0.Epoch, 0, len(nodes))
+		finalized := make([]phase0.Epoch, 0, len(nodes))
+
+		for _, status := range nodes {
+			justified = append(justified, status.JustifiedEpoch)
+			finalized = append(finalized, status.FinalizedEpoch)
+		}
+
+		majorityJustified := majorityEpoch(justified)
+		majorityFinalized := majorityEpoch(finalized)
+
+		for _, status := range nodes {
+			if event := m.compare(status, CheckpointJustified, status.JustifiedEpoch, majorityJustified, &status.JustificationLagging, now); event != nil {
+				events = append(events, *event)
+			}
+
+			if event := m.compare(status, CheckpointFinalized, status.FinalizedEpoch, majorityFinalized, &status.FinalityLagging, now); event != nil {
+				events = append(events, *event)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Network != events[j].Network {
+			return events[i].Network < events[j].Network
+		}
+
+		if events[i].Node != events[j].Node {
+			return events[i].Node < events[j].Node
+		}
+
+		return events[i].Checkpoint > events[j].Checkpoint
+	})
+
+	m.events = append(m.events, events...)
+	if len(m.events) > maxFinalityLagEvents {
+		m.events = m.events[len(m.events)-maxFinalityLagEvents:]
+	}
+
+	return events
+}
+
+// compare updates whether a node's checkpoint is lagging, returning an event if
+// that changed.
+func (m *finalityMonitor) compare(status *NodeFinality, checkpoint Checkpoint, epoch, majority phase0.Epoch, lagging *bool, now time.Time) *FinalityLagEvent {
+	isLagging := epochsBehind(majority, epoch) > m.config.Threshold
+
+	m.metrics.ObserveNodeLagging(status.Network, status.Node, checkpoint, isLagging)
+
+	if isLagging == *lagging {
+		return nil
+	}
+
+	*lagging = isLagging
+
+	event := &FinalityLagEvent{
+		Network:       status.Network,
+		Node:          status.Node,
+		Checkpoint:    checkpoint,
+		NodeEpoch:     epoch,
+		MajorityEpoch: majority,
+		DetectedAt:    now,
+		Recovered:     !isLagging,
+	}
+
+	m.metrics.ObserveFinalityLagEvent(event)
+
+	logCtx := m.log.WithFields(logrus.Fields{
+		"network":        status.Network,
+		"node":           status.Node,
+		"checkpoint":     checkpoint,
+		"node_epoch":     epoch,
+		"majority_epoch": majority,
+	})
+
+	if isLagging {
+		logCtx.Warn("Node is lagging the network majority")
+	} else {
+		logCtx.Info("Node has caught up with the network majority")
+	}
+
+	return event
+}
+
+// status returns the latest status of each node, optionally only on a network.
+func (m *finalityMonitor) status(network *string) ([]*NodeFinality, []FinalityLagEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nodes := make([]*NodeFinality, 0, len(m.nodes))
+
+	for key, status := range m.nodes {
+		if network != nil && key.network != *network {
+			continue
+		}
+
+		copied := *status
+		nodes = append(nodes, &copied)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Network != nodes[j].Network {
+			return nodes[i].Network < nodes[j].Network
+		}
+
+		return nodes[i].Node < nodes[j].Node
+	})
+
+	events := make([]FinalityLagEvent, 0, len(m.events))
+
+	for _, event := range m.events {
+		if network != nil && event.Network != *network {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return nodes, events
+}
+
+// majorityEpoch returns the most common epoch, preferring the latest on a tie.
+func majorityEpoch(epochs []phase0.Epoch) phase0.Epoch {
+	counts := make(map[phase0.Epoch]int, len(epochs))
+
+	var (
+		majority phase0.Epoch
+		most     int
+	)
+
+	for _, epoch := range epochs {
+		counts[epoch]++
+
+		if counts[epoch] > most || (counts[epoch] == most && epoch > majority) {
+			majority = epoch
+			most = counts[epoch]
+		}
+	}
+
+	return majority
+}
+
+func epochsBehind(ahead, behind phase0.Epoch) uint64 {
+	if behind >= ahead {
+		return 0
+	}
+
+	return uint64(ahead - behind)
+}
+
+// CheckFinalityLag compares each node's checkpoints with the majority of its
+// network, returning the events raised for nodes that started or stopped
+// lagging.
+func (f *ForkChoice) CheckFinalityLag(_ context.Context) ([]FinalityLagEvent, error) {
+	operation := OperationCheckFinalityLag
+
+	f.metrics.ObserveOperation(operation)
+
+	if f.finality == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, ErrFinalityMonitorDisabled
+	}
+
+	return f.finality.check(time.Now()), nil
+}
+
+// GetFinalityStatus returns the justification and finality of each node along
+// with recent lag events. All networks are included unless one is given.
+func (f *ForkChoice) GetFinalityStatus(_ context.Context, network string) ([]*NodeFinality, []FinalityLagEvent, error) {
+	operation := OperationGetFinalityStatus
+
+	f.metrics.ObserveOperation(operation)
+
+	if f.finality == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, ErrFinalityMonitorDisabled
+	}
+
+	var filter *string
+
+	if network != "" {
+		if _, err := f.network(network); err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			return nil, nil, err
+		}
+
+		filter = &network
+	}
+
+	nodes, events := f.finality.status(filter)
+
+	return nodes, events, nil
+}
+
+func (f *ForkChoice) pollForFinalityLag(ctx context.Context) {
+	for {
+		select {
+		case <-time.After(f.config.FinalityMonitor.Interval.Duration):
+		case <-ctx.Done():
+			return
+		}
+
+		if _, err := f.CheckFinalityLag(ctx); err != nil {
+			f.log.WithError(err).Error("Failed to check finality lag")
+		}
+	}
+}
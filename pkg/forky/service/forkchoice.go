@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
 )
 
 type SourceMetadata struct {
@@ -17,10 +19,18 @@ type FrameFilter struct {
 	Before          *time.Time `json:"before"`
 	After           *time.Time `json:"after"`
 	Slot            *uint64    `json:"slot"`
+	MinSlot         *uint64    `json:"min_slot"`
+	MaxSlot         *uint64    `json:"max_slot"`
 	Epoch           *uint64    `json:"epoch"`
 	Labels          *[]string  `json:"labels"`
 	ConsensusClient *string    `json:"consensus_client"`
 	EventSource     *string    `json:"event_source"`
+	Network         *string    `json:"network"`
+	// ConsensusClientVersion matches a release exactly, e.g. "5.1.0", while
+	// the min and max versions are inclusive bounds on the release.
+	ConsensusClientVersion    *string `json:"consensus_client_version"`
+	MinConsensusClientVersion *string `json:"min_consensus_client_version"`
+	MaxConsensusClientVersion *string `json:"max_consensus_client_version"`
 }
 
 func (f *FrameFilter) Validate() error {
@@ -28,25 +38,54 @@ func (f *FrameFilter) Validate() error {
 		f.Before == nil &&
 		f.After == nil &&
 		f.Slot == nil &&
+		f.MinSlot == nil &&
+		f.MaxSlot == nil &&
 		f.Epoch == nil &&
 		f.Labels == nil &&
 		f.ConsensusClient == nil &&
-		f.EventSource == nil {
+		f.EventSource == nil &&
+		f.Network == nil &&
+		f.ConsensusClientVersion == nil &&
+		f.MinConsensusClientVersion == nil &&
+		f.MaxConsensusClientVersion == nil {
 		return errors.New("no filter specified")
 	}
 
 	return nil
 }
 
+// ValidateVersions returns ErrInvalidFilter if the version range bounds can't
+// be parsed.
+func (f *FrameFilter) ValidateVersions() error {
+	for _, version := range []*string{f.MinConsensusClientVersion, f.MaxConsensusClientVersion} {
+		if version == nil {
+			continue
+		}
+
+		if _, err := ethereum.VersionSortKey(*version); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+		}
+	}
+
+	return nil
+}
+
 func (f *FrameFilter) AsDBFilter() *db.FrameFilter {
 	filter := &db.FrameFilter{
 		Node:            f.Node,
 		Before:          f.Before,
 		After:           f.After,
 		Slot:            f.Slot,
+		MinSlot:         f.MinSlot,
+		MaxSlot:         f.MaxSlot,
 		Epoch:           f.Epoch,
 		Labels:          f.Labels,
 		ConsensusClient: f.ConsensusClient,
+		Network:         f.Network,
+
+		ConsensusClientVersion:    f.ConsensusClientVersion,
+		MinConsensusClientVersion: f.MinConsensusClientVersion,
+		MaxConsensusClientVersion: f.MaxConsensusClientVersion,
 	}
 
 	if f.EventSource != nil {
@@ -3,10 +3,15 @@ package service
 import (
 	"runtime"
 
+	"github.com/ethpandaops/forky/pkg/forky/source"
 	"github.com/ethpandaops/forky/pkg/version"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// finalityLagBuckets cover healthy networks, where checkpoints trail the wall
+// clock by one or two epochs, through to long periods of non-finality.
+var finalityLagBuckets = []float64{0, 1, 2, 3, 4, 6, 8, 16, 32, 64, 128, 256}
+
 type Metrics struct {
 	namespace string
 
@@ -15,6 +20,18 @@ type Metrics struct {
 	retentionPeriod  prometheus.Gauge
 	operations       *prometheus.CounterVec
 	operationsErrors *prometheus.CounterVec
+
+	sourceState               *prometheus.GaugeVec
+	sourceConsecutiveFailures *prometheus.GaugeVec
+	sourceLastSuccess         *prometheus.GaugeVec
+	sourceFramesEmitted       *prometheus.GaugeVec
+
+	nodeJustificationLag *prometheus.GaugeVec
+	nodeFinalityLag      *prometheus.GaugeVec
+	justificationLag     *prometheus.HistogramVec
+	finalityLag          *prometheus.HistogramVec
+	nodeLagging          *prometheus.GaugeVec
+	finalityLagEvents    *prometheus.CounterVec
 }
 
 func NewMetrics(namespace string, config *Config, enabled bool) *Metrics {
@@ -49,6 +66,60 @@ func NewMetrics(namespace string, config *Config, enabled bool) *Metrics {
 			Name:      "operations_errors_count",
 			Help:      "The count of operations performed by the db that resulted in an error",
 		}, []string{"operation"}),
+
+		sourceState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "source_state",
+			Help:      "The state of each source. 1 for the source's current state, 0 otherwise",
+		}, []string{"source_name", "source_type", "state"}),
+		sourceConsecutiveFailures: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "source_consecutive_failures",
+			Help:      "The number of consecutive failures of each source",
+		}, []string{"source_name", "source_type"}),
+		sourceLastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "source_last_success_timestamp_seconds",
+			Help:      "The unix timestamp of the last success of each source",
+		}, []string{"source_name", "source_type"}),
+		sourceFramesEmitted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "source_frames_emitted",
+			Help:      "The number of frames emitted by each source since it started",
+		}, []string{"source_name", "source_type"}),
+
+		nodeJustificationLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_justification_lag_epochs",
+			Help:      "The number of epochs between the wall clock and the justified checkpoint of each node's latest frame",
+		}, []string{"network", "node"}),
+		nodeFinalityLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_finality_lag_epochs",
+			Help:      "The number of epochs between the wall clock and the finalized checkpoint of each node's latest frame",
+		}, []string{"network", "node"}),
+		justificationLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "justification_lag_epochs",
+			Help:      "The number of epochs between the wall clock and the justified checkpoint of each frame",
+			Buckets:   finalityLagBuckets,
+		}, []string{"network"}),
+		finalityLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "finality_lag_epochs",
+			Help:      "The number of epochs between the wall clock and the finalized checkpoint of each frame",
+			Buckets:   finalityLagBuckets,
+		}, []string{"network"}),
+		nodeLagging: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "node_lagging_majority",
+			Help:      "Whether each node's checkpoint trails the majority of its network. 1 if it does, 0 otherwise",
+		}, []string{"network", "node", "checkpoint"}),
+		finalityLagEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "finality_lag_events_count",
+			Help:      "The count of nodes starting to trail the majority of their network",
+		}, []string{"network", "checkpoint"}),
 	}
 
 	if enabled {
@@ -57,6 +128,16 @@ func NewMetrics(namespace string, config *Config, enabled bool) *Metrics {
 		prometheus.MustRegister(m.operationsErrors)
 		prometheus.MustRegister(m.info)
 		prometheus.MustRegister(m.versionInfo)
+		prometheus.MustRegister(m.sourceState)
+		prometheus.MustRegister(m.sourceConsecutiveFailures)
+		prometheus.MustRegister(m.sourceLastSuccess)
+		prometheus.MustRegister(m.sourceFramesEmitted)
+		prometheus.MustRegister(m.nodeJustificationLag)
+		prometheus.MustRegister(m.nodeFinalityLag)
+		prometheus.MustRegister(m.justificationLag)
+		prometheus.MustRegister(m.finalityLag)
+		prometheus.MustRegister(m.nodeLagging)
+		prometheus.MustRegister(m.finalityLagEvents)
 	}
 
 	m.retentionPeriod.Set(config.RetentionPeriod.Duration.Seconds())
@@ -80,3 +161,70 @@ func (m *Metrics) ObserveOperation(operation Operation) {
 func (m *Metrics) ObserveOperationError(operation Operation) {
 	m.operationsErrors.WithLabelValues(string(operation)).Inc()
 }
+
+func (m *Metrics) ObserveSourceStatus(status *source.Status) {
+	for _, state := range source.States {
+		value := 0.0
+		if state == status.State {
+			value = 1
+		}
+
+		m.sourceState.WithLabelValues(status.Name, status.Type, string(state)).Set(value)
+	}
+
+	m.sourceConsecutiveFailures.WithLabelValues(status.Name, status.Type).Set(float64(status.ConsecutiveFailures))
+	m.sourceFramesEmitted.WithLabelValues(status.Name, status.Type).Set(float64(status.FramesEmitted))
+
+	if status.LastSuccessAt != nil {
+		m.sourceLastSuccess.WithLabelValues(status.Name, status.Type).Set(float64(status.LastSuccessAt.Unix()))
+	}
+}
+
+func (m *Metrics) DeleteSourceStatus(name, sourceType string) {
+	for _, state := range source.States {
+		m.sourceState.DeleteLabelValues(name, sourceType, string(state))
+	}
+
+	m.sourceConsecutiveFailures.DeleteLabelValues(name, sourceType)
+	m.sourceFramesEmitted.DeleteLabelValues(name, sourceType)
+	m.sourceLastSuccess.DeleteLabelValues(name, sourceType)
+}
+
+// ObserveFrameFinalityLag records the lag of every frame, whether or not it's
+// the node's latest.
+func (m *Metrics) ObserveFrameFinalityLag(status *NodeFinality) {
+	m.justificationLag.WithLabelValues(status.Network).Observe(float64(status.JustificationLag))
+	m.finalityLag.WithLabelValues(status.Network).Observe(float64(status.FinalityLag))
+}
+
+// ObserveNodeFinalityLag records the lag of a node's latest frame.
+func (m *Metrics) ObserveNodeFinalityLag(status *NodeFinality) {
+	m.nodeJustificationLag.WithLabelValues(status.Network, status.Node).Set(float64(status.JustificationLag))
+	m.nodeFinalityLag.WithLabelValues(status.Network, status.Node).Set(float64(status.FinalityLag))
+}
+
+func (m *Metrics) ObserveNodeLagging(network, node string, checkpoint Checkpoint, lagging bool) {
+	value := 0.0
+	if lagging {
+		value = 1
+	}
+
+	m.nodeLagging.WithLabelValues(network, node, string(checkpoint)).Set(value)
+}
+
+func (m *Metrics) ObserveFinalityLagEvent(event *FinalityLagEvent) {
+	if event.Recovered {
+		return
+	}
+
+	m.finalityLagEvents.WithLabelValues(event.Network, string(event.Checkpoint)).Inc()
+}
+
+func (m *Metrics) DeleteFinalityLag(network, node string) {
+	m.nodeJustificationLag.DeleteLabelValues(network, node)
+	m.nodeFinalityLag.DeleteLabelValues(network, node)
+
+	for _, checkpoint := range Checkpoints {
+		m.nodeLagging.DeleteLabelValues(network, node, string(checkpoint))
+	}
+}
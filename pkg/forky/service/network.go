@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
+	"github.com/ethpandaops/forky/pkg/forky/source"
+	"github.com/sirupsen/logrus"
+)
+
+// network is an Ethereum network served by the instance.
+type network struct {
+	name string
+	eth  *ethereum.BeaconChain
+	// retention overrides how long the network's frames are kept for, if set.
+	retention time.Duration
+	// sourceOpts are the options the network's sources are created with.
+	sourceOpts *source.Options
+	// labelFramesWithFork labels the network's new frames with their fork.
+	labelFramesWithFork bool
+}
+
+func newNetwork(log logrus.FieldLogger, config *ethereum.Config, retention time.Duration, metricsEnabled bool) (*network, error) {
+	eth, err := ethereum.NewBeaconChain(log.WithField("network", config.Network.Name), config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &network{
+		name:                config.Network.Name,
+		eth:                 eth,
+		retention:           retention,
+		labelFramesWithFork: config.LabelFramesWithFork,
+		sourceOpts: source.
+			DefaultOptions().
+			SetMetricsEnabled(metricsEnabled).
+			WithAllowedEthereumNetworks([]string{config.Network.Name}).
+			WithNetworkVerifier(eth.VerifyNetwork).
+			WithSpec(eth.Spec),
+	}, nil
+}
+
+// newNetworks creates the default network and any additional networks, in
+// config order.
+func newNetworks(log logrus.FieldLogger, config *Config, metricsEnabled bool) (map[string]*network, []string, error) {
+	networks := make(map[string]*network, len(config.Networks)+1)
+	names := make([]string, 0, len(config.Networks)+1)
+
+	add := func(ethConfig *ethereum.Config, retention time.Duration) error {
+		n, err := newNetwork(log, ethConfig, retention, metricsEnabled)
+		if err != nil {
+			return fmt.Errorf("failed to create network %s: %w", ethConfig.Network.Name, err)
+		}
+
+		if _, ok := networks[n.name]; ok {
+			return fmt.Errorf("duplicate network name: %s", n.name)
+		}
+
+		networks[n.name] = n
+		names = append(names, n.name)
+
+		return nil
+	}
+
+	if err := add(&config.Ethereum, 0); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range config.Networks {
+		if err := add(&config.Networks[i].Ethereum, config.Networks[i].RetentionPeriod.Duration); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return networks, names, nil
+}
+
+// network returns the network with the name, or the default network if the
+// name is empty.
+func (f *ForkChoice) network(name string) (*network, error) {
+	return resolveNetwork(f.networks, f.networkNames, name)
+}
+
+// slotsPerEpoch returns the epoch length of the network, or zero if the
+// network isn't served by the instance.
+func (f *ForkChoice) slotsPerEpoch(name string) uint64 {
+	n, err := f.network(name)
+	if err != nil {
+		return 0
+	}
+
+	return n.eth.Spec().SlotsPerEpoch
+}
+
+func resolveNetwork(networks map[string]*network, names []string, name string) (*network, error) {
+	if name == "" {
+		name = names[0]
+	}
+
+	n, ok := networks[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNetworkNotFound, name)
+	}
+
+	return n, nil
+}
+
+// ListEthereumNetworks returns the names of every network, default network first.
+func (f *ForkChoice) ListEthereumNetworks(_ context.Context) []string {
+	operation := OperationListEthereumNetworks
+
+	f.metrics.ObserveOperation(operation)
+
+	return append([]string{}, f.networkNames...)
+}
+
+// retentionPeriod returns how long the network's frames are kept for.
+func (f *ForkChoice) retentionPeriod(n *network) time.Duration {
+	if n.retention > 0 {
+		return n.retention
+	}
+
+	return f.config.RetentionPeriod.Duration
+}
+
+// maxRetentionPeriod returns the longest retention period of any network.
+func (f *ForkChoice) maxRetentionPeriod() time.Duration {
+	retention := f.config.RetentionPeriod.Duration
+
+	for _, n := range f.networks {
+		retention = max(retention, f.retentionPeriod(n))
+	}
+
+	return retention
+}
@@ -6,6 +6,11 @@ const (
 	OperationAddFrame    Operation = "add_frame"
 	OperationGetFrame    Operation = "get_frame"
 	OperationDeleteFrame Operation = "delete_frame"
+	OperationGetRawFrame Operation = "get_raw_frame"
+
+	OperationGetFrameAnalysis Operation = "get_frame_analysis"
+	OperationGetChainReport   Operation = "get_chain_report"
+	OperationGetBlockWeights  Operation = "get_block_weights"
 
 	OperationListMetadata   Operation = "list_metadata"
 	OperationUpdateMetadata Operation = "update_metadata"
@@ -15,7 +20,28 @@ const (
 	OperationListEpochs Operation = "list_epochs"
 	OperationListLabels Operation = "list_labels"
 
+	OperationCheckConsistency  Operation = "check_consistency"
+	OperationRepairConsistency Operation = "repair_consistency"
+	OperationReindex           Operation = "reindex"
+
+	OperationCheckFinalityLag  Operation = "check_finality_lag"
+	OperationGetFinalityStatus Operation = "get_finality_status"
+
+	OperationAddReorg   Operation = "add_reorg"
+	OperationListReorgs Operation = "list_reorgs"
+	OperationGetReorg   Operation = "get_reorg"
+
+	OperationListSources  Operation = "list_sources"
+	OperationUpsertSource Operation = "upsert_source"
+	OperationRemoveSource Operation = "remove_source"
+
+	OperationReconcileDiscovery Operation = "reconcile_discovery"
+
+	OperationExportFrames Operation = "export_frames"
+	OperationImportFrames Operation = "import_frames"
+
 	OperationGetEthereumNow         Operation = "get_ethereum_now"
 	OperationGetEthereumSpec        Operation = "get_ethereum_spec"
 	OperationGetEthereumNetworkName Operation = "get_ethereum_network_name"
+	OperationListEthereumNetworks   Operation = "list_ethereum_networks"
 )
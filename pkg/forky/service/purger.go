@@ -8,12 +8,25 @@ import (
 )
 
 func (f *ForkChoice) DeleteOldFrames(ctx context.Context) error {
+	// Each network has its own retention period.
+	for _, name := range f.networkNames {
+		if err := f.deleteOldNetworkFrames(ctx, name, f.retentionPeriod(f.networks[name])); err != nil {
+			return err
+		}
+	}
+
+	// Frames from before networks were recorded belong to the default network.
+	return f.deleteOldNetworkFrames(ctx, "", f.retentionPeriod(f.networks[f.networkNames[0]]))
+}
+
+func (f *ForkChoice) deleteOldNetworkFrames(ctx context.Context, network string, retention time.Duration) error {
 	// Get all frames that are outside of the retention period
 	// and delete them.
-	before := time.Now().Add(-f.config.RetentionPeriod.Duration)
+	before := time.Now().Add(-retention)
 
 	filter := &FrameFilter{
-		Before: &before,
+		Before:  &before,
+		Network: &network,
 	}
 
 	frames, err := f.indexer.ListFrameMetadata(ctx, filter.AsDBFilter(), &db.PaginationCursor{
@@ -33,7 +46,21 @@ func (f *ForkChoice) DeleteOldFrames(ctx context.Context) error {
 		}
 	}
 
-	f.log.Debugf("Deleted %v old frames", len(frames))
+	f.log.WithField("network", network).Debugf("Deleted %v old frames", len(frames))
+
+	return nil
+}
+
+func (f *ForkChoice) DeleteOldReorgs(ctx context.Context) error {
+	// Reorgs aren't tied to a network, so are kept for as long as any frames are.
+	before := time.Now().Add(-f.maxRetentionPeriod())
+
+	deleted, err := f.indexer.DeleteReorgsBefore(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	f.log.Debugf("Deleted %v old reorgs", deleted)
 
 	return nil
 }
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+// ReindexOptions controls how the indexer is rebuilt from the store.
+type ReindexOptions struct {
+	// Workers is the number of frames fetched from the store concurrently.
+	Workers int
+	// BatchSize is the number of frames inserted into the indexer at once.
+	BatchSize int
+	// Progress is called after every batch has been written to the indexer.
+	Progress func(progress ReindexProgress)
+}
+
+func DefaultReindexOptions() ReindexOptions {
+	return ReindexOptions{
+		Workers:   8,
+		BatchSize: 500,
+	}
+}
+
+func (o *ReindexOptions) Validate() error {
+	if o.Workers < 1 {
+		return errors.New("workers must be greater than 0")
+	}
+
+	if o.BatchSize < 1 {
+		return errors.New("batch size must be greater than 0")
+	}
+
+	return nil
+}
+
+// ReindexProgress describes how far through a reindex we are.
+type ReindexProgress struct {
+	// Total is the number of frames found in the store.
+	Total int `json:"total"`
+	// Skipped is the number of frames that were already indexed.
+	Skipped int `json:"skipped"`
+	// Indexed is the number of frames that have been indexed.
+	Indexed int `json:"indexed"`
+	// Failed is the number of frames that could not be indexed.
+	Failed int `json:"failed"`
+}
+
+// Remaining returns the number of frames still to be processed.
+func (p ReindexProgress) Remaining() int {
+	return p.Total - p.Skipped - p.Indexed - p.Failed
+}
+
+// Reindex rebuilds the indexer from the frames held in the store. Frames that are
+// already indexed are skipped, so an interrupted reindex can simply be run again.
+func (f *ForkChoice) Reindex(ctx context.Context, opts ReindexOptions) (*ReindexProgress, error) {
+	operation := OperationReindex
+
+	f.metrics.ObserveOperation(operation)
+
+	if err := opts.Validate(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	stored, err := f.store.ListFrames(ctx)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	indexed, err := f.indexer.ListFrameIDs(ctx)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	indexedSet := make(map[string]struct{}, len(indexed))
+	for _, id := range indexed {
+		indexedSet[id] = struct{}{}
+	}
+
+	pending := []string{}
+
+	for _, id := range stored {
+		if _, ok := indexedSet[id]; !ok {
+			pending = append(pending, id)
+		}
+	}
+
+	sort.Strings(pending)
+
+	progress := ReindexProgress{
+		Total:   len(stored),
+		Skipped: len(stored) - len(pending),
+	}
+
+	f.log.
+		WithField("total", progress.Total).
+		WithField("skipped", progress.Skipped).
+		WithField("workers", opts.Workers).
+		Info("Reindexing frames from store")
+
+	var failed atomic.Int64
+
+	ids := make(chan string)
+	metadatas := make(chan *types.FrameMetadata, opts.BatchSize)
+
+	go func() {
+		defer close(ids)
+
+		for _, id := range pending {
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for id := range ids {
+				frame, err := f.store.GetFrame(ctx, id)
+				if err != nil {
+					f.log.WithError(err).WithField("frame_id", id).Error("Failed to fetch frame for reindex")
+
+					failed.Add(1)
+
+					continue
+				}
+
+				if frame.Metadata.ID != id {
+					f.log.WithField("frame_id", id).WithField("metadata_id", frame.Metadata.ID).Error("Frame has mismatched metadata ID")
+
+					failed.Add(1)
+
+					continue
+				}
+
+				metadatas <- &frame.Metadata
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+
+		close(metadatas)
+	}()
+
+	batch := make([]*types.FrameMetadata, 0, opts.BatchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		indexedCount := f.insertReindexBatch(ctx, batch)
+
+		progress.Indexed += indexedCount
+		failed.Add(int64(len(batch) - indexedCount))
+		progress.Failed = int(failed.Load())
+
+		batch = batch[:0]
+
+		if opts.Progress != nil {
+			opts.Progress(progress)
+		}
+	}
+
+	for metadata := range metadatas {
+		batch = append(batch, metadata)
+
+		if len(batch) >= opts.BatchSize {
+			flush()
+		}
+	}
+
+	flush()
+
+	progress.Failed = int(failed.Load())
+
+	if err := ctx.Err(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return &progress, err
+	}
+
+	if progress.Failed > 0 {
+		f.metrics.ObserveOperationError(operation)
+	}
+
+	return &progress, nil
+}
+
+// insertReindexBatch inserts a batch of metadata, falling back to one-by-one inserts
+// if the batch fails so a single bad frame doesn't fail the whole batch.
+func (f *ForkChoice) insertReindexBatch(ctx context.Context, batch []*types.FrameMetadata) int {
+	if err := f.indexer.InsertFrameMetadatas(ctx, batch); err == nil {
+		return len(batch)
+	}
+
+	inserted := 0
+
+	for _, metadata := range batch {
+		if err := f.indexer.InsertFrameMetadata(ctx, metadata); err != nil {
+			f.log.WithError(err).WithField("frame_id", metadata.ID).Error("Failed to reindex frame")
+
+			continue
+		}
+
+		inserted++
+	}
+
+	return inserted
+}
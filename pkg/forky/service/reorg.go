@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/sirupsen/logrus"
+)
+
+type ReorgFilter struct {
+	Network         *string    `json:"network"`
+	Node            *string    `json:"node"`
+	Before          *time.Time `json:"before"`
+	After           *time.Time `json:"after"`
+	Slot            *uint64    `json:"slot"`
+	MinSlot         *uint64    `json:"min_slot"`
+	MaxSlot         *uint64    `json:"max_slot"`
+	Epoch           *uint64    `json:"epoch"`
+	MinDepth        *uint64    `json:"min_depth"`
+	OldHeadBlock    *string    `json:"old_head_block"`
+	NewHeadBlock    *string    `json:"new_head_block"`
+	ConsensusClient *string    `json:"consensus_client"`
+}
+
+func (f *ReorgFilter) AsDBFilter() *db.ReorgFilter {
+	return &db.ReorgFilter{
+		Network:         f.Network,
+		Node:            f.Node,
+		Before:          f.Before,
+		After:           f.After,
+		Slot:            f.Slot,
+		MinSlot:         f.MinSlot,
+		MaxSlot:         f.MaxSlot,
+		Epoch:           f.Epoch,
+		MinDepth:        f.MinDepth,
+		OldHeadBlock:    f.OldHeadBlock,
+		NewHeadBlock:    f.NewHeadBlock,
+		ConsensusClient: f.ConsensusClient,
+	}
+}
+
+// ReorgFrames holds the frames captured either side of a reorg.
+type ReorgFrames struct {
+	Before *types.Frame `json:"before"`
+	After  *types.Frame `json:"after"`
+}
+
+func (f *ForkChoice) AddNewReorg(ctx context.Context, sourceName string, reorg *types.Reorg) error {
+	operation := OperationAddReorg
+
+	f.metrics.ObserveOperation(operation)
+
+	if reorg == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return errors.New("reorg is nil")
+	}
+
+	if err := reorg.Validate(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return err
+	}
+
+	logCtx := f.log.WithFields(logrus.Fields{
+		"source": sourceName,
+		"id":     reorg.ID,
+		"slot":   reorg.Slot,
+		"depth":  reorg.Depth,
+		"node":   reorg.Node,
+	})
+
+	if err := f.indexer.InsertReorg(ctx, reorg); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		logCtx.WithError(err).Error("Failed to index reorg")
+
+		return err
+	}
+
+	logCtx.Debug("Indexed reorg")
+
+	return nil
+}
+
+func (f *ForkChoice) ListReorgs(ctx context.Context, filter *ReorgFilter, page PaginationCursor) ([]*types.Reorg, *PaginationResponse, error) {
+	operation := OperationListReorgs
+
+	f.metrics.ObserveOperation(operation)
+
+	if filter == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, ErrInvalidFilter
+	}
+
+	reorgs, err := f.indexer.ListReorgs(ctx, filter.AsDBFilter(), page.AsDBPageCursor())
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).Error("failed to list reorgs")
+
+		return nil, nil, ErrUnknownServerErrorOccurred
+	}
+
+	count, err := f.indexer.CountReorgs(ctx, filter.AsDBFilter())
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).Error("failed to count reorgs")
+
+		return nil, nil, ErrUnknownServerErrorOccurred
+	}
+
+	r := db.Reorgs(reorgs)
+
+	return r.AsReorgs(), &PaginationResponse{
+		Total: count,
+	}, nil
+}
+
+func (f *ForkChoice) GetReorg(ctx context.Context, id string) (*types.Reorg, error) {
+	operation := OperationGetReorg
+
+	f.metrics.ObserveOperation(operation)
+
+	if id == "" {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, ErrInvalidID
+	}
+
+	filter := &db.ReorgFilter{}
+	filter.AddID(id)
+
+	reorgs, err := f.indexer.ListReorgs(ctx, filter, &db.PaginationCursor{Limit: 1})
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).WithField("id", id).Error("failed to get reorg")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	if len(reorgs) == 0 {
+		return nil, ErrReorgNotFound
+	}
+
+	return reorgs[0].AsReorg(), nil
+}
+
+// GetReorgFrames returns the frames captured before and after the reorg. Either
+// frame may be nil if it was never captured or has since been purged.
+func (f *ForkChoice) GetReorgFrames(ctx context.Context, id string) (*types.Reorg, *ReorgFrames, error) {
+	reorg, err := f.GetReorg(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	frames := &ReorgFrames{}
+
+	for _, target := range []struct {
+		id    string
+		frame **types.Frame
+	}{
+		{reorg.BeforeFrameID, &frames.Before},
+		{reorg.AfterFrameID, &frames.After},
+	} {
+		if target.id == "" {
+			continue
+		}
+
+		frame, err := f.GetFrame(ctx, target.id)
+		if err != nil {
+			if errors.Is(err, ErrFrameNotFound) {
+				continue
+			}
+
+			return nil, nil, err
+		}
+
+		*target.frame = frame
+	}
+
+	return reorg, frames, nil
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/discovery"
 	"github.com/ethpandaops/forky/pkg/forky/ethereum"
+	"github.com/ethpandaops/forky/pkg/forky/forkchoice"
 	"github.com/ethpandaops/forky/pkg/forky/source"
 	"github.com/ethpandaops/forky/pkg/forky/store"
 	"github.com/ethpandaops/forky/pkg/forky/types"
@@ -16,35 +20,105 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// ForkLabelPrefix prefixes the label recording which fork was active when a
+// frame was fetched.
+const ForkLabelPrefix = "ethereum_fork_"
+
 type ForkChoice struct {
-	config  *Config
-	opts    *Options
-	log     logrus.FieldLogger
-	sources map[string]source.Source
-	store   store.Store
-	indexer *db.Indexer
-	metrics *Metrics
-	eth     *ethereum.BeaconChain
+	config    *Config
+	opts      *Options
+	log       logrus.FieldLogger
+	namespace string
+	store     store.Store
+	indexer   *db.Indexer
+	metrics   *Metrics
+
+	// networks are the networks served by the instance, keyed by name.
+	// networkNames is in config order, so the default network is first.
+	networks     map[string]*network
+	networkNames []string
+
+	// sources can be changed at runtime, so must only be accessed while
+	// holding sourcesMu. sourceConfigs holds the config each source was created
+	// from, with its network resolved, so that it can be recreated.
+	sourcesMu     sync.RWMutex
+	sources       map[string]source.Source
+	sourceConfigs map[string]source.Config
+	sourceCancels map[string]context.CancelFunc
+
+	// sourceLifecycleMu serialises starting and stopping sources at runtime,
+	// so that sourcesMu is only held while the maps above are read or updated.
+	sourceLifecycleMu sync.Mutex
+
+	discoveries []*discoveredSources
+
+	// finality is nil unless the finality monitor is enabled.
+	finality *finalityMonitor
+
+	// runCtx is the context the service was started with. Sources added at
+	// runtime are started with it.
+	runCtx context.Context //nolint:containedctx // Sources added at runtime need the service's context.
 }
 
 func NewForkChoice(namespace string, log logrus.FieldLogger, config *Config, opts *Options) (*ForkChoice, error) {
+	if err := config.ConsistencyCheck.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := config.FinalityMonitor.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Create our ethereum beaconchain services.
+	networks, networkNames, err := newNetworks(log, config, opts.MetricsEnabled)
+	if err != nil {
+		log.Fatalf("failed to create ethereum beaconchain: %s", err)
+	}
+
 	// Create our sources.
 	sources := make(map[string]source.Source)
-
-	sourceOpts := source.
-		DefaultOptions().
-		SetMetricsEnabled(opts.MetricsEnabled).
-		WithAllowedEthereumNetworks([]string{config.Ethereum.Network.Name})
+	sourceConfigs := make(map[string]source.Config)
 
 	for _, s := range config.Sources {
+		n, err := resolveNetwork(networks, networkNames, s.Network)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network for source %s: %w", s.Name, err)
+		}
+
 		conf := s.Config
-		sou, err := source.NewSource(namespace, log, s.Name, s.Type, conf, sourceOpts)
+		sou, err := source.NewSource(namespace, log, s.Name, s.Type, conf, n.sourceOpts)
 
 		if err != nil {
 			log.Fatalf("failed to create source %s: %s", s.Name, err)
 		}
 
 		sources[s.Name] = sou
+		sourceConfigs[s.Name] = source.Config{Name: s.Name, Type: s.Type, Network: n.name, Config: conf}
+	}
+
+	discoveries := make([]*discoveredSources, 0, len(config.Discovery))
+	discoveryNames := make(map[string]struct{}, len(config.Discovery))
+
+	for i := range config.Discovery {
+		d, err := discovery.NewDiscovery(&config.Discovery[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create discovery %s: %w", config.Discovery[i].Name, err)
+		}
+
+		if _, ok := discoveryNames[d.Name()]; ok {
+			return nil, fmt.Errorf("duplicate discovery name: %s", d.Name())
+		}
+
+		if _, err := resolveNetwork(networks, networkNames, config.Discovery[i].Network); err != nil {
+			return nil, fmt.Errorf("invalid network for discovery %s: %w", d.Name(), err)
+		}
+
+		discoveryNames[d.Name()] = struct{}{}
+
+		discoveries = append(discoveries, &discoveredSources{
+			discovery: d,
+			sources:   make(map[string]string),
+		})
 	}
 
 	// Create our store.
@@ -63,21 +137,28 @@ func NewForkChoice(namespace string, log logrus.FieldLogger, config *Config, opt
 		log.Fatalf("failed to create indexer: %s", err)
 	}
 
-	// Create our ethereum beaconchain service.
-	eth, err := ethereum.NewBeaconChain(log, &config.Ethereum)
-	if err != nil {
-		log.Fatalf("failed to create ethereum beaconchain: %s", err)
+	metrics := NewMetrics(namespace+"_service", config, opts.MetricsEnabled)
+
+	var finality *finalityMonitor
+	if config.FinalityMonitor.Enabled {
+		finality = newFinalityMonitor(&config.FinalityMonitor, log, metrics)
 	}
 
 	return &ForkChoice{
-		config:  config,
-		opts:    opts,
-		log:     log.WithField("component", "service"),
-		sources: sources,
-		store:   st,
-		indexer: indexer,
-		metrics: NewMetrics(namespace+"_service", config, opts.MetricsEnabled),
-		eth:     eth,
+		config:        config,
+		opts:          opts,
+		log:           log.WithField("component", "service"),
+		namespace:     namespace,
+		sources:       sources,
+		sourceConfigs: sourceConfigs,
+		sourceCancels: make(map[string]context.CancelFunc),
+		discoveries:   discoveries,
+		store:         st,
+		indexer:       indexer,
+		metrics:       metrics,
+		networks:      networks,
+		networkNames:  networkNames,
+		finality:      finality,
 	}, nil
 }
 
@@ -85,35 +166,86 @@ func (f *ForkChoice) Start(ctx context.Context) error {
 	f.log.
 		WithField("retention_period", f.config.RetentionPeriod.Duration.String()).
 		WithField("version", version.Short()).
+		WithField("networks", f.networkNames).
 		WithField("sources", len(f.sources)).
+		WithField("discoveries", len(f.discoveries)).
 		WithField("store", f.config.Store.Type).
 		WithField("indexer", f.config.Indexer.DriverName).
 		Info("Starting forky service")
 
-	for _, source := range f.sources {
-		s := source
-		s.OnFrame(func(ctx context.Context, frame *types.Frame) {
-			if err := f.AddNewFrame(ctx, s.Name(), frame); err != nil {
-				f.log.WithError(err).Error("Failed to add new frame")
-			}
-		})
+	for _, name := range f.networkNames {
+		if err := f.networks[name].eth.Start(ctx); err != nil {
+			return err
+		}
+	}
+
+	f.sourcesMu.Lock()
+
+	f.runCtx = ctx
+
+	for name, s := range f.sources {
+		cancel, err := f.startSource(ctx, s, f.sourceConfigs[name].Network)
+		if err != nil {
+			f.sourcesMu.Unlock()
 
-		if err := s.Start(ctx); err != nil {
 			return err
 		}
+
+		f.sourceCancels[name] = cancel
 	}
 
+	f.sourcesMu.Unlock()
+
 	go f.pollForUnwantedFrames(ctx)
 	go f.pollForEmptyConsensusClientFrames(ctx)
 	go f.pollForEmptyEventSource(ctx)
+	go f.pollForEmptyNetworkFrames(ctx)
+	go func() {
+		// Frames indexed since versions were indexed already have one, so
+		// this only needs to run once.
+		if err := f.BackfillConsensusClientVersion(ctx); err != nil {
+			f.log.WithError(err).Error("Failed to backfill consensus client version")
+		}
+	}()
 	go f.pollForUselessLabelDeletion(ctx)
+	go f.pollForLabelEncodedReorgs(ctx)
+	go f.pollForSourceStatus(ctx)
+
+	for _, d := range f.discoveries {
+		go f.pollForDiscovery(ctx, d)
+	}
+
+	if f.config.ConsistencyCheck.Enabled {
+		go f.pollForInconsistencies(ctx)
+	}
+
+	if f.finality != nil {
+		go f.pollForFinalityLag(ctx)
+	}
 
 	return nil
 }
 
 func (f *ForkChoice) Stop(ctx context.Context) error {
-	for _, source := range f.sources {
-		if err := source.Stop(ctx); err != nil {
+	f.sourceLifecycleMu.Lock()
+	defer f.sourceLifecycleMu.Unlock()
+
+	f.sourcesMu.Lock()
+
+	sources := make(map[string]source.Source, len(f.sources))
+	cancels := make(map[string]context.CancelFunc, len(f.sourceCancels))
+
+	for name, s := range f.sources {
+		sources[name] = s
+		cancels[name] = f.sourceCancels[name]
+
+		delete(f.sourceCancels, name)
+	}
+
+	f.sourcesMu.Unlock()
+
+	for name, s := range sources {
+		if err := stopSource(ctx, s, cancels[name]); err != nil {
 			return err
 		}
 	}
@@ -127,6 +259,10 @@ func (f *ForkChoice) pollForUnwantedFrames(ctx context.Context) {
 			f.log.WithError(err).Error("Failed to delete old frames")
 		}
 
+		if err := f.DeleteOldReorgs(ctx); err != nil {
+			f.log.WithError(err).Error("Failed to delete old reorgs")
+		}
+
 		select {
 		case <-time.After(1 * time.Minute):
 		case <-ctx.Done():
@@ -149,6 +285,20 @@ func (f *ForkChoice) pollForEmptyConsensusClientFrames(ctx context.Context) {
 	}
 }
 
+func (f *ForkChoice) pollForEmptyNetworkFrames(ctx context.Context) {
+	for {
+		if err := f.BackfillNetwork(ctx); err != nil {
+			f.log.WithError(err).Error("Failed to backfill network")
+		}
+
+		select {
+		case <-time.After(1 * time.Minute):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (f *ForkChoice) pollForEmptyEventSource(ctx context.Context) {
 	for {
 		if err := f.BackfillEventSource(ctx); err != nil {
@@ -163,6 +313,26 @@ func (f *ForkChoice) pollForEmptyEventSource(ctx context.Context) {
 	}
 }
 
+func (f *ForkChoice) pollForLabelEncodedReorgs(ctx context.Context) {
+	for {
+		migrated, err := f.BackfillReorgs(ctx)
+		if err != nil {
+			f.log.WithError(err).Error("Failed to backfill reorgs")
+		}
+
+		// Keep going straight away while there's a backlog to work through.
+		if migrated > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-time.After(1 * time.Minute):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func (f *ForkChoice) pollForUselessLabelDeletion(ctx context.Context) {
 	for {
 		if err := f.DeleteUselessLabels(ctx); err != nil {
@@ -195,6 +365,8 @@ func (f *ForkChoice) AddNewFrame(ctx context.Context, sourceName string, frame *
 		return err
 	}
 
+	f.labelFork(frame)
+
 	logCtx := f.log.WithFields(logrus.Fields{
 		"source":    sourceName,
 		"id":        frame.Metadata.ID,
@@ -203,8 +375,10 @@ func (f *ForkChoice) AddNewFrame(ctx context.Context, sourceName string, frame *
 		"node":      frame.Metadata.Node,
 	})
 
-	// Store the frame in the store.
-	if err := f.store.SaveFrame(ctx, frame); err != nil {
+	// Store the frame in the store. Sources give redelivered frames the same
+	// ID, so it may already be there if it was added before or if indexing it
+	// failed last time.
+	if err := f.store.SaveFrame(ctx, frame); err != nil && !errors.Is(err, store.ErrFrameAlreadyStored) {
 		f.metrics.ObserveOperationError(operation)
 
 		logCtx.WithError(err).Error("Failed to store frame")
@@ -212,8 +386,25 @@ func (f *ForkChoice) AddNewFrame(ctx context.Context, sourceName string, frame *
 		return err
 	}
 
-	// Add the frame to the indexer.
+	// The raw payload is a debugging aid, so failing to store it shouldn't
+	// lose the frame.
+	if len(frame.RawPayload) > 0 {
+		if err := f.store.SaveRawFrame(ctx, frame.Metadata.ID, frame.RawPayload); err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			logCtx.WithError(err).Error("Failed to store raw frame")
+		}
+	}
+
+	// Add the frame to the indexer. A frame that's already indexed has been
+	// added before.
 	if err := f.indexer.InsertFrameMetadata(ctx, &frame.Metadata); err != nil {
+		if errors.Is(err, db.ErrFrameAlreadyIndexed) {
+			logCtx.Debug("Frame has already been added")
+
+			return nil
+		}
+
 		f.metrics.ObserveOperationError(operation)
 
 		logCtx.WithError(err).Error("Failed to index frame")
@@ -223,6 +414,10 @@ func (f *ForkChoice) AddNewFrame(ctx context.Context, sourceName string, frame *
 
 	logCtx.Debug("Stored and indexed frame")
 
+	if f.finality != nil {
+		f.finality.observe(frame)
+	}
+
 	return nil
 }
 
@@ -235,6 +430,12 @@ func (f *ForkChoice) ListNodes(ctx context.Context, filter *FrameFilter, page Pa
 		return nil, nil, ErrInvalidFilter
 	}
 
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, err
+	}
+
 	count, err := f.indexer.CountNodesWithFrames(ctx, filter.AsDBFilter())
 	if err != nil {
 		f.metrics.ObserveOperationError(operation)
@@ -269,6 +470,12 @@ func (f *ForkChoice) ListSlots(ctx context.Context, filter *FrameFilter, page Pa
 		return nil, nil, ErrInvalidFilter
 	}
 
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, err
+	}
+
 	count, err := f.indexer.CountSlotsWithFrames(ctx, filter.AsDBFilter())
 	if err != nil {
 		f.metrics.ObserveOperationError(operation)
@@ -303,6 +510,12 @@ func (f *ForkChoice) ListEpochs(ctx context.Context, filter *FrameFilter, page P
 		return nil, nil, ErrInvalidFilter
 	}
 
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, err
+	}
+
 	count, err := f.indexer.CountEpochsWithFrames(ctx, filter.AsDBFilter())
 	if err != nil {
 		f.metrics.ObserveOperationError(operation)
@@ -337,6 +550,12 @@ func (f *ForkChoice) ListLabels(ctx context.Context, filter *FrameFilter, page P
 		return nil, nil, ErrInvalidFilter
 	}
 
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, err
+	}
+
 	count, err := f.indexer.CountLabelsWithFrames(ctx, filter.AsDBFilter())
 	if err != nil {
 		f.metrics.ObserveOperationError(operation)
@@ -371,6 +590,12 @@ func (f *ForkChoice) ListMetadata(ctx context.Context, filter *FrameFilter, page
 		return nil, nil, ErrInvalidFilter
 	}
 
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, nil, err
+	}
+
 	metadata, err := f.indexer.ListFrameMetadata(ctx, filter.AsDBFilter(), page.AsDBPageCursor())
 	if err != nil {
 		f.metrics.ObserveOperationError(operation)
@@ -423,6 +648,61 @@ func (f *ForkChoice) GetFrame(ctx context.Context, id string) (*types.Frame, err
 	return frame, nil
 }
 
+// GetRawFrame returns the verbatim payload a frame was parsed from. Only frames
+// from sources configured to keep their raw payload have one.
+func (f *ForkChoice) GetRawFrame(ctx context.Context, id string) ([]byte, error) {
+	operation := OperationGetRawFrame
+
+	f.metrics.ObserveOperation(operation)
+
+	if id == "" {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, ErrInvalidID
+	}
+
+	data, err := f.store.GetRawFrame(ctx, id)
+	if err != nil {
+		if errors.Is(err, store.ErrRawFrameNotFound) {
+			return nil, ErrRawFrameNotFound
+		}
+
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).WithField("id", id).Error("failed to get raw frame")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	return data, nil
+}
+
+// GetFrameAnalysis recomputes the head of a frame with LMD-GHOST and reports
+// any inconsistencies with what the node reported.
+func (f *ForkChoice) GetFrameAnalysis(ctx context.Context, id string) (*forkchoice.Analysis, error) {
+	operation := OperationGetFrameAnalysis
+
+	f.metrics.ObserveOperation(operation)
+
+	frame, err := f.GetFrame(ctx, id)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	analysis, err := forkchoice.Analyze(frame, f.slotsPerEpoch(frame.Metadata.Network))
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).WithField("id", id).Error("failed to analyze frame")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	return analysis, nil
+}
+
 func (f *ForkChoice) DeleteFrame(ctx context.Context, id string) error {
 	operation := OperationDeleteFrame
 
@@ -453,12 +733,24 @@ func (f *ForkChoice) DeleteFrame(ctx context.Context, id string) error {
 	return nil
 }
 
-func (f *ForkChoice) GetEthereumNow(_ context.Context) (phase0.Slot, phase0.Epoch, error) {
+func (f *ForkChoice) GetEthereumNow(_ context.Context, network string) (phase0.Slot, phase0.Epoch, error) {
 	operation := OperationGetEthereumNow
 
 	f.metrics.ObserveOperation(operation)
 
-	slot, epoch, err := f.eth.Wallclock().Now()
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return 0, 0, err
+	}
+
+	wallclock := n.eth.Wallclock()
+	if wallclock == nil {
+		return 0, 0, ErrNetworkNotReady
+	}
+
+	slot, epoch, err := wallclock.Now()
 	if err != nil {
 		f.metrics.ObserveOperationError(operation)
 
@@ -470,34 +762,131 @@ func (f *ForkChoice) GetEthereumNow(_ context.Context) (phase0.Slot, phase0.Epoc
 	return phase0.Slot(slot.Number()), phase0.Epoch(epoch.Number()), nil
 }
 
-func (f *ForkChoice) GetEthereumSpecSecondsPerSlot(_ context.Context) uint64 {
+// EthereumReady returns true once the network's spec is known.
+func (f *ForkChoice) EthereumReady(_ context.Context, network string) (bool, error) {
+	n, err := f.network(network)
+	if err != nil {
+		return false, err
+	}
+
+	return n.eth.Ready(), nil
+}
+
+func (f *ForkChoice) GetEthereumSpecSecondsPerSlot(_ context.Context, network string) (uint64, error) {
 	operation := OperationGetEthereumSpec
 
 	f.metrics.ObserveOperation(operation)
 
-	return f.eth.Spec().SecondsPerSlot
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return 0, err
+	}
+
+	return n.eth.Spec().SecondsPerSlot, nil
+}
+
+func (f *ForkChoice) GetEthereumSpecSlotsPerEpoch(_ context.Context, network string) (uint64, error) {
+	operation := OperationGetEthereumSpec
+
+	f.metrics.ObserveOperation(operation)
+
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return 0, err
+	}
+
+	return n.eth.Spec().SlotsPerEpoch, nil
 }
 
-func (f *ForkChoice) GetEthereumSpecSlotsPerEpoch(_ context.Context) uint64 {
+func (f *ForkChoice) GetEthereumSpecGenesisTime(_ context.Context, network string) (time.Time, error) {
 	operation := OperationGetEthereumSpec
 
 	f.metrics.ObserveOperation(operation)
 
-	return f.eth.Spec().SlotsPerEpoch
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return time.Time{}, err
+	}
+
+	return n.eth.GenesisTime(), nil
 }
 
-func (f *ForkChoice) GetEthereumSpecGenesisTime(_ context.Context) time.Time {
+func (f *ForkChoice) GetEthereumForks(_ context.Context, network string) ([]ethereum.ScheduledFork, error) {
 	operation := OperationGetEthereumSpec
 
 	f.metrics.ObserveOperation(operation)
 
-	return f.eth.GenesisTime()
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	return n.eth.Forks(), nil
 }
 
-func (f *ForkChoice) GetEthereumNetworkName(_ context.Context) string {
+func (f *ForkChoice) GetEthereumForkAtSlot(_ context.Context, network string, slot phase0.Slot) (ethereum.Fork, error) {
 	operation := OperationGetEthereumSpec
 
 	f.metrics.ObserveOperation(operation)
 
-	return f.eth.NetworkName()
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return "", err
+	}
+
+	fork, ok := n.eth.ForkAtSlot(slot)
+	if !ok {
+		return "", ErrNetworkNotReady
+	}
+
+	return fork, nil
+}
+
+// GetEthereumNetworkName returns the name of the network, which is the default
+// network if no name is given.
+func (f *ForkChoice) GetEthereumNetworkName(_ context.Context, network string) (string, error) {
+	operation := OperationGetEthereumNetworkName
+
+	f.metrics.ObserveOperation(operation)
+
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return "", err
+	}
+
+	return n.name, nil
+}
+
+// labelFork labels the frame with the fork that was active at its wall clock
+// slot if its network has fork labelling enabled, unless it's already labelled.
+func (f *ForkChoice) labelFork(frame *types.Frame) {
+	n, err := f.network(frame.Metadata.Network)
+	if err != nil || !n.labelFramesWithFork {
+		return
+	}
+
+	for _, label := range frame.Metadata.Labels {
+		if strings.HasPrefix(label, ForkLabelPrefix) {
+			return
+		}
+	}
+
+	fork, ok := n.eth.ForkAtSlot(frame.Metadata.WallClockSlot)
+	if !ok {
+		return
+	}
+
+	frame.Metadata.Labels = append(frame.Metadata.Labels, ForkLabelPrefix+string(fork))
 }
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/source"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/ethpandaops/forky/pkg/yaml"
+)
+
+// sourceStopTimeout is how long a source has to stop when it's removed or replaced.
+const sourceStopTimeout = 30 * time.Second
+
+// ListSources returns a status snapshot of every source, sorted by name.
+func (f *ForkChoice) ListSources(ctx context.Context) ([]source.Status, error) {
+	operation := OperationListSources
+
+	f.metrics.ObserveOperation(operation)
+
+	f.sourcesMu.RLock()
+
+	statuses := make([]source.Status, 0, len(f.sources))
+
+	for _, s := range f.sources {
+		statuses = append(statuses, s.Status())
+	}
+
+	f.sourcesMu.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	return statuses, nil
+}
+
+// UpsertSource creates a source, or replaces the existing source with the same
+// name, while the service is running. The source is validated exactly as it
+// would be if it were in the config file. Returns true if the source was created
+// rather than replaced. The source is put on the default network if network is
+// empty.
+//
+// A replacement is started before the existing source is stopped, so the
+// existing source keeps running if the replacement fails to start.
+func (f *ForkChoice) UpsertSource(ctx context.Context, name, sourceType, network string, config yaml.RawMessage) (bool, error) {
+	operation := OperationUpsertSource
+
+	f.metrics.ObserveOperation(operation)
+
+	if name == "" {
+		f.metrics.ObserveOperationError(operation)
+
+		return false, fmt.Errorf("%w: name is required", ErrInvalidSource)
+	}
+
+	n, err := f.network(network)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return false, fmt.Errorf("%w: %w", ErrInvalidSource, err)
+	}
+
+	conf := source.Config{Name: name, Type: sourceType, Network: n.name, Config: config}
+
+	s, err := f.newSource(conf)
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return false, fmt.Errorf("%w: %w", ErrInvalidSource, err)
+	}
+
+	f.sourceLifecycleMu.Lock()
+	defer f.sourceLifecycleMu.Unlock()
+
+	f.sourcesMu.RLock()
+
+	runCtx := f.runCtx
+	existing, exists := f.sources[name]
+	existingConf := f.sourceConfigs[name]
+	existingCancel := f.sourceCancels[name]
+
+	f.sourcesMu.RUnlock()
+
+	if runCtx == nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return false, ErrNotStarted
+	}
+
+	cancel, err := f.startSource(runCtx, s, n.name)
+	if err != nil && exists {
+		// The existing source may hold resources the new source needs, e.g.
+		// a listen address, so stop it and try again. A source that failed
+		// to start can't be reused, so it's recreated.
+		f.log.WithError(err).WithField("source", name).Debug("Replacement source failed to start alongside existing source")
+
+		if err := stopSourceWithTimeout(ctx, existing, existingCancel); err != nil {
+			f.log.WithError(err).WithField("source", name).Warn("Failed to cleanly stop replaced source")
+		}
+
+		s, err = f.newSource(conf)
+		if err == nil {
+			cancel, err = f.startSource(runCtx, s, n.name)
+		}
+
+		if err != nil {
+			f.restoreSource(runCtx, existing, existingConf)
+
+			f.metrics.ObserveOperationError(operation)
+
+			return false, fmt.Errorf("failed to start source: %w", err)
+		}
+
+		existing = nil
+	} else if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return false, fmt.Errorf("failed to start source: %w", err)
+	}
+
+	f.sourcesMu.Lock()
+
+	f.sources[name] = s
+	f.sourceConfigs[name] = conf
+	f.sourceCancels[name] = cancel
+
+	f.sourcesMu.Unlock()
+
+	if existing != nil {
+		if err := stopSourceWithTimeout(ctx, existing, existingCancel); err != nil {
+			f.log.WithError(err).WithField("source", name).Warn("Failed to cleanly stop replaced source")
+		}
+	}
+
+	if exists && existingConf.Type != s.Type() {
+		f.metrics.DeleteSourceStatus(name, existingConf.Type)
+	}
+
+	f.log.
+		WithField("source", name).
+		WithField("type", sourceType).
+		WithField("network", n.name).
+		WithField("replaced", exists).
+		Info("Source added at runtime")
+
+	return !exists, nil
+}
+
+// RemoveSource stops and removes a source while the service is running. Frames
+// the source has already produced are kept.
+func (f *ForkChoice) RemoveSource(ctx context.Context, name string) error {
+	operation := OperationRemoveSource
+
+	f.metrics.ObserveOperation(operation)
+
+	f.sourceLifecycleMu.Lock()
+	defer f.sourceLifecycleMu.Unlock()
+
+	s, cancel, exists := f.deleteSource(name)
+	if !exists {
+		f.metrics.ObserveOperationError(operation)
+
+		return ErrSourceNotFound
+	}
+
+	err := stopSourceWithTimeout(ctx, s, cancel)
+
+	f.metrics.DeleteSourceStatus(name, s.Type())
+
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return fmt.Errorf("source removed but failed to stop cleanly: %w", err)
+	}
+
+	f.log.WithField("source", name).Info("Source removed at runtime")
+
+	return nil
+}
+
+// newSource creates a source from its config. The config's network must
+// already be resolved.
+func (f *ForkChoice) newSource(conf source.Config) (source.Source, error) {
+	n, err := f.network(conf.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	return source.NewSource(f.namespace, f.log, conf.Name, conf.Type, conf.Config, n.sourceOpts)
+}
+
+// restoreSource recreates and restarts a source that was stopped to make way
+// for a replacement that then failed to start. The source is removed if it
+// can't be restarted either.
+func (f *ForkChoice) restoreSource(ctx context.Context, previous source.Source, conf source.Config) {
+	s, err := f.newSource(conf)
+	if err != nil {
+		f.log.WithError(err).WithField("source", conf.Name).Error("Failed to recreate replaced source")
+	} else {
+		var cancel context.CancelFunc
+
+		cancel, err = f.startSource(ctx, s, conf.Network)
+		if err == nil {
+			f.sourcesMu.Lock()
+
+			f.sources[conf.Name] = s
+			f.sourceCancels[conf.Name] = cancel
+
+			f.sourcesMu.Unlock()
+
+			f.log.WithField("source", conf.Name).Warn("Replacement source failed to start, restored previous source")
+
+			return
+		}
+
+		f.log.WithError(err).WithField("source", conf.Name).Error("Failed to restart replaced source")
+	}
+
+	f.deleteSource(conf.Name)
+
+	f.metrics.DeleteSourceStatus(conf.Name, previous.Type())
+}
+
+// deleteSource removes a source from the service without stopping it, and
+// returns it along with its cancel func.
+func (f *ForkChoice) deleteSource(name string) (source.Source, context.CancelFunc, bool) {
+	f.sourcesMu.Lock()
+	defer f.sourcesMu.Unlock()
+
+	s, exists := f.sources[name]
+	cancel := f.sourceCancels[name]
+
+	delete(f.sources, name)
+	delete(f.sourceConfigs, name)
+	delete(f.sourceCancels, name)
+
+	return s, cancel, exists
+}
+
+// startSource wires a source up to the service and starts it. Frames and reorgs
+// from the source are tagged with its network, replacing any network they
+// already carry, e.g. from the deployment a replayed frame was captured by.
+// Returns the func that cancels the source's context.
+func (f *ForkChoice) startSource(ctx context.Context, s source.Source, network string) (context.CancelFunc, error) {
+	name := s.Name()
+
+	s.OnFrame(func(ctx context.Context, frame *types.Frame) error {
+		if frame != nil {
+			if frame.Metadata.Network != "" && frame.Metadata.Network != network {
+				f.log.
+					WithField("source", name).
+					WithField("frame_network", frame.Metadata.Network).
+					WithField("network", network).
+					Debug("Replacing frame network with the source's network")
+			}
+
+			frame.Metadata.Network = network
+		}
+
+		if err := f.AddNewFrame(ctx, name, frame); err != nil {
+			f.log.WithError(err).Error("Failed to add new frame")
+
+			return err
+		}
+
+		return nil
+	})
+
+	s.OnReorg(func(ctx context.Context, reorg *types.Reorg) error {
+		if reorg != nil {
+			reorg.Network = network
+		}
+
+		if err := f.AddNewReorg(ctx, name, reorg); err != nil {
+			f.log.WithError(err).Error("Failed to add new reorg")
+
+			return err
+		}
+
+		return nil
+	})
+
+	// Each source gets its own context so that any background work it started
+	// is cancelled when it's removed.
+	ctx, cancel := context.WithCancel(ctx)
+
+	if err := s.Start(ctx); err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	return cancel, nil
+}
+
+// stopSourceWithTimeout stops a source, giving it at most sourceStopTimeout.
+func stopSourceWithTimeout(ctx context.Context, s source.Source, cancel context.CancelFunc) error {
+	ctx, cancelTimeout := context.WithTimeout(ctx, sourceStopTimeout)
+	defer cancelTimeout()
+
+	return stopSource(ctx, s, cancel)
+}
+
+// stopSource stops a source and cancels its context.
+func stopSource(ctx context.Context, s source.Source, cancel context.CancelFunc) error {
+	err := s.Stop(ctx)
+
+	if cancel != nil {
+		cancel()
+	}
+
+	return err
+}
+
+func (f *ForkChoice) pollForSourceStatus(ctx context.Context) {
+	for {
+		statuses, err := f.ListSources(ctx)
+		if err != nil {
+			f.log.WithError(err).Error("Failed to list sources")
+		}
+
+		for i := range statuses {
+			f.metrics.ObserveSourceStatus(&statuses[i])
+		}
+
+		select {
+		case <-time.After(15 * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
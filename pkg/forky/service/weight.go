@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/db"
+	"github.com/ethpandaops/forky/pkg/forky/forkchoice"
+)
+
+const (
+	// MaxBlockWeightFrames is the most frames a block weight series will load.
+	MaxBlockWeightFrames = 1000
+	// DefaultBlockWeightSlots is how many slots, starting at the block's own,
+	// are searched when the filter doesn't narrow the search to a slot range.
+	DefaultBlockWeightSlots = 64
+	// blockSlotLookupFrames is how many of the latest frames are searched for
+	// the block to find its slot.
+	blockSlotLookupFrames = 10
+)
+
+// GetBlockWeights returns how the block's weight, and its share of the weight
+// under its parent, changed across the frames matching the filter. At most one
+// frame per node per wall clock slot is used, earliest slots first.
+//
+// Frames aren't indexed by the blocks they contain, so if the filter has no
+// slot range the block is looked up in the latest frames and the search is
+// narrowed to the DefaultBlockWeightSlots slots from its slot.
+func (f *ForkChoice) GetBlockWeights(ctx context.Context, root phase0.Root, filter *FrameFilter) (*forkchoice.WeightSeries, error) {
+	operation := OperationGetBlockWeights
+
+	f.metrics.ObserveOperation(operation)
+
+	if filter == nil {
+		filter = &FrameFilter{}
+	}
+
+	if err := filter.ValidateVersions(); err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		return nil, err
+	}
+
+	if filter.Slot == nil && filter.MinSlot == nil && filter.MaxSlot == nil && filter.Epoch == nil {
+		slot, err := f.blockSlot(ctx, filter, root)
+		if err != nil {
+			f.metrics.ObserveOperationError(operation)
+
+			f.log.WithError(err).Error("failed to find block slot for block weights")
+
+			return nil, ErrUnknownServerErrorOccurred
+		}
+
+		if slot == nil {
+			f.metrics.ObserveOperationError(operation)
+
+			return nil, fmt.Errorf("%w: block not found in the latest frames, a slot range is required", ErrInvalidFilter)
+		}
+
+		minSlot := uint64(*slot)
+		maxSlot := minSlot + DefaultBlockWeightSlots - 1
+
+		windowed := *filter
+		windowed.MinSlot = &minSlot
+		windowed.MaxSlot = &maxSlot
+
+		filter = &windowed
+	}
+
+	metadata, truncated, err := f.blockWeightFrames(ctx, filter.AsDBFilter())
+	if err != nil {
+		f.metrics.ObserveOperationError(operation)
+
+		f.log.WithError(err).Error("failed to list metadata for block weights")
+
+		return nil, ErrUnknownServerErrorOccurred
+	}
+
+	series := forkchoice.NewEmptyWeightSeries(root)
+
+	// Frames are added one at a time so that only one is held in memory.
+	for _, md := range metadata {
+		frame, err := f.store.GetFrame(ctx, md.ID)
+		if err != nil {
+			f.log.WithError(err).WithField("id", md.ID).Warn("failed to get frame for block weights")
+
+			continue
+		}
+
+		series.Add(frame)
+	}
+
+	series.Truncated = truncated
+
+	if series.SkippedFrames > 0 {
+		f.log.
+			WithField("root", root.String()).
+			WithField("skipped", series.SkippedFrames).
+			Warn("skipped frames with invalid fork choice for block weights")
+	}
+
+	return series, nil
+}
+
+// blockSlot returns the block's slot from the latest frames matching the
+// filter, or nil if none of them contain the block.
+func (f *ForkChoice) blockSlot(ctx context.Context, filter *FrameFilter, root phase0.Root) (*phase0.Slot, error) {
+	metadata, err := f.indexer.ListFrameMetadata(ctx, filter.AsDBFilter(), &db.PaginationCursor{
+		Limit:   blockSlotLookupFrames,
+		OrderBy: "fetched_at DESC",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, md := range metadata {
+		frame, err := f.store.GetFrame(ctx, md.ID)
+		if err != nil {
+			f.log.WithError(err).WithField("id", md.ID).Warn("failed to get frame for block slot")
+
+			continue
+		}
+
+		tree, err := forkchoice.NewTree(frame.Data)
+		if err != nil {
+			continue
+		}
+
+		if block := tree.Node(root); block != nil {
+			slot := block.Slot
+
+			return &slot, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// blockWeightFrames returns the first frame fetched by each node in each wall
+// clock slot, earliest slots first. It returns true if there were more than
+// MaxBlockWeightFrames.
+func (f *ForkChoice) blockWeightFrames(ctx context.Context, filter *db.FrameFilter) ([]*db.FrameMetadata, bool, error) {
+	selected := []*db.FrameMetadata{}
+	seen := make(map[string]bool)
+
+	page := &db.PaginationCursor{
+		Limit:   MaxBlockWeightFrames,
+		OrderBy: "wall_clock_slot ASC, node ASC, fetched_at ASC",
+	}
+
+	for {
+		metadata, err := f.indexer.ListFrameMetadata(ctx, filter, page)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, md := range metadata {
+			key := fmt.Sprintf("%s/%d", md.Node, md.WallClockSlot)
+			if seen[key] {
+				continue
+			}
+
+			if len(selected) == MaxBlockWeightFrames {
+				return selected, true, nil
+			}
+
+			seen[key] = true
+
+			selected = append(selected, md)
+		}
+
+		if len(metadata) < page.Limit {
+			return selected, false, nil
+		}
+
+		page.Offset += page.Limit
+	}
+}
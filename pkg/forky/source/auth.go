@@ -0,0 +1,89 @@
+package source
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AuthType is the type of authentication a source requires from its clients.
+type AuthType string
+
+const (
+	AuthTypeNone   AuthType = ""
+	AuthTypeBearer AuthType = "bearer"
+	AuthTypeBasic  AuthType = "basic"
+)
+
+// AuthConfig configures authentication for sources that accept incoming requests.
+type AuthConfig struct {
+	// Type is either "bearer" or "basic". Authentication is disabled if empty.
+	Type AuthType `yaml:"type"`
+	// Token is the bearer token clients must send.
+	Token string `yaml:"token"`
+	// Username and Password are the basic auth credentials clients must send.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+func (a *AuthConfig) Validate() error {
+	switch a.Type {
+	case AuthTypeNone:
+		return nil
+	case AuthTypeBearer:
+		if a.Token == "" {
+			return errors.New("token is required for bearer auth")
+		}
+	case AuthTypeBasic:
+		if a.Username == "" || a.Password == "" {
+			return errors.New("username and password are required for basic auth")
+		}
+	default:
+		return fmt.Errorf("invalid auth type: %s", a.Type)
+	}
+
+	return nil
+}
+
+// Enabled returns true if clients must authenticate.
+func (a *AuthConfig) Enabled() bool {
+	return a.Type != AuthTypeNone
+}
+
+// Authorize checks an Authorization header value against the config.
+func (a *AuthConfig) Authorize(header string) bool {
+	if !a.Enabled() {
+		return true
+	}
+
+	scheme, credentials, ok := strings.Cut(header, " ")
+	if !ok {
+		return false
+	}
+
+	switch a.Type {
+	case AuthTypeBearer:
+		if !strings.EqualFold(scheme, "bearer") {
+			return false
+		}
+
+		return subtle.ConstantTimeCompare([]byte(credentials), []byte(a.Token)) == 1
+	case AuthTypeBasic:
+		if !strings.EqualFold(scheme, "basic") {
+			return false
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(credentials)
+		if err != nil {
+			return false
+		}
+
+		expected := a.Username + ":" + a.Password
+
+		return subtle.ConstantTimeCompare(decoded, []byte(expected)) == 1
+	default:
+		return false
+	}
+}
@@ -0,0 +1,42 @@
+package source
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthConfig_Authorize(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		auth := &AuthConfig{}
+
+		assert.NoError(t, auth.Validate())
+		assert.True(t, auth.Authorize(""))
+	})
+
+	t.Run("bearer", func(t *testing.T) {
+		auth := &AuthConfig{Type: AuthTypeBearer, Token: "secret"}
+
+		assert.NoError(t, auth.Validate())
+		assert.True(t, auth.Authorize("Bearer secret"))
+		assert.False(t, auth.Authorize("Bearer wrong"))
+		assert.False(t, auth.Authorize("secret"))
+		assert.False(t, auth.Authorize(""))
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		auth := &AuthConfig{Type: AuthTypeBasic, Username: "user", Password: "pass"}
+
+		assert.NoError(t, auth.Validate())
+		assert.True(t, auth.Authorize("Basic "+base64.StdEncoding.EncodeToString([]byte("user:pass"))))
+		assert.False(t, auth.Authorize("Basic "+base64.StdEncoding.EncodeToString([]byte("user:wrong"))))
+		assert.False(t, auth.Authorize("Bearer pass"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Error(t, (&AuthConfig{Type: AuthTypeBearer}).Validate())
+		assert.Error(t, (&AuthConfig{Type: AuthTypeBasic, Username: "user"}).Validate())
+		assert.Error(t, (&AuthConfig{Type: "digest"}).Validate())
+	})
+}
@@ -2,7 +2,16 @@ package source
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
@@ -31,56 +40,307 @@ type BeaconNode struct {
 
 	cron *gocron.Scheduler
 
-	client eth2client.Service
+	endpoints *beaconNodeEndpoints
+
+	status *statusTracker
 
 	name string
 
-	onFrameCallbacks []func(ctx context.Context, frame *types.Frame)
+	onFrameCallbacks []func(ctx context.Context, frame *types.Frame) error
+	onReorgCallbacks []func(ctx context.Context, reorg *types.Reorg) error
 
 	metrics *BasicMetrics
 
+	opts *Options
+
 	// Ethereum network parameters.
-	genesis        *v1.Genesis
-	secondsPerSlot time.Duration
-	slotsPerEpoch  uint64
-	wallclock      *ethwallclock.EthereumBeaconChain
+	network   *ethereum.NetworkParameters
+	wallclock *ethwallclock.EthereumBeaconChain
 }
 
 type BeaconNodeConfig struct {
-	Address         string       `yaml:"address"`
-	PollingInterval string       `yaml:"polling_interval"`
-	Store           store.Config `yaml:"store"`
-	Labels          []string     `yaml:"labels"`
+	// Address is the address of the beacon node. Use Endpoints instead for failover.
+	Address string `yaml:"address"`
+	// Endpoints are equivalent beacon node endpoints in order of preference.
+	// Frames are fetched from the most preferred healthy endpoint, and the
+	// endpoint used is recorded as a label on each frame.
+	Endpoints []BeaconNodeEndpointConfig `yaml:"endpoints"`
+	// HealthCheckInterval is how often endpoint health is checked, which is how
+	// the source fails back to preferred endpoints. Defaults to 30s.
+	HealthCheckInterval string `yaml:"health_check_interval"`
+	// FailureThreshold is the number of consecutive failures before an endpoint
+	// is considered unhealthy. Defaults to 1.
+	FailureThreshold int          `yaml:"failure_threshold"`
+	PollingInterval  string       `yaml:"polling_interval"`
+	Store            store.Config `yaml:"store"`
+	Labels           []string     `yaml:"labels"`
+	// Timeout is the timeout for requests to the beacon node. Fork choice dumps
+	// on large networks can take a while, so this may need raising. Defaults to 15s.
+	Timeout string `yaml:"timeout"`
+	// Headers are sent with every request. Values can reference environment
+	// variables, e.g. "Bearer ${BEACON_TOKEN}".
+	Headers map[string]string `yaml:"headers"`
+	// HeaderFiles maps header names to files containing their values. Files are
+	// re-read whenever the client is (re)created.
+	HeaderFiles map[string]string `yaml:"header_files"`
+	// TLS configures the TLS client used to connect to the beacon node.
+	TLS BeaconNodeTLSConfig `yaml:"tls"`
+	// StoreRawPayload stores the verbatim debug/fork_choice response body
+	// alongside every frame. Useful for debugging client encodings, at the cost
+	// of roughly doubling storage.
+	StoreRawPayload bool `yaml:"store_raw_payload"`
+}
+
+type BeaconNodeTLSConfig struct {
+	// CAFile is a PEM encoded CA bundle used to verify the beacon node.
+	CAFile string `yaml:"ca_file"`
+	// CertFile and KeyFile are a PEM encoded client certificate and key for mTLS.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ServerName overrides the server name used to verify the beacon node's certificate.
+	ServerName string `yaml:"server_name"`
+	// InsecureSkipVerify disables verification of the beacon node's certificate.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+}
+
+func (t *BeaconNodeTLSConfig) Enabled() bool {
+	return t.CAFile != "" || t.CertFile != "" || t.ServerName != "" || t.InsecureSkipVerify
 }
 
 func (b *BeaconNodeConfig) Validate() error {
-	if b.Address == "" {
+	if b.Address == "" && len(b.Endpoints) == 0 {
 		return errors.New("invalid address")
 	}
 
+	if b.Address != "" && len(b.Endpoints) > 0 {
+		return errors.New("only one of address and endpoints can be set")
+	}
+
+	names := map[string]bool{}
+
+	for _, endpoint := range b.GetEndpoints() {
+		if endpoint.Address == "" {
+			return errors.New("invalid endpoint address")
+		}
+
+		if names[endpoint.Name] {
+			return fmt.Errorf("duplicate endpoint name: %s", endpoint.Name)
+		}
+
+		names[endpoint.Name] = true
+	}
+
+	if b.HealthCheckInterval != "" {
+		if _, err := time.ParseDuration(b.HealthCheckInterval); err != nil {
+			return perrors.Wrap(err, "invalid health check interval")
+		}
+	}
+
+	if b.FailureThreshold < 0 {
+		return errors.New("failure threshold must be positive")
+	}
+
 	if b.PollingInterval == "" {
 		return errors.New("invalid polling interval")
 	}
 
+	if b.Timeout != "" {
+		if _, err := time.ParseDuration(b.Timeout); err != nil {
+			return perrors.Wrap(err, "invalid timeout")
+		}
+	}
+
+	if (b.TLS.CertFile == "") != (b.TLS.KeyFile == "") {
+		return errors.New("tls cert_file and key_file must be set together")
+	}
+
 	return nil
 }
 
-func NewBeaconNode(namespace string, log logrus.FieldLogger, config *BeaconNodeConfig, name string, metrics *BasicMetrics) (*BeaconNode, error) {
+// GetEndpoints returns the configured endpoints in order of preference, with
+// names defaulted to their redacted address. Endpoints on the same host redact
+// to the same address, so their position is appended to keep names unique.
+func (b *BeaconNodeConfig) GetEndpoints() []BeaconNodeEndpointConfig {
+	if len(b.Endpoints) == 0 {
+		return []BeaconNodeEndpointConfig{{
+			Name:    redactAddress(b.Address),
+			Address: b.Address,
+		}}
+	}
+
+	used := map[string]bool{}
+
+	for _, endpoint := range b.Endpoints {
+		if endpoint.Name != "" {
+			used[endpoint.Name] = true
+		}
+	}
+
+	endpoints := make([]BeaconNodeEndpointConfig, 0, len(b.Endpoints))
+
+	for i, endpoint := range b.Endpoints {
+		if endpoint.Name == "" {
+			endpoint.Name = redactAddress(endpoint.Address)
+
+			if used[endpoint.Name] {
+				endpoint.Name = fmt.Sprintf("%s#%d", endpoint.Name, i)
+			}
+
+			used[endpoint.Name] = true
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// GetHealthCheckInterval returns the endpoint health check interval, defaulting to 30s.
+func (b *BeaconNodeConfig) GetHealthCheckInterval() time.Duration {
+	interval, err := time.ParseDuration(b.HealthCheckInterval)
+	if err != nil || interval <= 0 {
+		return 30 * time.Second
+	}
+
+	return interval
+}
+
+// GetTimeout returns the request timeout, defaulting to 15s.
+func (b *BeaconNodeConfig) GetTimeout() time.Duration {
+	timeout, err := time.ParseDuration(b.Timeout)
+	if err != nil || timeout <= 0 {
+		return 15 * time.Second
+	}
+
+	return timeout
+}
+
+// ResolveHeaders returns the headers to send with every request, expanding
+// environment variables and reading header files.
+func (b *BeaconNodeConfig) ResolveHeaders() (map[string]string, error) {
+	headers := make(map[string]string, len(b.Headers)+len(b.HeaderFiles))
+
+	for name, value := range b.Headers {
+		headers[name] = os.ExpandEnv(value)
+	}
+
+	for name, path := range b.HeaderFiles {
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return nil, perrors.Wrapf(err, "failed to read header file for %s", name)
+		}
+
+		headers[name] = strings.TrimSpace(string(value))
+	}
+
+	return headers, nil
+}
+
+// HTTPClient returns the HTTP client used to connect to the beacon node.
+func (b *BeaconNodeConfig) HTTPClient() (*nethttp.Client, error) {
+	transport, ok := nethttp.DefaultTransport.(*nethttp.Transport)
+	if !ok {
+		return nil, errors.New("default transport is not an *http.Transport")
+	}
+
+	transport = transport.Clone()
+
+	if b.TLS.Enabled() {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         b.TLS.ServerName,
+			InsecureSkipVerify: b.TLS.InsecureSkipVerify, //nolint:gosec // Explicitly opted in to.
+		}
+
+		if b.TLS.CAFile != "" {
+			ca, err := os.ReadFile(b.TLS.CAFile)
+			if err != nil {
+				return nil, perrors.Wrap(err, "failed to read tls ca_file")
+			}
+
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return nil, errors.New("no certificates found in tls ca_file")
+			}
+
+			tlsConfig.RootCAs = pool
+		}
+
+		if b.TLS.CertFile != "" {
+			cert, err := tls.LoadX509KeyPair(b.TLS.CertFile, b.TLS.KeyFile)
+			if err != nil {
+				return nil, perrors.Wrap(err, "failed to load tls client certificate")
+			}
+
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &nethttp.Client{Transport: transport}, nil
+}
+
+// redactAddress strips credentials, paths and query strings (which often
+// contain API keys) from an address so it can be logged.
+func redactAddress(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return "<redacted>"
+	}
+
+	redacted := u.Scheme + "://"
+
+	if u.User != nil {
+		redacted += "xxxxx@"
+	}
+
+	redacted += u.Host
+
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		redacted += "/xxxxx"
+	}
+
+	return redacted
+}
+
+// addressPattern matches the URLs that errors, e.g. from net/http, quote.
+var addressPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"']+`)
+
+// redactError returns the error's message with any addresses in it redacted,
+// so that it can be shown publicly.
+func redactError(err error) string {
+	return addressPattern.ReplaceAllStringFunc(err.Error(), redactAddress)
+}
+
+func NewBeaconNode(namespace string, log logrus.FieldLogger, config *BeaconNodeConfig, name string, metrics *BasicMetrics, opts *Options) (*BeaconNode, error) {
 	if err := config.Validate(); err != nil {
 		return nil, perrors.Wrap(err, "invalid config")
 	}
 
 	scheduler := gocron.NewScheduler(time.Local)
 
+	log = log.
+		WithField("source_name", name).
+		WithField("component", "source/beacon_node")
+
+	// The source is considered stalled if it misses a few polls in a row.
+	var stallAfter time.Duration
+	if interval, err := time.ParseDuration(config.PollingInterval); err == nil {
+		stallAfter = 3 * interval
+	}
+
 	return &BeaconNode{
-		log: log.
-			WithField("source_name", name).
-			WithField("component", "source/beacon_node"),
+		log:              log,
 		config:           config,
 		cron:             scheduler,
+		endpoints:        newBeaconNodeEndpoints(log, config.GetEndpoints(), config.FailureThreshold),
+		status:           newStatusTracker(stallAfter),
 		name:             name,
-		onFrameCallbacks: []func(ctx context.Context, frame *types.Frame){},
+		onFrameCallbacks: []func(ctx context.Context, frame *types.Frame) error{},
+		onReorgCallbacks: []func(ctx context.Context, reorg *types.Reorg) error{},
 		metrics:          metrics,
+		opts:             opts,
 	}, nil
 }
 
@@ -93,7 +353,10 @@ func (b *BeaconNode) Type() string {
 }
 
 func (b *BeaconNode) Start(ctx context.Context) error {
-	_, err := b.cron.Every(b.config.PollingInterval).Do(func() {
+	b.log.WithField("timeout", b.config.GetTimeout().String()).Info("Starting beacon_node source")
+
+	// Slow fork choice dumps shouldn't pile up on top of each other.
+	_, err := b.cron.Every(b.config.PollingInterval).SingletonMode().Do(func() {
 		if err := b.fetchFrame(ctx); err != nil {
 			b.log.WithError(err).Error("Failed to fetch frame")
 		}
@@ -102,6 +365,15 @@ func (b *BeaconNode) Start(ctx context.Context) error {
 		return perrors.Wrap(err, "failed to schedule polling")
 	}
 
+	if len(b.endpoints.endpoints) > 1 {
+		_, err = b.cron.Every(b.config.GetHealthCheckInterval()).SingletonMode().Do(func() {
+			b.endpoints.CheckHealth(ctx)
+		})
+		if err != nil {
+			return perrors.Wrap(err, "failed to schedule health checks")
+		}
+	}
+
 	go func() {
 		back := backoff.NewExponentialBackOff()
 
@@ -110,11 +382,17 @@ func (b *BeaconNode) Start(ctx context.Context) error {
 
 		for {
 			if err := b.bootstrap(ctx); err != nil {
+				b.status.RecordFailure(err)
+
 				sleepFor := back.NextBackOff()
 
 				b.log.WithError(err).WithField("next_attempt_in", sleepFor.String()).Error("Failed to bootstrap")
 
-				time.Sleep(sleepFor)
+				select {
+				case <-time.After(sleepFor):
+				case <-ctx.Done():
+					return
+				}
 			} else {
 				break
 			}
@@ -129,23 +407,17 @@ func (b *BeaconNode) Start(ctx context.Context) error {
 func (b *BeaconNode) Stop(ctx context.Context) error {
 	b.cron.Stop()
 
+	b.status.RecordStopped()
+
 	return nil
 }
 
 func (b *BeaconNode) Ready(ctx context.Context) bool {
-	if b.client == nil {
+	if len(b.endpoints.Ordered()) == 0 {
 		return false
 	}
 
-	if b.genesis == nil {
-		return false
-	}
-
-	if b.secondsPerSlot == 0 {
-		return false
-	}
-
-	if b.slotsPerEpoch == 0 {
+	if b.network == nil {
 		return false
 	}
 
@@ -156,97 +428,132 @@ func (b *BeaconNode) Ready(ctx context.Context) bool {
 	return true
 }
 
-func (b *BeaconNode) OnFrame(callback func(ctx context.Context, frame *types.Frame)) {
+func (b *BeaconNode) Status() Status {
+	status := b.status.Snapshot(b.name, BeaconNodeType)
+
+	status.Endpoints = b.endpoints.Status()
+
+	return status
+}
+
+func (b *BeaconNode) OnFrame(callback func(ctx context.Context, frame *types.Frame) error) {
 	b.onFrameCallbacks = append(b.onFrameCallbacks, callback)
 }
 
+// OnReorg registers a reorg callback. Beacon node sources don't currently detect reorgs.
+func (b *BeaconNode) OnReorg(callback func(ctx context.Context, reorg *types.Reorg) error) {
+	b.onReorgCallbacks = append(b.onReorgCallbacks, callback)
+}
+
 func (b *BeaconNode) publishFrame(ctx context.Context, frame *types.Frame) {
 	for _, callback := range b.onFrameCallbacks {
-		go callback(ctx, frame)
+		go func(callback func(ctx context.Context, frame *types.Frame) error) {
+			if err := callback(ctx, frame); err != nil {
+				b.log.WithError(err).WithField("id", frame.Metadata.ID).Debug("Frame callback failed")
+			}
+		}(callback)
 	}
 }
 
 func (b *BeaconNode) bootstrap(ctx context.Context) error {
-	client, err := http.New(ctx,
-		http.WithAddress(b.config.Address),
-		http.WithLogLevel(zerolog.WarnLevel),
-		http.WithTimeout(15*time.Second),
-	)
+	headers, err := b.config.ResolveHeaders()
 	if err != nil {
-		return perrors.Wrap(err, "failed to create client")
-	}
-
-	b.client = client
-
-	genesisProvider, ok := b.client.(eth2client.GenesisProvider)
-	if !ok {
-		return errors.New("client does not support genesis provider")
+		return err
 	}
 
-	// Fetch the genesis time and network parameters.
-	rsp, err := genesisProvider.Genesis(ctx, &api.GenesisOpts{})
+	httpClient, err := b.config.HTTPClient()
 	if err != nil {
-		return perrors.Wrap(err, "failed to fetch genesis time")
+		return err
 	}
 
-	if rsp == nil || rsp.Data == nil {
-		return errors.New("received nil response when fetching genesis time")
-	}
+	for _, endpoint := range b.endpoints.endpoints {
+		if endpoint.Client() != nil {
+			continue
+		}
 
-	b.genesis = rsp.Data
+		client, err := http.New(ctx,
+			http.WithAddress(endpoint.address),
+			http.WithLogLevel(zerolog.WarnLevel),
+			http.WithTimeout(b.config.GetTimeout()),
+			http.WithExtraHeaders(headers),
+			http.WithHTTPClient(httpClient),
+			// Endpoints that are down shouldn't stop the others from being used.
+			http.WithAllowDelayedStart(true),
+		)
+		if err != nil {
+			return perrors.Wrapf(err, "failed to create client for endpoint %s", endpoint.name)
+		}
 
-	// Fetch the network parameters.
-	specProvider, ok := b.client.(eth2client.SpecProvider)
-	if !ok {
-		return errors.New("client does not support spec provider")
+		endpoint.SetClient(client)
+		endpoint.SetForkChoiceClient(newForkChoiceClient(endpoint.address, headers, httpClient))
 	}
 
-	specRsp, err := specProvider.Spec(ctx, &api.SpecOpts{})
-	if err != nil {
-		return perrors.Wrap(err, "failed to fetch spec")
-	}
+	var errs []error
 
-	if specRsp == nil || specRsp.Data == nil {
-		return errors.New("received nil response when fetching spec")
-	}
+	for _, endpoint := range b.endpoints.Ordered() {
+		if err := b.fetchNetworkParameters(ctx, endpoint); err != nil {
+			b.endpoints.MarkFailure(endpoint, err)
 
-	spec := specRsp.Data
+			errs = append(errs, perrors.Wrapf(err, "endpoint %s", endpoint.name))
 
-	secondsPerSlotSpec, ok := spec["SECONDS_PER_SLOT"]
-	if !ok {
-		return errors.New("failed to fetch SECONDS_PER_SLOT")
-	}
+			continue
+		}
 
-	secondsPerSlot, ok := secondsPerSlotSpec.(time.Duration)
-	if !ok {
-		return errors.New("failed to cast SECONDS_PER_SLOT to time.Duration")
+		b.endpoints.MarkSuccess(endpoint)
+
+		return nil
 	}
 
-	//nolint:unconvert //incorrect
-	b.secondsPerSlot = time.Duration(secondsPerSlot)
+	return errors.Join(errs...)
+}
 
-	slotsPerEpoch, ok := spec["SLOTS_PER_EPOCH"]
-	if !ok {
-		return errors.New("failed to fetch SLOTS_PER_EPOCH")
+func (b *BeaconNode) fetchNetworkParameters(ctx context.Context, endpoint *beaconNodeEndpoint) error {
+	network, err := ethereum.FetchNetworkParameters(ctx, endpoint.Client())
+	if err != nil {
+		return err
 	}
 
-	sslotsPerEpoch, ok := slotsPerEpoch.(uint64)
-	if !ok {
-		return errors.New("failed to cast SLOTS_PER_EPOCH to uint64")
+	if network.Name != "" {
+		b.status.SetNetwork(network.Name)
 	}
 
-	b.slotsPerEpoch = sslotsPerEpoch
+	if err := b.verifyNetwork(endpoint, network); err != nil {
+		return err
+	}
+
+	b.network = network
 
 	// Create the wallclock.
 	b.wallclock = ethwallclock.NewEthereumBeaconChain(
-		b.genesis.GenesisTime,
-		b.secondsPerSlot,
-		b.slotsPerEpoch,
+		network.GenesisTime,
+		network.SecondsPerSlot,
+		network.SlotsPerEpoch,
 	)
 
 	return nil
 }
 
+// verifyNetwork checks that an endpoint is on the network forky expects, so
+// that frames from different networks are never mixed.
+func (b *BeaconNode) verifyNetwork(endpoint *beaconNodeEndpoint, network *ethereum.NetworkParameters) error {
+	if b.opts != nil && b.opts.NetworkVerifier != nil {
+		if err := b.opts.NetworkVerifier(network); err != nil {
+			return err
+		}
+	}
+
+	// Every endpoint of a source has to be on the same network too.
+	if b.network != nil {
+		if err := b.network.Verify(network); err != nil {
+			return err
+		}
+	}
+
+	endpoint.SetNetworkVerified(true)
+
+	return nil
+}
+
 func (b *BeaconNode) fetchFrame(ctx context.Context) error {
 	if !b.Ready(ctx) {
 		return errors.New("not ready to fetch frames")
@@ -257,52 +564,164 @@ func (b *BeaconNode) fetchFrame(ctx context.Context) error {
 		return perrors.Wrap(err, "failed to get current wallclock")
 	}
 
-	nodeVersionProvider, ok := b.client.(eth2client.NodeVersionProvider)
+	var errs []error
+
+	for _, endpoint := range b.endpoints.Ordered() {
+		frame, err := b.fetchFrameFromEndpoint(ctx, endpoint, phase0.Slot(slot.Number()), phase0.Epoch(epoch.Number()))
+		if err != nil {
+			b.endpoints.MarkFailure(endpoint, err)
+
+			errs = append(errs, perrors.Wrapf(err, "endpoint %s", endpoint.name))
+
+			continue
+		}
+
+		b.endpoints.MarkSuccess(endpoint)
+		b.endpoints.Used(endpoint)
+		b.status.RecordSuccess()
+
+		if frame == nil {
+			return nil
+		}
+
+		b.metrics.ObserveItemFetched(string(DataFrame))
+		b.status.RecordFrame()
+
+		b.publishFrame(ctx, frame)
+
+		b.log.WithFields(logrus.Fields{
+			"wallclock_slot":  slot.Number(),
+			"wallclock_epoch": epoch.Number(),
+			"fetchedAt":       frame.Metadata.FetchedAt,
+			"endpoint":        endpoint.name,
+		}).Debug("Fetched frame")
+
+		return nil
+	}
+
+	err = errors.Join(errs...)
+
+	b.status.RecordFailure(err)
+
+	return err
+}
+
+// fetchFrameFromEndpoint fetches a fork choice dump from a single endpoint. A nil
+// frame is returned if the endpoint doesn't support fetching fork choice dumps.
+func (b *BeaconNode) fetchFrameFromEndpoint(ctx context.Context, endpoint *beaconNodeEndpoint, slot phase0.Slot, epoch phase0.Epoch) (*types.Frame, error) {
+	client := endpoint.Client()
+
+	// Endpoints that weren't used to bootstrap are verified the first time
+	// they're used.
+	if !endpoint.NetworkVerified() {
+		network, err := ethereum.FetchNetworkParameters(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := b.verifyNetwork(endpoint, network); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeVersionProvider, ok := client.(eth2client.NodeVersionProvider)
 	if !ok {
-		return errors.New("client does not support node version provider")
+		return nil, errors.New("client does not support node version provider")
 	}
 
 	rsp, err := nodeVersionProvider.NodeVersion(ctx, &api.NodeVersionOpts{})
 	if err != nil {
-		return perrors.Wrap(err, "failed to get node version")
+		return nil, perrors.Wrap(err, "failed to get node version")
 	}
 
 	nodeVersion := rsp.Data
 
+	clientVersion := ethereum.ParseClientVersion(nodeVersion)
+
+	b.status.SetConsensusClient(string(clientVersion.Client), nodeVersion)
+
 	fetchedAt := time.Now()
 
-	if provider, isProvider := b.client.(eth2client.ForkChoiceProvider); isProvider {
-		rsp, err := provider.ForkChoice(ctx, &api.ForkChoiceOpts{})
-		if err != nil {
-			return perrors.Wrap(err, "failed to get fork choice dump")
-		}
+	// The node's context is captured alongside the fork choice dump so that
+	// both describe the node at the same moment.
+	nodeContextCh := make(chan *types.NodeContext, 1)
 
-		dump := rsp.Data
+	go func() {
+		nodeContextCh <- fetchNodeContext(ctx, b.log, client, nodeVersion)
+	}()
 
-		b.metrics.ObserveItemFetched(string(DataFrame))
+	forkChoice, extraData, rawPayload, err := b.fetchForkChoice(ctx, endpoint)
+
+	nodeContext := <-nodeContextCh
+
+	if err != nil {
+		return nil, err
+	}
 
-		frame := &types.Frame{
-			Metadata: types.FrameMetadata{
-				Node:            b.Name(),
-				FetchedAt:       fetchedAt,
-				WallClockSlot:   phase0.Slot(slot.Number()),
-				WallClockEpoch:  phase0.Epoch(epoch.Number()),
-				ID:              uuid.New().String(),
-				Labels:          b.config.Labels,
-				EventSource:     types.BeaconNodeEventSource.String(),
-				ConsensusClient: string(ethereum.ClientFromString(nodeVersion)),
-			},
-			Data: dump,
+	if forkChoice == nil {
+		return nil, nil
+	}
+
+	labels := append([]string{}, b.config.Labels...)
+
+	if len(b.config.Endpoints) > 0 {
+		labels = append(labels, beaconNodeEndpointLabelPrefix+endpoint.name)
+	}
+
+	frame := &types.Frame{
+		Metadata: types.FrameMetadata{
+			Node:                   b.Name(),
+			FetchedAt:              fetchedAt,
+			WallClockSlot:          slot,
+			WallClockEpoch:         epoch,
+			ID:                     uuid.New().String(),
+			Labels:                 labels,
+			EventSource:            types.BeaconNodeEventSource.String(),
+			ConsensusClient:        string(clientVersion.Client),
+			ConsensusClientVersion: clientVersion.Version,
+			NodeContext:            nodeContext,
+		},
+		Data:      forkChoice,
+		ExtraData: extraData,
+	}
+
+	if b.config.StoreRawPayload {
+		frame.RawPayload = rawPayload
+	}
+
+	return frame, nil
+}
+
+// fetchForkChoice fetches a fork choice dump, its top level extra data and the
+// verbatim response body from an endpoint. A nil dump is returned if the
+// endpoint doesn't support fetching fork choice dumps.
+func (b *BeaconNode) fetchForkChoice(ctx context.Context, endpoint *beaconNodeEndpoint) (*v1.ForkChoice, json.RawMessage, []byte, error) {
+	if forkChoiceClient := endpoint.ForkChoiceClient(); forkChoiceClient != nil {
+		ctx, cancel := context.WithTimeout(ctx, b.config.GetTimeout())
+		defer cancel()
+
+		body, err := forkChoiceClient.ForkChoice(ctx)
+		if err != nil {
+			return nil, nil, nil, perrors.Wrap(err, "failed to get fork choice dump")
 		}
 
-		b.publishFrame(ctx, frame)
+		dump, extraData, err := decodeForkChoice(body)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 
-		b.log.WithFields(logrus.Fields{
-			"wallclock_slot":  slot.Number(),
-			"wallclock_epoch": epoch.Number(),
-			"fetchedAt":       frame.Metadata.FetchedAt,
-		}).Debug("Fetched frame")
+		return dump, extraData, body, nil
 	}
 
-	return nil
+	provider, isProvider := endpoint.Client().(eth2client.ForkChoiceProvider)
+	if !isProvider {
+		return nil, nil, nil, nil
+	}
+
+	rsp, err := provider.ForkChoice(ctx, &api.ForkChoiceOpts{})
+	if err != nil {
+		return nil, nil, nil, perrors.Wrap(err, "failed to get fork choice dump")
+	}
+
+	return rsp.Data, nil, nil, nil
 }
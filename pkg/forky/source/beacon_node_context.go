@@ -0,0 +1,122 @@
+package source
+
+import (
+	"context"
+	"sync"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	api "github.com/attestantio/go-eth2-client/api"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/sirupsen/logrus"
+)
+
+// fetchNodeContext captures the state of a beacon node alongside a frame. It's
+// best effort: anything the node fails to provide is left unset rather than
+// failing the frame.
+func fetchNodeContext(ctx context.Context, log logrus.FieldLogger, client eth2client.Service, version string) *types.NodeContext {
+	nodeContext := &types.NodeContext{
+		Version: version,
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	fetch := func(name string, f func() error) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := f(); err != nil {
+				log.WithError(err).WithField("field", name).Debug("Failed to fetch node context")
+			}
+		}()
+	}
+
+	if provider, ok := client.(eth2client.NodePeersProvider); ok {
+		fetch("peer_count", func() error {
+			rsp, err := provider.NodePeers(ctx, &api.NodePeersOpts{State: []string{"connected"}})
+			if err != nil {
+				return err
+			}
+
+			peerCount := len(rsp.Data)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			nodeContext.PeerCount = &peerCount
+
+			return nil
+		})
+	}
+
+	if provider, ok := client.(eth2client.NodeSyncingProvider); ok {
+		fetch("sync_state", func() error {
+			rsp, err := provider.NodeSyncing(ctx, &api.NodeSyncingOpts{})
+			if err != nil {
+				return err
+			}
+
+			if rsp.Data == nil {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			nodeContext.IsSyncing = &rsp.Data.IsSyncing
+			nodeContext.IsOptimistic = &rsp.Data.IsOptimistic
+			nodeContext.SyncDistance = &rsp.Data.SyncDistance
+
+			return nil
+		})
+	}
+
+	if provider, ok := client.(eth2client.BeaconBlockHeadersProvider); ok {
+		fetch("head", func() error {
+			rsp, err := provider.BeaconBlockHeader(ctx, &api.BeaconBlockHeaderOpts{Block: "head"})
+			if err != nil {
+				return err
+			}
+
+			if rsp.Data == nil || rsp.Data.Header == nil || rsp.Data.Header.Message == nil {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			nodeContext.HeadRoot = &rsp.Data.Root
+			nodeContext.HeadSlot = &rsp.Data.Header.Message.Slot
+
+			return nil
+		})
+	}
+
+	if provider, ok := client.(eth2client.FinalityProvider); ok {
+		fetch("finalized_checkpoint", func() error {
+			rsp, err := provider.Finality(ctx, &api.FinalityOpts{State: "head"})
+			if err != nil {
+				return err
+			}
+
+			if rsp.Data == nil {
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			nodeContext.FinalizedCheckpoint = rsp.Data.Finalized
+
+			return nil
+		})
+	}
+
+	wg.Wait()
+
+	return nodeContext
+}
@@ -0,0 +1,278 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	eth2client "github.com/attestantio/go-eth2-client"
+	api "github.com/attestantio/go-eth2-client/api"
+	"github.com/sirupsen/logrus"
+)
+
+// BeaconNodeEndpointConfig is a single endpoint of a beacon node source.
+type BeaconNodeEndpointConfig struct {
+	// Name identifies the endpoint in logs and frame labels. Defaults to the
+	// redacted address.
+	Name    string `yaml:"name"`
+	Address string `yaml:"address"`
+}
+
+// beaconNodeEndpointLabelPrefix prefixes the label recording which endpoint a
+// frame was fetched from.
+const beaconNodeEndpointLabelPrefix = "beacon_node_endpoint_"
+
+// beaconNodeEndpoint tracks the client and health of a single endpoint.
+type beaconNodeEndpoint struct {
+	name    string
+	address string
+
+	client     eth2client.Service
+	forkChoice *forkChoiceClient
+
+	mu                  sync.RWMutex
+	healthy             bool
+	networkVerified     bool
+	consecutiveFailures int
+	lastError           error
+	lastCheckedAt       time.Time
+}
+
+func (e *beaconNodeEndpoint) Client() eth2client.Service {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.client
+}
+
+func (e *beaconNodeEndpoint) SetClient(client eth2client.Service) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.client = client
+}
+
+// ForkChoiceClient returns the client used to fetch raw fork choice dumps, or
+// nil if fork choice dumps should be fetched through Client.
+func (e *beaconNodeEndpoint) ForkChoiceClient() *forkChoiceClient {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.forkChoice
+}
+
+func (e *beaconNodeEndpoint) SetForkChoiceClient(client *forkChoiceClient) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.forkChoice = client
+}
+
+// NetworkVerified returns true once the endpoint is known to be on the right network.
+func (e *beaconNodeEndpoint) NetworkVerified() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.networkVerified
+}
+
+func (e *beaconNodeEndpoint) SetNetworkVerified(verified bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.networkVerified = verified
+}
+
+func (e *beaconNodeEndpoint) Healthy() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.healthy
+}
+
+// beaconNodeEndpoints is an ordered pool of endpoints that serve the same
+// logical beacon node. Requests go to the most preferred healthy endpoint.
+type beaconNodeEndpoints struct {
+	log logrus.FieldLogger
+
+	endpoints []*beaconNodeEndpoint
+
+	// failureThreshold is the number of consecutive failures before an
+	// endpoint is marked unhealthy.
+	failureThreshold int
+
+	mu     sync.Mutex
+	active string
+}
+
+func newBeaconNodeEndpoints(log logrus.FieldLogger, configs []BeaconNodeEndpointConfig, failureThreshold int) *beaconNodeEndpoints {
+	endpoints := make([]*beaconNodeEndpoint, 0, len(configs))
+
+	for _, config := range configs {
+		endpoints = append(endpoints, &beaconNodeEndpoint{
+			name:    config.Name,
+			address: config.Address,
+			// Endpoints are assumed healthy until proven otherwise.
+			healthy: true,
+		})
+	}
+
+	if failureThreshold <= 0 {
+		failureThreshold = 1
+	}
+
+	return &beaconNodeEndpoints{
+		log:              log,
+		endpoints:        endpoints,
+		failureThreshold: failureThreshold,
+	}
+}
+
+// Ordered returns the endpoints to try, healthy endpoints first in order of
+// preference followed by unhealthy endpoints as a last resort.
+func (p *beaconNodeEndpoints) Ordered() []*beaconNodeEndpoint {
+	healthy := make([]*beaconNodeEndpoint, 0, len(p.endpoints))
+	unhealthy := make([]*beaconNodeEndpoint, 0)
+
+	for _, endpoint := range p.endpoints {
+		if endpoint.Client() == nil {
+			continue
+		}
+
+		if endpoint.Healthy() {
+			healthy = append(healthy, endpoint)
+		} else {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// Used records that an endpoint served a request, logging failovers and failbacks.
+func (p *beaconNodeEndpoints) Used(endpoint *beaconNodeEndpoint) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.active == endpoint.name {
+		return
+	}
+
+	if p.active != "" {
+		p.log.
+			WithField("from", p.active).
+			WithField("to", endpoint.name).
+			Warn("Switched beacon node endpoint")
+	}
+
+	p.active = endpoint.name
+}
+
+// Status returns the health of every endpoint.
+func (p *beaconNodeEndpoints) Status() []EndpointStatus {
+	p.mu.Lock()
+	active := p.active
+	p.mu.Unlock()
+
+	statuses := make([]EndpointStatus, 0, len(p.endpoints))
+
+	for _, endpoint := range p.endpoints {
+		endpoint.mu.RLock()
+
+		status := EndpointStatus{
+			Name:                endpoint.name,
+			Healthy:             endpoint.healthy,
+			Active:              endpoint.name == active,
+			ConsecutiveFailures: endpoint.consecutiveFailures,
+		}
+
+		if endpoint.lastError != nil {
+			status.LastError = redactError(endpoint.lastError)
+		}
+
+		if !endpoint.lastCheckedAt.IsZero() {
+			lastCheckedAt := endpoint.lastCheckedAt
+			status.LastCheckedAt = &lastCheckedAt
+		}
+
+		endpoint.mu.RUnlock()
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+func (p *beaconNodeEndpoints) MarkSuccess(endpoint *beaconNodeEndpoint) {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	if !endpoint.healthy {
+		p.log.WithField("endpoint", endpoint.name).Info("Beacon node endpoint is healthy again")
+	}
+
+	endpoint.healthy = true
+	endpoint.consecutiveFailures = 0
+	endpoint.lastError = nil
+	endpoint.lastCheckedAt = time.Now()
+}
+
+func (p *beaconNodeEndpoints) MarkFailure(endpoint *beaconNodeEndpoint, err error) {
+	endpoint.mu.Lock()
+	defer endpoint.mu.Unlock()
+
+	endpoint.consecutiveFailures++
+	endpoint.lastError = err
+	endpoint.lastCheckedAt = time.Now()
+
+	if endpoint.healthy && endpoint.consecutiveFailures >= p.failureThreshold {
+		p.log.
+			WithError(err).
+			WithField("endpoint", endpoint.name).
+			Warn("Beacon node endpoint is unhealthy")
+
+		endpoint.healthy = false
+	}
+}
+
+// CheckHealth checks every endpoint. An endpoint is healthy if it responds and
+// isn't syncing, which allows failing back to preferred endpoints once they recover.
+func (p *beaconNodeEndpoints) CheckHealth(ctx context.Context) {
+	for _, endpoint := range p.endpoints {
+		client := endpoint.Client()
+		if client == nil {
+			continue
+		}
+
+		if err := checkBeaconNodeEndpoint(ctx, client); err != nil {
+			p.MarkFailure(endpoint, err)
+
+			continue
+		}
+
+		p.MarkSuccess(endpoint)
+	}
+}
+
+func checkBeaconNodeEndpoint(ctx context.Context, client eth2client.Service) error {
+	provider, ok := client.(eth2client.NodeSyncingProvider)
+	if !ok {
+		return errors.New("client does not support node syncing provider")
+	}
+
+	rsp, err := provider.NodeSyncing(ctx, &api.NodeSyncingOpts{})
+	if err != nil {
+		return err
+	}
+
+	if rsp == nil || rsp.Data == nil {
+		return errors.New("received nil response when fetching sync state")
+	}
+
+	if rsp.Data.IsSyncing {
+		return errors.New("node is syncing")
+	}
+
+	return nil
+}
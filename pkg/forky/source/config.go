@@ -5,7 +5,10 @@ import (
 )
 
 type Config struct {
-	Name   string          `yaml:"name"`
-	Type   string          `yaml:"type"`
-	Config yaml.RawMessage `yaml:"config"`
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// Network is the name of the network the source is on. Defaults to the
+	// default network.
+	Network string          `yaml:"network"`
+	Config  yaml.RawMessage `yaml:"config"`
 }
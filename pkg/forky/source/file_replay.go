@@ -0,0 +1,528 @@
+package source
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/archive"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/google/uuid"
+	perrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+var FileReplayType = "file_replay"
+
+// FileReplayMode controls how frames are emitted by the file replay source.
+type FileReplayMode string
+
+const (
+	// FileReplayModeAllAtOnce emits every frame as soon as the source starts.
+	FileReplayModeAllAtOnce FileReplayMode = "all_at_once"
+	// FileReplayModeReplay emits frames at their original cadence, divided by the configured speed.
+	FileReplayModeReplay FileReplayMode = "replay"
+)
+
+type FileReplayConfig struct {
+	// Path is a file or directory of frames to replay. Supported files are exported
+	// archives (.tar.gz, .tgz, .ndjson.gz), frames (.json, .json.gz) and raw
+	// beacon API debug/fork_choice responses (.json, .json.gz).
+	Path string `yaml:"path"`
+	// Mode is either "replay" (default) or "all_at_once".
+	Mode FileReplayMode `yaml:"mode"`
+	// Speed divides the delay between frames when replaying. Defaults to 1.
+	Speed float64 `yaml:"speed"`
+	// Loop restarts the replay once every frame has been emitted, a slot after
+	// the last one. Only supported in replay mode.
+	Loop bool `yaml:"loop"`
+	// KeepTimestamps emits frames with their original fetched_at and wall clock
+	// slot/epoch instead of rewriting them relative to now.
+	KeepTimestamps bool `yaml:"keep_timestamps"`
+	// Node overrides the node name of every frame. Raw fork choice dumps use the
+	// source name if this isn't set.
+	Node string `yaml:"node"`
+	// Labels are appended to the labels of every frame.
+	Labels []string `yaml:"labels"`
+}
+
+func (c *FileReplayConfig) Validate() error {
+	if c.Path == "" {
+		return errors.New("path is required")
+	}
+
+	if c.Mode != FileReplayModeReplay && c.Mode != FileReplayModeAllAtOnce {
+		return fmt.Errorf("invalid mode: %s", c.Mode)
+	}
+
+	if c.Speed <= 0 {
+		return errors.New("speed must be greater than 0")
+	}
+
+	// Every frame is emitted immediately, so looping would never stop to wait.
+	if c.Loop && c.Mode == FileReplayModeAllAtOnce {
+		return errors.New("loop is only supported in replay mode")
+	}
+
+	return nil
+}
+
+func (c *FileReplayConfig) setDefaults() {
+	if c.Mode == "" {
+		c.Mode = FileReplayModeReplay
+	}
+
+	if c.Speed == 0 {
+		c.Speed = 1
+	}
+}
+
+const (
+	// defaultFileReplaySecondsPerSlot and defaultFileReplaySlotsPerEpoch are
+	// used until the network's spec is known.
+	defaultFileReplaySecondsPerSlot = 12
+	defaultFileReplaySlotsPerEpoch  = 32
+)
+
+// FileReplay is a source that replays frames from files on disk.
+type FileReplay struct {
+	log logrus.FieldLogger
+
+	config *FileReplayConfig
+
+	name string
+
+	metrics *BasicMetrics
+
+	opts *Options
+
+	onFrameCallbacks []func(ctx context.Context, frame *types.Frame) error
+	onReorgCallbacks []func(ctx context.Context, reorg *types.Reorg) error
+
+	status *statusTracker
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewFileReplay(namespace, name string, log logrus.FieldLogger, config *FileReplayConfig, metrics *BasicMetrics, opts *Options) (*FileReplay, error) {
+	config.setDefaults()
+
+	if err := config.Validate(); err != nil {
+		return nil, perrors.Wrap(err, "invalid config")
+	}
+
+	return &FileReplay{
+		log: log.
+			WithField("source_name", name).
+			WithField("component", "source/file_replay"),
+		config:           config,
+		name:             name,
+		metrics:          metrics,
+		opts:             opts,
+		onFrameCallbacks: []func(ctx context.Context, frame *types.Frame) error{},
+		onReorgCallbacks: []func(ctx context.Context, reorg *types.Reorg) error{},
+		status:           newStatusTracker(0),
+	}, nil
+}
+
+func (f *FileReplay) Name() string {
+	return f.name
+}
+
+func (f *FileReplay) Type() string {
+	return FileReplayType
+}
+
+func (f *FileReplay) Start(ctx context.Context) error {
+	frames, err := f.loadFrames()
+	if err != nil {
+		err = perrors.Wrap(err, "failed to load frames")
+
+		f.status.RecordFailure(err)
+
+		return err
+	}
+
+	if len(frames) == 0 {
+		err = fmt.Errorf("no frames found in %s", f.config.Path)
+
+		f.status.RecordFailure(err)
+
+		return err
+	}
+
+	f.log.WithFields(logrus.Fields{
+		"frames": len(frames),
+		"mode":   f.config.Mode,
+		"speed":  f.config.Speed,
+	}).Info("Loaded frames for replay")
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	f.cancel = cancel
+
+	f.wg.Add(1)
+
+	go func() {
+		defer f.wg.Done()
+
+		for {
+			f.replay(ctx, frames)
+
+			if !f.config.Loop || ctx.Err() != nil {
+				// There's nothing left to replay.
+				f.status.RecordStopped()
+
+				return
+			}
+
+			// A pass over a single frame takes no time at all, so leave a slot
+			// between passes.
+			secondsPerSlot, _ := f.spec()
+
+			select {
+			case <-ctx.Done():
+				f.status.RecordStopped()
+
+				return
+			case <-time.After(time.Duration(secondsPerSlot) * time.Second):
+			}
+		}
+	}()
+
+	return nil
+}
+
+// spec returns the network's slot and epoch lengths, used to rewrite wall clock
+// slots and epochs.
+func (f *FileReplay) spec() (secondsPerSlot, slotsPerEpoch uint64) {
+	secondsPerSlot, slotsPerEpoch = defaultFileReplaySecondsPerSlot, defaultFileReplaySlotsPerEpoch
+
+	if f.opts == nil || f.opts.Spec == nil {
+		return secondsPerSlot, slotsPerEpoch
+	}
+
+	spec := f.opts.Spec()
+
+	if spec.SecondsPerSlot > 0 {
+		secondsPerSlot = spec.SecondsPerSlot
+	}
+
+	if spec.SlotsPerEpoch > 0 {
+		slotsPerEpoch = spec.SlotsPerEpoch
+	}
+
+	return secondsPerSlot, slotsPerEpoch
+}
+
+func (f *FileReplay) Stop(ctx context.Context) error {
+	if f.cancel != nil {
+		f.cancel()
+	}
+
+	f.wg.Wait()
+
+	f.status.RecordStopped()
+
+	return nil
+}
+
+func (f *FileReplay) Status() Status {
+	return f.status.Snapshot(f.name, FileReplayType)
+}
+
+func (f *FileReplay) OnFrame(callback func(ctx context.Context, frame *types.Frame) error) {
+	f.onFrameCallbacks = append(f.onFrameCallbacks, callback)
+}
+
+// OnReorg registers a reorg callback. Replayed files don't contain reorgs.
+func (f *FileReplay) OnReorg(callback func(ctx context.Context, reorg *types.Reorg) error) {
+	f.onReorgCallbacks = append(f.onReorgCallbacks, callback)
+}
+
+func (f *FileReplay) publishFrame(ctx context.Context, frame *types.Frame) {
+	for _, callback := range f.onFrameCallbacks {
+		go func(callback func(ctx context.Context, frame *types.Frame) error) {
+			if err := callback(ctx, frame); err != nil {
+				f.log.WithError(err).WithField("id", frame.Metadata.ID).Debug("Frame callback failed")
+			}
+		}(callback)
+	}
+}
+
+// replay emits a single pass over the frames. Frames must be sorted by fetched_at.
+func (f *FileReplay) replay(ctx context.Context, frames []*types.Frame) {
+	first := frames[0].Metadata.FetchedAt
+	last := frames[len(frames)-1].Metadata.FetchedAt
+	start := time.Now()
+
+	for _, original := range frames {
+		offset := time.Duration(float64(original.Metadata.FetchedAt.Sub(first)) / f.config.Speed)
+
+		fetchedAt := start.Add(offset)
+
+		if f.config.Mode == FileReplayModeReplay {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Until(fetchedAt)):
+			}
+		} else {
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Spread the frames out so the newest one lands on now.
+			fetchedAt = start.Add(-time.Duration(float64(last.Sub(original.Metadata.FetchedAt)) / f.config.Speed))
+		}
+
+		frame := f.prepareFrame(original, fetchedAt)
+
+		f.metrics.ObserveItemFetched(string(DataFrame))
+
+		f.publishFrame(ctx, frame)
+
+		f.status.RecordSuccess()
+		f.status.RecordFrame()
+
+		f.log.WithFields(logrus.Fields{
+			"id":             frame.Metadata.ID,
+			"wallclock_slot": frame.Metadata.WallClockSlot,
+		}).Debug("Replayed frame")
+	}
+}
+
+// prepareFrame copies the frame's metadata so it can be emitted as a new frame.
+func (f *FileReplay) prepareFrame(original *types.Frame, fetchedAt time.Time) *types.Frame {
+	metadata := original.Metadata
+
+	metadata.ID = uuid.New().String()
+	metadata.EventSource = types.FileReplayEventSource.String()
+	metadata.Labels = append(append([]string{}, original.Metadata.Labels...), f.config.Labels...)
+
+	if f.config.Node != "" {
+		metadata.Node = f.config.Node
+	}
+
+	if !f.config.KeepTimestamps {
+		seconds, slotsPerEpoch := f.spec()
+		secondsPerSlot := time.Duration(seconds) * time.Second
+
+		shift := int64(math.Round(float64(fetchedAt.Sub(original.Metadata.FetchedAt)) / float64(secondsPerSlot)))
+
+		slot := int64(original.Metadata.WallClockSlot) + shift
+		if slot < 0 {
+			slot = 0
+		}
+
+		metadata.FetchedAt = fetchedAt
+		metadata.WallClockSlot = phase0.Slot(slot)
+		metadata.WallClockEpoch = phase0.Epoch(uint64(slot) / slotsPerEpoch)
+	}
+
+	return &types.Frame{
+		Data:      original.Data,
+		Metadata:  metadata,
+		ExtraData: original.ExtraData,
+	}
+}
+
+// loadFrames reads every supported file under the configured path and returns the
+// frames sorted by fetched_at.
+func (f *FileReplay) loadFrames() ([]*types.Frame, error) {
+	info, err := os.Stat(f.config.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	if info.IsDir() {
+		err = filepath.WalkDir(f.config.Path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		files = append(files, f.config.Path)
+	}
+
+	frames := []*types.Frame{}
+
+	for _, file := range files {
+		loaded, err := f.loadFile(file)
+		if err != nil {
+			return nil, perrors.Wrapf(err, "failed to load %s", file)
+		}
+
+		frames = append(frames, loaded...)
+	}
+
+	sort.SliceStable(frames, func(i, j int) bool {
+		return frames[i].Metadata.FetchedAt.Before(frames[j].Metadata.FetchedAt)
+	})
+
+	return frames, nil
+}
+
+func (f *FileReplay) loadFile(path string) ([]*types.Frame, error) {
+	if format, err := archive.FormatFromFilename(path); err == nil {
+		return f.loadArchive(path, format)
+	}
+
+	var data []byte
+
+	switch {
+	case strings.HasSuffix(path, ".json.gz"):
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		data, err = io.ReadAll(gz)
+		if err != nil {
+			return nil, err
+		}
+	case strings.HasSuffix(path, ".json"):
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		data = raw
+	default:
+		f.log.WithField("file", path).Debug("Skipping unsupported file")
+
+		return nil, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	frame, err := f.decodeJSON(data, info.ModTime())
+	if err != nil {
+		return nil, err
+	}
+
+	return []*types.Frame{frame}, nil
+}
+
+func (f *FileReplay) loadArchive(path string, format archive.Format) ([]*types.Frame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := archive.NewReader(file, format)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	frames := []*types.Frame{}
+
+	for {
+		frame, err := reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return frames, nil
+			}
+
+			return nil, err
+		}
+
+		frames = append(frames, frame)
+	}
+}
+
+// decodeJSON decodes either a forky frame or a raw beacon API debug/fork_choice
+// response. Raw responses have no metadata, so the file's modification time is
+// used as the fetched_at and the highest slot in the dump as the wall clock slot.
+func (f *FileReplay) decodeJSON(data []byte, modTime time.Time) (*types.Frame, error) {
+	var probe map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	if _, ok := probe["metadata"]; ok {
+		frame := &types.Frame{}
+
+		if err := frame.FromJSON(data); err != nil {
+			return nil, err
+		}
+
+		return frame, nil
+	}
+
+	// The beacon API response may or may not be wrapped in a "data" envelope.
+	if wrapped, ok := probe["data"]; ok {
+		data = wrapped
+	}
+
+	dump, extraData, err := decodeForkChoice(data)
+	if err != nil {
+		return nil, err
+	}
+
+	node := f.config.Node
+	if node == "" {
+		node = f.name
+	}
+
+	_, slotsPerEpoch := f.spec()
+
+	slot := phase0.Slot(0)
+
+	for _, n := range dump.ForkChoiceNodes {
+		if n.Slot > slot {
+			slot = n.Slot
+		}
+	}
+
+	return &types.Frame{
+		Data:      dump,
+		ExtraData: extraData,
+		Metadata: types.FrameMetadata{
+			ID:              uuid.New().String(),
+			Node:            node,
+			FetchedAt:       modTime,
+			WallClockSlot:   slot,
+			WallClockEpoch:  phase0.Epoch(uint64(slot) / slotsPerEpoch),
+			Labels:          []string{},
+			ConsensusClient: "unknown",
+		},
+	}, nil
+}
@@ -0,0 +1,183 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileReplay(t *testing.T) {
+	t.Run("all at once rewrites timestamps", func(t *testing.T) {
+		dir := t.TempDir()
+
+		base := time.Now().Add(-24 * time.Hour)
+
+		for i := 0; i < 3; i++ {
+			frame := types.GenerateFakeFrame()
+			frame.Metadata.FetchedAt = base.Add(time.Duration(i) * 12 * time.Second)
+			frame.Metadata.WallClockSlot = 100
+
+			data, err := frame.AsJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(filepath.Join(dir, frame.Metadata.ID+".json"), data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Raw beacon API dump.
+		raw := `{"justified_checkpoint":{"epoch":"1","root":"0x0000000000000000000000000000000000000000000000000000000000000000"},"finalized_checkpoint":{"epoch":"0","root":"0x0000000000000000000000000000000000000000000000000000000000000000"},"fork_choice_nodes":[],"extra_data":{}}`
+		if err := os.WriteFile(filepath.Join(dir, "raw.json"), []byte(`{"data":`+raw+`}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Raw dumps use the file's modification time as their fetched_at.
+		modTime := base.Add(36 * time.Second)
+		if err := os.Chtimes(filepath.Join(dir, "raw.json"), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+
+		source, err := NewFileReplay("forky_test", "replay", logrus.New(), &FileReplayConfig{
+			Path: dir,
+			Mode: FileReplayModeAllAtOnce,
+		}, NewBasicMetrics("forky_test", FileReplayType, "replay", false), &Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mu := sync.Mutex{}
+		received := []*types.Frame{}
+
+		source.OnFrame(func(ctx context.Context, frame *types.Frame) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			received = append(received, frame)
+
+			return nil
+		})
+
+		if err := source.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(received) == 4
+		}, 5*time.Second, 10*time.Millisecond)
+
+		if err := source.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, frame := range received {
+			assert.NoError(t, frame.Validate())
+			assert.Equal(t, types.FileReplayEventSource.String(), frame.Metadata.EventSource)
+			assert.WithinDuration(t, time.Now(), frame.Metadata.FetchedAt, time.Minute)
+		}
+	})
+
+	t.Run("loops a slot apart", func(t *testing.T) {
+		dir := t.TempDir()
+
+		frame := types.GenerateFakeFrame()
+		frame.Metadata.FetchedAt = time.Now()
+		frame.Metadata.WallClockSlot = 100
+		frame.ExtraData = []byte(`{"proposer_boost_root":"0x01"}`)
+
+		data, err := frame.AsJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(dir, frame.Metadata.ID+".json"), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		source, err := NewFileReplay("forky_test", "replay", logrus.New(), &FileReplayConfig{
+			Path: dir,
+			Loop: true,
+		}, NewBasicMetrics("forky_test", FileReplayType, "replay", false), &Options{
+			Spec: func() *ethereum.SpecConfig {
+				return &ethereum.SpecConfig{SecondsPerSlot: 1, SlotsPerEpoch: 4}
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mu := sync.Mutex{}
+		received := []*types.Frame{}
+
+		source.OnFrame(func(ctx context.Context, frame *types.Frame) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			received = append(received, frame)
+
+			return nil
+		})
+
+		if err := source.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(received) >= 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		if err := source.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// A single frame is replayed once per slot rather than continuously.
+		assert.Less(t, len(received), 4)
+		assert.GreaterOrEqual(t, received[1].Metadata.FetchedAt.Sub(received[0].Metadata.FetchedAt), time.Second)
+
+		// Wall clock slots and epochs are rewritten with the network's spec.
+		assert.Equal(t, received[0].Metadata.WallClockSlot+1, received[1].Metadata.WallClockSlot)
+		assert.Equal(t, phase0.Epoch(uint64(received[1].Metadata.WallClockSlot)/4), received[1].Metadata.WallClockEpoch)
+		assert.NotEqual(t, received[0].Metadata.ID, received[1].Metadata.ID)
+
+		// The dump's top level extra data is replayed with it.
+		for _, replayed := range received {
+			assert.JSONEq(t, string(frame.ExtraData), string(replayed.ExtraData))
+		}
+	})
+
+	t.Run("loop requires replay mode", func(t *testing.T) {
+		_, err := NewFileReplay("forky_test", "replay", logrus.New(), &FileReplayConfig{
+			Path: t.TempDir(),
+			Mode: FileReplayModeAllAtOnce,
+			Loop: true,
+		}, NewBasicMetrics("forky_test", FileReplayType, "replay", false), &Options{})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := NewFileReplay("forky_test", "replay", logrus.New(), &FileReplayConfig{
+			Path: t.TempDir(),
+			Mode: "bogus",
+		}, NewBasicMetrics("forky_test", FileReplayType, "replay", false), &Options{})
+		assert.Error(t, err)
+	})
+}
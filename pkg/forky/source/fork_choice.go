@@ -0,0 +1,114 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/url"
+	"strings"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	perrors "github.com/pkg/errors"
+)
+
+const forkChoiceEndpoint = "/eth/v1/debug/fork_choice"
+
+// decodeForkChoice decodes a beacon API debug/fork_choice response. The top
+// level extra_data is returned verbatim as go-eth2-client drops it.
+func decodeForkChoice(data []byte) (*v1.ForkChoice, json.RawMessage, error) {
+	dump := &v1.ForkChoice{}
+
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(dump); err != nil {
+		return nil, nil, perrors.Wrap(err, "failed to decode fork choice dump")
+	}
+
+	var extra struct {
+		ExtraData json.RawMessage `json:"extra_data"`
+	}
+
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, nil, perrors.Wrap(err, "failed to decode fork choice extra data")
+	}
+
+	if bytes.Equal(bytes.TrimSpace(extra.ExtraData), []byte("null")) {
+		return dump, nil, nil
+	}
+
+	return dump, extra.ExtraData, nil
+}
+
+// forkChoiceClient fetches fork choice dumps straight from a beacon node rather
+// than through go-eth2-client, so that the response body is available verbatim.
+type forkChoiceClient struct {
+	address string
+	headers map[string]string
+	client  *nethttp.Client
+}
+
+func newForkChoiceClient(address string, headers map[string]string, client *nethttp.Client) *forkChoiceClient {
+	return &forkChoiceClient{
+		address: address,
+		headers: headers,
+		client:  client,
+	}
+}
+
+// ForkChoice returns the raw debug/fork_choice response body.
+func (c *forkChoiceClient) ForkChoice(ctx context.Context) ([]byte, error) {
+	u, err := url.Parse(c.address)
+	if err != nil {
+		return nil, perrors.Wrap(err, "invalid address")
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + forkChoiceEndpoint
+
+	user := u.User
+	u.User = nil
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, u.String(), nethttp.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	if user != nil {
+		password, _ := user.Password()
+
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return nil, perrors.Wrap(err, "failed to request fork choice dump")
+	}
+
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, perrors.Wrap(err, "failed to read fork choice dump")
+	}
+
+	if rsp.StatusCode != nethttp.StatusOK {
+		if len(body) > 256 {
+			body = body[:256]
+		}
+
+		return nil, fmt.Errorf("unexpected status code %d: %s", rsp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if len(body) == 0 {
+		return nil, errors.New("empty fork choice dump")
+	}
+
+	return body, nil
+}
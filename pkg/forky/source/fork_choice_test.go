@@ -0,0 +1,89 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/stretchr/testify/assert"
+)
+
+const testForkChoiceDump = `{
+  "justified_checkpoint": {"epoch": "1", "root": "0x0100000000000000000000000000000000000000000000000000000000000000"},
+  "finalized_checkpoint": {"epoch": "0", "root": "0x0000000000000000000000000000000000000000000000000000000000000000"},
+  "fork_choice_nodes": [
+    {
+      "slot": "32",
+      "block_root": "0x0200000000000000000000000000000000000000000000000000000000000000",
+      "parent_root": "0x0100000000000000000000000000000000000000000000000000000000000000",
+      "justified_epoch": "1",
+      "finalized_epoch": "0",
+      "weight": "12345678901234567890",
+      "validity": "valid",
+      "execution_block_hash": "0x0300000000000000000000000000000000000000000000000000000000000000",
+      "extra_data": {"unrealized_justified_epoch": "1", "execution_status": "Valid"}
+    }
+  ],
+  "extra_data": {"proposer_boost_root": "0x0200000000000000000000000000000000000000000000000000000000000000", "equivocating_indices": [1, 2]}
+}`
+
+func TestDecodeForkChoice(t *testing.T) {
+	dump, extraData, err := decodeForkChoice([]byte(testForkChoiceDump))
+	assert.NoError(t, err)
+	assert.Len(t, dump.ForkChoiceNodes, 1)
+	assert.Equal(t, uint64(12345678901234567890), dump.ForkChoiceNodes[0].Weight)
+	assert.Equal(t, "Valid", dump.ForkChoiceNodes[0].ExtraData["execution_status"])
+	assert.JSONEq(t, `{"proposer_boost_root": "0x0200000000000000000000000000000000000000000000000000000000000000", "equivocating_indices": [1, 2]}`, string(extraData))
+
+	// Extra data survives being stored with the frame.
+	frame := types.GenerateFakeFrame()
+	frame.Data = dump
+	frame.ExtraData = extraData
+
+	encoded, err := frame.AsGzipJSON()
+	assert.NoError(t, err)
+
+	decoded := &types.Frame{}
+	assert.NoError(t, decoded.FromGzipJSON(encoded))
+	assert.JSONEq(t, string(extraData), string(decoded.ExtraData))
+	assert.Equal(t, "Valid", decoded.Data.ForkChoiceNodes[0].ExtraData["execution_status"])
+
+	_, extraData, err = decodeForkChoice([]byte(strings.Replace(testForkChoiceDump, `"extra_data": {"proposer_boost_root"`, `"ignored": {"proposer_boost_root"`, 1)))
+	assert.NoError(t, err)
+	assert.Nil(t, extraData)
+
+	_, _, err = decodeForkChoice([]byte(`{"extra_data": {}}`))
+	assert.Error(t, err)
+}
+
+func TestForkChoiceClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+
+		if r.URL.Path != "/prefix/eth/v1/debug/fork_choice" || username != "user" || password != "pass" || r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code": 404, "message": "not found"}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(testForkChoiceDump))
+	}))
+	defer server.Close()
+
+	address := strings.Replace(server.URL, "http://", "http://user:pass@", 1) + "/prefix/"
+
+	client := newForkChoiceClient(address, map[string]string{"X-Api-Key": "secret"}, server.Client())
+
+	body, err := client.ForkChoice(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, testForkChoiceDump, string(body))
+
+	client = newForkChoiceClient(server.URL, nil, server.Client())
+
+	_, err = client.ForkChoice(context.Background())
+	assert.ErrorContains(t, err, "404")
+}
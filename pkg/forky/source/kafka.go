@@ -0,0 +1,509 @@
+package source
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/ethpandaops/xatu/pkg/proto/xatu"
+	"github.com/sirupsen/logrus"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var KafkaType = "kafka"
+
+// KafkaEncoding is the encoding of the DecoratedEvent messages on a topic.
+type KafkaEncoding string
+
+const (
+	KafkaEncodingProtobuf KafkaEncoding = "protobuf"
+	KafkaEncodingJSON     KafkaEncoding = "json"
+)
+
+// KafkaResetOffset is where a consumer group without committed offsets starts consuming.
+type KafkaResetOffset string
+
+const (
+	KafkaResetOffsetEarliest KafkaResetOffset = "earliest"
+	KafkaResetOffsetLatest   KafkaResetOffset = "latest"
+)
+
+// KafkaSASLMechanism is the SASL mechanism used to authenticate with the brokers.
+type KafkaSASLMechanism string
+
+const (
+	KafkaSASLMechanismPlain       KafkaSASLMechanism = "plain"
+	KafkaSASLMechanismScramSHA256 KafkaSASLMechanism = "scram-sha-256"
+	KafkaSASLMechanismScramSHA512 KafkaSASLMechanism = "scram-sha-512"
+)
+
+type KafkaSASLConfig struct {
+	Mechanism KafkaSASLMechanism `yaml:"mechanism"`
+	Username  string             `yaml:"username"`
+	Password  string             `yaml:"password"`
+}
+
+type KafkaConfig struct {
+	// Brokers are the seed brokers to connect to.
+	Brokers []string `yaml:"brokers"`
+	// Topics are the topics to consume xatu events from.
+	Topics []string `yaml:"topics"`
+	// ConsumerGroup is the consumer group to join. Offsets are committed to it
+	// once the frames in a batch of records have been persisted.
+	ConsumerGroup string `yaml:"consumer_group"`
+	// Encoding is either "protobuf" (default) or "json".
+	Encoding KafkaEncoding `yaml:"encoding"`
+	// ClientID is the client ID sent to the brokers. Defaults to "forky".
+	ClientID string `yaml:"client_id"`
+	// ResetOffset is either "latest" (default) or "earliest".
+	ResetOffset KafkaResetOffset `yaml:"reset_offset"`
+	// MaxPollRecords is the maximum number of records processed per batch. Defaults to 100.
+	MaxPollRecords int `yaml:"max_poll_records"`
+	// TLS enables TLS when connecting to the brokers.
+	TLS bool `yaml:"tls"`
+	// SASL configures SASL authentication. Disabled if nil.
+	SASL *KafkaSASLConfig `yaml:"sasl"`
+}
+
+func (c *KafkaConfig) Validate() error {
+	if len(c.Brokers) == 0 {
+		return errors.New("at least one broker is required")
+	}
+
+	if len(c.Topics) == 0 {
+		return errors.New("at least one topic is required")
+	}
+
+	if c.ConsumerGroup == "" {
+		return errors.New("consumer_group is required")
+	}
+
+	switch c.Encoding {
+	case "", KafkaEncodingProtobuf, KafkaEncodingJSON:
+	default:
+		return fmt.Errorf("invalid encoding: %s", c.Encoding)
+	}
+
+	switch c.ResetOffset {
+	case "", KafkaResetOffsetEarliest, KafkaResetOffsetLatest:
+	default:
+		return fmt.Errorf("invalid reset_offset: %s", c.ResetOffset)
+	}
+
+	if c.MaxPollRecords < 0 {
+		return errors.New("max_poll_records must be positive")
+	}
+
+	if c.SASL != nil {
+		switch c.SASL.Mechanism {
+		case KafkaSASLMechanismPlain, KafkaSASLMechanismScramSHA256, KafkaSASLMechanismScramSHA512:
+		default:
+			return fmt.Errorf("invalid sasl mechanism: %s", c.SASL.Mechanism)
+		}
+
+		if c.SASL.Username == "" || c.SASL.Password == "" {
+			return errors.New("sasl username and password are required")
+		}
+	}
+
+	return nil
+}
+
+// kafkaConsumer is the subset of a kafka client the source needs. It allows the
+// source to be tested without a broker.
+type kafkaConsumer interface {
+	// Poll returns the next batch of records. Records may be returned alongside
+	// an error if some partitions failed to fetch.
+	Poll(ctx context.Context, maxRecords int) ([]*kgo.Record, error)
+	// Commit commits the offsets of the records.
+	Commit(ctx context.Context, records []*kgo.Record) error
+	// AllowRebalance allows rebalances that were blocked by the last poll.
+	AllowRebalance()
+	// Close leaves the consumer group and closes the consumer.
+	Close()
+}
+
+// kgoConsumer is a kafkaConsumer backed by a franz-go client.
+type kgoConsumer struct {
+	client *kgo.Client
+}
+
+func (k *kgoConsumer) Poll(ctx context.Context, maxRecords int) ([]*kgo.Record, error) {
+	fetches := k.client.PollRecords(ctx, maxRecords)
+	if fetches.IsClientClosed() {
+		return nil, kgo.ErrClientClosed
+	}
+
+	var errs []error
+
+	for _, fetchErr := range fetches.Errors() {
+		errs = append(errs, fmt.Errorf("topic %s partition %d: %w", fetchErr.Topic, fetchErr.Partition, fetchErr.Err))
+	}
+
+	return fetches.Records(), errors.Join(errs...)
+}
+
+func (k *kgoConsumer) Commit(ctx context.Context, records []*kgo.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	return k.client.CommitRecords(ctx, records...)
+}
+
+func (k *kgoConsumer) AllowRebalance() {
+	k.client.AllowRebalance()
+}
+
+func (k *kgoConsumer) Close() {
+	k.client.Close()
+}
+
+// Kafka is a source that consumes xatu DecoratedEvents from kafka topics.
+type Kafka struct {
+	*xatuEventHandler
+
+	log logrus.FieldLogger
+
+	config *KafkaConfig
+
+	name string
+
+	metrics *BasicMetrics
+
+	consumer kafkaConsumer
+
+	decode       func(value []byte) (*xatu.DecoratedEvent, error)
+	handleEvents func(ctx context.Context, events []*xatu.DecoratedEvent) error
+
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewKafka(namespace, name string, log logrus.FieldLogger, config *KafkaConfig, metrics *BasicMetrics, opts *Options) (*Kafka, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	clientOpts, err := config.clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kgo.NewClient(clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	return newKafka(name, log, config, metrics, opts, &kgoConsumer{client: client})
+}
+
+func newKafka(name string, log logrus.FieldLogger, config *KafkaConfig, metrics *BasicMetrics, opts *Options, consumer kafkaConsumer) (*Kafka, error) {
+	if config.Encoding == "" {
+		config.Encoding = KafkaEncodingProtobuf
+	}
+
+	if config.MaxPollRecords == 0 {
+		config.MaxPollRecords = 100
+	}
+
+	log = log.
+		WithField("source_name", name).
+		WithField("component", "source/kafka")
+
+	handler, err := newXatuEventHandler(log, opts, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Kafka{
+		xatuEventHandler: handler,
+		log:              log,
+		config:           config,
+		name:             name,
+		metrics:          metrics,
+		consumer:         consumer,
+		retryInterval:    time.Second,
+		maxRetryInterval: 30 * time.Second,
+	}
+
+	k.handleEvents = k.handleXatuEvents
+
+	switch config.Encoding {
+	case KafkaEncodingJSON:
+		k.decode = decodeJSONEvent
+	default:
+		k.decode = decodeProtobufEvent
+	}
+
+	return k, nil
+}
+
+func (c *KafkaConfig) clientOptions() ([]kgo.Opt, error) {
+	clientID := c.ClientID
+	if clientID == "" {
+		clientID = "forky"
+	}
+
+	resetOffset := kgo.NewOffset().AtEnd()
+	if c.ResetOffset == KafkaResetOffsetEarliest {
+		resetOffset = kgo.NewOffset().AtStart()
+	}
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(c.Brokers...),
+		kgo.ClientID(clientID),
+		kgo.ConsumerGroup(c.ConsumerGroup),
+		kgo.ConsumeTopics(c.Topics...),
+		kgo.ConsumeResetOffset(resetOffset),
+		kgo.DisableAutoCommit(),
+		kgo.BlockRebalanceOnPoll(),
+	}
+
+	if c.TLS {
+		opts = append(opts, kgo.DialTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+
+	if c.SASL != nil {
+		var mechanism sasl.Mechanism
+
+		switch c.SASL.Mechanism {
+		case KafkaSASLMechanismPlain:
+			mechanism = plain.Auth{User: c.SASL.Username, Pass: c.SASL.Password}.AsMechanism()
+		case KafkaSASLMechanismScramSHA256:
+			mechanism = scram.Auth{User: c.SASL.Username, Pass: c.SASL.Password}.AsSha256Mechanism()
+		case KafkaSASLMechanismScramSHA512:
+			mechanism = scram.Auth{User: c.SASL.Username, Pass: c.SASL.Password}.AsSha512Mechanism()
+		default:
+			return nil, fmt.Errorf("invalid sasl mechanism: %s", c.SASL.Mechanism)
+		}
+
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+
+	return opts, nil
+}
+
+func decodeProtobufEvent(value []byte) (*xatu.DecoratedEvent, error) {
+	event := &xatu.DecoratedEvent{}
+
+	if err := proto.Unmarshal(value, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func decodeJSONEvent(value []byte) (*xatu.DecoratedEvent, error) {
+	event := &xatu.DecoratedEvent{}
+
+	if err := protojson.Unmarshal(value, event); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (k *Kafka) Name() string {
+	return k.name
+}
+
+func (k *Kafka) Type() string {
+	return KafkaType
+}
+
+func (k *Kafka) Status() Status {
+	return k.status.Snapshot(k.name, KafkaType)
+}
+
+func (k *Kafka) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	k.cancel = cancel
+
+	k.log.
+		WithField("brokers", k.config.Brokers).
+		WithField("topics", k.config.Topics).
+		WithField("consumer_group", k.config.ConsumerGroup).
+		Info("Starting kafka source")
+
+	k.wg.Add(1)
+
+	go func() {
+		defer k.wg.Done()
+
+		k.consume(ctx)
+	}()
+
+	return nil
+}
+
+func (k *Kafka) Stop(ctx context.Context) error {
+	defer k.status.RecordStopped()
+
+	if k.cancel != nil {
+		k.cancel()
+	}
+
+	stopped := make(chan struct{})
+
+	go func() {
+		k.wg.Wait()
+
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		k.log.Warn("Timed out waiting for kafka consumer to stop")
+	}
+
+	k.consumer.Close()
+
+	return nil
+}
+
+func (k *Kafka) consume(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !k.consumeBatch(ctx) {
+			return
+		}
+	}
+}
+
+// consumeBatch polls for a batch of records, processes them and commits their
+// offsets. Returns false once the consumer should stop.
+func (k *Kafka) consumeBatch(ctx context.Context) bool {
+	records, err := k.consumer.Poll(ctx, k.config.MaxPollRecords)
+
+	// Rebalances are blocked from the poll until the batch has been committed so
+	// that offsets are never committed for partitions that have been revoked.
+	// They're allowed again however the batch ends.
+	defer k.consumer.AllowRebalance()
+
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, kgo.ErrClientClosed) {
+			return false
+		}
+
+		k.log.WithError(err).Error("Failed to fetch records from kafka")
+
+		k.status.RecordFailure(err)
+
+		if len(records) == 0 {
+			return k.sleep(ctx, k.retryInterval)
+		}
+	}
+
+	if !k.processRecords(ctx, records) {
+		return false
+	}
+
+	if err := k.consumer.Commit(ctx, records); err != nil {
+		// The records will be redelivered to whichever consumer owns the
+		// partition next.
+		k.log.WithError(err).Error("Failed to commit kafka offsets")
+	}
+
+	return true
+}
+
+// processRecords handles a batch of records, retrying each event until its frames
+// have been persisted. Events that are invalid are dropped, since they'll never
+// succeed. Returns false if the context was cancelled before it finished.
+func (k *Kafka) processRecords(ctx context.Context, records []*kgo.Record) bool {
+	events := make([]*xatu.DecoratedEvent, 0, len(records))
+
+	for _, record := range records {
+		k.metrics.ObserveItemFetched("record")
+
+		event, err := k.decode(record.Value)
+		if err != nil {
+			// Undecodable records will never succeed, so they're skipped rather
+			// than blocking the partition.
+			k.metrics.ObserveItemFetched("invalid_record")
+
+			k.log.
+				WithError(err).
+				WithField("topic", record.Topic).
+				WithField("partition", record.Partition).
+				WithField("offset", record.Offset).
+				Warn("Failed to decode kafka record")
+
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	// Events are retried individually so that events that have already been
+	// persisted aren't handled again. Frames are given IDs derived from their
+	// event, so an event that was partly persisted, e.g. one side of a reorg,
+	// doesn't duplicate the frames that made it.
+	for _, event := range events {
+		if !k.processEvent(ctx, event) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (k *Kafka) processEvent(ctx context.Context, event *xatu.DecoratedEvent) bool {
+	back := backoff.NewExponentialBackOff()
+
+	back.InitialInterval = k.retryInterval
+	back.MaxInterval = k.maxRetryInterval
+	back.MaxElapsedTime = 0
+
+	for {
+		err := k.handleEvents(ctx, []*xatu.DecoratedEvent{event})
+		if err == nil {
+			return true
+		}
+
+		// Only storage failures are worth retrying. Anything else would block
+		// the partition forever.
+		if !errors.Is(err, errFailedToPersist) {
+			k.metrics.ObserveItemFetched("dropped_event")
+
+			k.log.WithError(err).Warn("Dropping kafka event that can't be persisted")
+
+			return true
+		}
+
+		sleepFor := back.NextBackOff()
+
+		k.log.WithError(err).WithField("next_attempt_in", sleepFor.String()).Error("Failed to persist event from kafka")
+
+		if !k.sleep(ctx, sleepFor) {
+			return false
+		}
+	}
+}
+
+func (k *Kafka) sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
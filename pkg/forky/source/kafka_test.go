@@ -0,0 +1,340 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/xatu/pkg/proto/xatu"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// fakeKafkaConsumer is an in-process stand-in for a kafka broker that serves
+// pre-defined batches of records and records committed offsets.
+type fakeKafkaConsumer struct {
+	mu sync.Mutex
+
+	batches   [][]*kgo.Record
+	pollErrs  []error
+	committed []*kgo.Record
+	closed    bool
+
+	polls           int
+	rebalanceAllows int
+}
+
+func (f *fakeKafkaConsumer) Poll(ctx context.Context, _ int) ([]*kgo.Record, error) {
+	f.mu.Lock()
+
+	f.polls++
+
+	if len(f.pollErrs) > 0 {
+		err := f.pollErrs[0]
+		f.pollErrs = f.pollErrs[1:]
+
+		f.mu.Unlock()
+
+		return nil, err
+	}
+
+	if len(f.batches) > 0 {
+		batch := f.batches[0]
+		f.batches = f.batches[1:]
+
+		f.mu.Unlock()
+
+		return batch, nil
+	}
+
+	f.mu.Unlock()
+
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (f *fakeKafkaConsumer) Commit(_ context.Context, records []*kgo.Record) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.committed = append(f.committed, records...)
+
+	return nil
+}
+
+func (f *fakeKafkaConsumer) AllowRebalance() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rebalanceAllows++
+}
+
+// rebalancesBlocked returns whether a poll has blocked rebalances that haven't
+// been allowed again.
+func (f *fakeKafkaConsumer) rebalancesBlocked() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.polls != f.rebalanceAllows
+}
+
+func (f *fakeKafkaConsumer) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+}
+
+func (f *fakeKafkaConsumer) committedOffsets() []int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	offsets := []int64{}
+	for _, record := range f.committed {
+		offsets = append(offsets, record.Offset)
+	}
+
+	return offsets
+}
+
+func newTestKafka(t *testing.T, consumer *fakeKafkaConsumer, handle func(events []*xatu.DecoratedEvent) error) *Kafka {
+	t.Helper()
+
+	k, err := newKafka("kafka", logrus.New(), &KafkaConfig{
+		Brokers:       []string{"localhost:9092"},
+		Topics:        []string{"events"},
+		ConsumerGroup: "forky",
+	}, NewBasicMetrics("forky_test", KafkaType, "kafka", false), &Options{}, consumer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k.retryInterval = time.Millisecond
+	k.maxRetryInterval = 5 * time.Millisecond
+
+	k.decode = func(value []byte) (*xatu.DecoratedEvent, error) {
+		if string(value) == "invalid" {
+			return nil, errors.New("invalid record")
+		}
+
+		return &xatu.DecoratedEvent{}, nil
+	}
+
+	k.handleEvents = func(_ context.Context, events []*xatu.DecoratedEvent) error {
+		return handle(events)
+	}
+
+	return k
+}
+
+func TestKafka(t *testing.T) {
+	t.Run("commits offsets after events are persisted", func(t *testing.T) {
+		consumer := &fakeKafkaConsumer{
+			batches: [][]*kgo.Record{
+				{{Offset: 1, Value: []byte("a")}, {Offset: 2, Value: []byte("b")}},
+				{{Offset: 3, Value: []byte("c")}},
+			},
+		}
+
+		mu := sync.Mutex{}
+		handled := 0
+
+		k := newTestKafka(t, consumer, func(events []*xatu.DecoratedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			handled += len(events)
+
+			return nil
+		})
+
+		assert.NoError(t, k.Start(context.Background()))
+
+		assert.Eventually(t, func() bool {
+			return len(consumer.committedOffsets()) == 3
+		}, time.Second, time.Millisecond)
+
+		assert.NoError(t, k.Stop(context.Background()))
+
+		mu.Lock()
+		assert.Equal(t, 3, handled)
+		mu.Unlock()
+
+		assert.Equal(t, []int64{1, 2, 3}, consumer.committedOffsets())
+		assert.True(t, consumer.closed)
+	})
+
+	t.Run("retries events that fail to persist before committing", func(t *testing.T) {
+		consumer := &fakeKafkaConsumer{
+			batches: [][]*kgo.Record{
+				{{Offset: 1, Value: []byte("a")}},
+			},
+		}
+
+		mu := sync.Mutex{}
+		attempts := 0
+		committedBeforeSuccess := false
+
+		k := newTestKafka(t, consumer, func(events []*xatu.DecoratedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts++
+
+			if len(consumer.committedOffsets()) > 0 {
+				committedBeforeSuccess = true
+			}
+
+			if attempts < 3 {
+				return errFailedToPersist
+			}
+
+			return nil
+		})
+
+		assert.NoError(t, k.Start(context.Background()))
+
+		assert.Eventually(t, func() bool {
+			return len(consumer.committedOffsets()) == 1
+		}, time.Second, time.Millisecond)
+
+		assert.NoError(t, k.Stop(context.Background()))
+
+		mu.Lock()
+		assert.Equal(t, 3, attempts)
+		assert.False(t, committedBeforeSuccess)
+		mu.Unlock()
+	})
+
+	t.Run("doesn't commit if stopped while retrying", func(t *testing.T) {
+		consumer := &fakeKafkaConsumer{
+			batches: [][]*kgo.Record{
+				{{Offset: 1, Value: []byte("a")}},
+			},
+		}
+
+		k := newTestKafka(t, consumer, func(events []*xatu.DecoratedEvent) error {
+			return errFailedToPersist
+		})
+
+		assert.NoError(t, k.Start(context.Background()))
+
+		time.Sleep(20 * time.Millisecond)
+
+		assert.NoError(t, k.Stop(context.Background()))
+
+		assert.Empty(t, consumer.committedOffsets())
+	})
+
+	t.Run("drops events that aren't worth retrying", func(t *testing.T) {
+		consumer := &fakeKafkaConsumer{
+			batches: [][]*kgo.Record{
+				{{Offset: 1, Value: []byte("a")}, {Offset: 2, Value: []byte("b")}},
+			},
+		}
+
+		mu := sync.Mutex{}
+		attempts := 0
+
+		k := newTestKafka(t, consumer, func(events []*xatu.DecoratedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			attempts++
+
+			if attempts == 1 {
+				return errInvalidEvent
+			}
+
+			return nil
+		})
+
+		assert.NoError(t, k.Start(context.Background()))
+
+		assert.Eventually(t, func() bool {
+			return len(consumer.committedOffsets()) == 2
+		}, time.Second, time.Millisecond)
+
+		assert.NoError(t, k.Stop(context.Background()))
+
+		mu.Lock()
+		assert.Equal(t, 2, attempts)
+		mu.Unlock()
+	})
+
+	t.Run("allows rebalances after every poll", func(t *testing.T) {
+		consumer := &fakeKafkaConsumer{
+			pollErrs: []error{errors.New("broker unavailable")},
+			batches: [][]*kgo.Record{
+				{},
+				{{Offset: 1, Value: []byte("a")}},
+			},
+		}
+
+		k := newTestKafka(t, consumer, func(events []*xatu.DecoratedEvent) error {
+			return errFailedToPersist
+		})
+
+		assert.NoError(t, k.Start(context.Background()))
+
+		// The failed poll, the empty poll and the batch that's stuck retrying.
+		assert.Eventually(t, func() bool {
+			consumer.mu.Lock()
+			defer consumer.mu.Unlock()
+
+			return consumer.polls == 3
+		}, time.Second, time.Millisecond)
+
+		assert.NoError(t, k.Stop(context.Background()))
+
+		assert.False(t, consumer.rebalancesBlocked())
+		assert.Empty(t, consumer.committedOffsets())
+	})
+
+	t.Run("skips records that can't be decoded", func(t *testing.T) {
+		consumer := &fakeKafkaConsumer{
+			batches: [][]*kgo.Record{
+				{{Offset: 1, Value: []byte("invalid")}, {Offset: 2, Value: []byte("a")}},
+			},
+		}
+
+		mu := sync.Mutex{}
+		handled := 0
+
+		k := newTestKafka(t, consumer, func(events []*xatu.DecoratedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			handled += len(events)
+
+			return nil
+		})
+
+		assert.NoError(t, k.Start(context.Background()))
+
+		assert.Eventually(t, func() bool {
+			return len(consumer.committedOffsets()) == 2
+		}, time.Second, time.Millisecond)
+
+		assert.NoError(t, k.Stop(context.Background()))
+
+		mu.Lock()
+		assert.Equal(t, 1, handled)
+		mu.Unlock()
+	})
+
+	t.Run("validates config", func(t *testing.T) {
+		assert.Error(t, (&KafkaConfig{}).Validate())
+		assert.Error(t, (&KafkaConfig{Brokers: []string{"a"}, Topics: []string{"b"}}).Validate())
+		assert.Error(t, (&KafkaConfig{Brokers: []string{"a"}, Topics: []string{"b"}, ConsumerGroup: "c", Encoding: "avro"}).Validate())
+		assert.Error(t, (&KafkaConfig{
+			Brokers: []string{"a"}, Topics: []string{"b"}, ConsumerGroup: "c",
+			SASL: &KafkaSASLConfig{Mechanism: KafkaSASLMechanismPlain},
+		}).Validate())
+		assert.NoError(t, (&KafkaConfig{Brokers: []string{"a"}, Topics: []string{"b"}, ConsumerGroup: "c", Encoding: KafkaEncodingJSON}).Validate())
+	})
+}
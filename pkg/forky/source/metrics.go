@@ -1,34 +1,91 @@
 package source
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 type BasicMetrics struct {
 	namespace string
 
-	itemsFetched *prometheus.CounterVec
-	sourceType   string
-	sourceName   string
+	itemsFetched     *prometheus.CounterVec
+	eventsReceived   *prometheus.CounterVec
+	requestsRejected *prometheus.CounterVec
+	sourceType       string
+	sourceName       string
 }
 
+const (
+	EventStatusAccepted     = "accepted"
+	EventStatusFiltered     = "filtered"
+	EventStatusWrongNetwork = "wrong_network"
+	EventStatusInvalid      = "invalid"
+	EventStatusFailed       = "failed"
+)
+
+const (
+	RequestRejectedUnauthorized = "unauthorized"
+	RequestRejectedTooLarge     = "too_large"
+	RequestRejectedBadRequest   = "bad_request"
+	RequestRejectedQueueFull    = "queue_full"
+	RequestRejectedShuttingDown = "shutting_down"
+)
+
 func NewBasicMetrics(namespace, sourceType, sourceName string, enabled bool) *BasicMetrics {
 	m := &BasicMetrics{
 		namespace:  namespace,
 		sourceType: sourceType,
+		sourceName: sourceName,
 		itemsFetched: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace,
 			Name:      "items_fetched_count",
 			Help:      "The amount of items fetched by the source",
 		}, []string{"source_type", "source_name", "type"}),
+		eventsReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "events_received_count",
+			Help:      "The amount of events received by the source, by the sender that sent them",
+		}, []string{"source_type", "source_name", "sender", "status"}),
+		requestsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_rejected_count",
+			Help:      "The amount of requests rejected by the source before their events were read",
+		}, []string{"source_type", "source_name", "reason"}),
 	}
 
 	if enabled {
-		//nolint:errcheck // We can only register metrics once.
-		prometheus.Register(m.itemsFetched)
+		// Every source shares the same collectors, so reuse them if another
+		// source has already registered them.
+		m.itemsFetched = registerOrExisting(m.itemsFetched)
+		m.eventsReceived = registerOrExisting(m.eventsReceived)
+		m.requestsRejected = registerOrExisting(m.requestsRejected)
 	}
 
 	return m
 }
 
+func registerOrExisting(c *prometheus.CounterVec) *prometheus.CounterVec {
+	if err := prometheus.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+				return existing
+			}
+		}
+	}
+
+	return c
+}
+
 func (m *BasicMetrics) ObserveItemFetched(itemType string) {
 	m.itemsFetched.WithLabelValues(m.sourceType, m.sourceName, itemType).Inc()
 }
+
+func (m *BasicMetrics) ObserveEventReceived(sender, status string) {
+	m.eventsReceived.WithLabelValues(m.sourceType, m.sourceName, sender, status).Inc()
+}
+
+func (m *BasicMetrics) ObserveRequestRejected(reason string) {
+	m.requestsRejected.WithLabelValues(m.sourceType, m.sourceName, reason).Inc()
+}
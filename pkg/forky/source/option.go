@@ -1,8 +1,14 @@
 package source
 
+import "github.com/ethpandaops/forky/pkg/forky/ethereum"
+
 type Options struct {
 	MetricsEnabled          bool
 	AllowedEthereumNetworks []string
+	// NetworkVerifier rejects beacon nodes that are on a different network.
+	NetworkVerifier func(network *ethereum.NetworkParameters) error
+	// Spec returns the network's spec. It may be zero until the spec is known.
+	Spec func() *ethereum.SpecConfig
 }
 
 func DefaultOptions() *Options {
@@ -39,3 +45,15 @@ func (o *Options) WithAllowedEthereumNetworks(networks []string) *Options {
 
 	return o
 }
+
+func (o *Options) WithNetworkVerifier(verifier func(network *ethereum.NetworkParameters) error) *Options {
+	o.NetworkVerifier = verifier
+
+	return o
+}
+
+func (o *Options) WithSpec(spec func() *ethereum.SpecConfig) *Options {
+	o.Spec = spec
+
+	return o
+}
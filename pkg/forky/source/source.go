@@ -19,12 +19,22 @@ type Source interface {
 	// Stop stops the source.
 	Stop(ctx context.Context) error
 
-	// OnFrame is called when a new frame has been received.
-	OnFrame(func(ctx context.Context, frame *types.Frame))
+	// OnFrame is called when a new frame has been received. Sources that can
+	// acknowledge receipt only do so once every callback has returned nil.
+	OnFrame(func(ctx context.Context, frame *types.Frame) error)
+	// OnReorg is called when a reorg has been observed. Any frames the reorg
+	// references are published via OnFrame before the reorg itself.
+	OnReorg(func(ctx context.Context, reorg *types.Reorg) error)
+
+	// Status returns a snapshot of the source's health.
+	Status() Status
 }
 
 var _ = Source(&BeaconNode{})
 var _ = Source(&XatuHTTP{})
+var _ = Source(&FileReplay{})
+var _ = Source(&XatuGRPC{})
+var _ = Source(&Kafka{})
 
 func NewSource(namespace string, log logrus.FieldLogger, name, sourceType string, config yaml.RawMessage, opts *Options) (Source, error) {
 	namespace += "_source"
@@ -39,7 +49,7 @@ func NewSource(namespace string, log logrus.FieldLogger, name, sourceType string
 			return nil, err
 		}
 
-		source, err := NewBeaconNode(namespace, log, &conf, name, metrics)
+		source, err := NewBeaconNode(namespace, log, &conf, name, metrics, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -58,6 +68,48 @@ func NewSource(namespace string, log logrus.FieldLogger, name, sourceType string
 			return nil, err
 		}
 
+		return source, nil
+
+	case XatuGRPCType:
+		conf := XatuGRPCConfig{}
+
+		if err := config.Unmarshal(&conf); err != nil {
+			return nil, err
+		}
+
+		source, err := NewXatuGRPC(namespace, name, log, &conf, metrics, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return source, nil
+
+	case KafkaType:
+		conf := KafkaConfig{}
+
+		if err := config.Unmarshal(&conf); err != nil {
+			return nil, err
+		}
+
+		source, err := NewKafka(namespace, name, log, &conf, metrics, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		return source, nil
+
+	case FileReplayType:
+		conf := FileReplayConfig{}
+
+		if err := config.Unmarshal(&conf); err != nil {
+			return nil, err
+		}
+
+		source, err := NewFileReplay(namespace, name, log, &conf, metrics, opts)
+		if err != nil {
+			return nil, err
+		}
+
 		return source, nil
 	default:
 		return nil, fmt.Errorf("unknown source type: %s", sourceType)
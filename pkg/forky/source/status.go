@@ -0,0 +1,168 @@
+package source
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the overall state of a source.
+type State string
+
+const (
+	// StateStarting means the source hasn't successfully produced anything yet.
+	StateStarting State = "starting"
+	// StateHealthy means the source's last attempt succeeded.
+	StateHealthy State = "healthy"
+	// StateFailing means the source's last attempt failed.
+	StateFailing State = "failing"
+	// StateStalled means the source hasn't succeeded for longer than expected.
+	StateStalled State = "stalled"
+	// StateStopped means the source has been stopped or has nothing left to do.
+	StateStopped State = "stopped"
+)
+
+// States are all possible states of a source.
+var States = []State{StateStarting, StateHealthy, StateFailing, StateStalled, StateStopped}
+
+// Status is a point-in-time snapshot of a source's health.
+type Status struct {
+	Name                   string           `json:"name"`
+	Type                   string           `json:"type"`
+	State                  State            `json:"state"`
+	LastSuccessAt          *time.Time       `json:"last_success_at,omitempty"`
+	LastError              string           `json:"last_error,omitempty"`
+	LastErrorAt            *time.Time       `json:"last_error_at,omitempty"`
+	ConsecutiveFailures    int              `json:"consecutive_failures"`
+	FramesEmitted          uint64           `json:"frames_emitted"`
+	ConsensusClient        string           `json:"consensus_client,omitempty"`
+	ConsensusClientVersion string           `json:"consensus_client_version,omitempty"`
+	Network                string           `json:"network,omitempty"`
+	Endpoints              []EndpointStatus `json:"endpoints,omitempty"`
+}
+
+// EndpointStatus is the health of a single endpoint of a source.
+type EndpointStatus struct {
+	Name                string     `json:"name"`
+	Healthy             bool       `json:"healthy"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastCheckedAt       *time.Time `json:"last_checked_at,omitempty"`
+}
+
+// statusTracker records the health of a source so it can be reported as a Status.
+type statusTracker struct {
+	mu sync.Mutex
+
+	// stallAfter is how long a source can go without succeeding before it's
+	// considered stalled. Stall detection is disabled if zero.
+	stallAfter time.Duration
+
+	stopped             bool
+	lastSuccessAt       time.Time
+	lastError           string
+	lastErrorAt         time.Time
+	consecutiveFailures int
+	framesEmitted       uint64
+
+	consensusClient        string
+	consensusClientVersion string
+	network                string
+}
+
+func newStatusTracker(stallAfter time.Duration) *statusTracker {
+	return &statusTracker{
+		stallAfter: stallAfter,
+	}
+}
+
+func (s *statusTracker) RecordSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSuccessAt = time.Now()
+	s.consecutiveFailures = 0
+}
+
+func (s *statusTracker) RecordFailure(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastError = redactError(err)
+	s.lastErrorAt = time.Now()
+	s.consecutiveFailures++
+}
+
+func (s *statusTracker) RecordFrame() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.framesEmitted++
+}
+
+func (s *statusTracker) RecordStopped() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+}
+
+func (s *statusTracker) SetConsensusClient(client, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.consensusClient = client
+	s.consensusClientVersion = version
+}
+
+func (s *statusTracker) SetNetwork(network string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.network = network
+}
+
+// Snapshot returns the current status of the source.
+func (s *statusTracker) Snapshot(name, sourceType string) Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{
+		Name:                   name,
+		Type:                   sourceType,
+		State:                  s.state(),
+		LastError:              s.lastError,
+		ConsecutiveFailures:    s.consecutiveFailures,
+		FramesEmitted:          s.framesEmitted,
+		ConsensusClient:        s.consensusClient,
+		ConsensusClientVersion: s.consensusClientVersion,
+		Network:                s.network,
+	}
+
+	if !s.lastSuccessAt.IsZero() {
+		lastSuccessAt := s.lastSuccessAt
+		status.LastSuccessAt = &lastSuccessAt
+	}
+
+	if !s.lastErrorAt.IsZero() {
+		lastErrorAt := s.lastErrorAt
+		status.LastErrorAt = &lastErrorAt
+	}
+
+	return status
+}
+
+func (s *statusTracker) state() State {
+	switch {
+	case s.stopped:
+		return StateStopped
+	case s.consecutiveFailures > 0:
+		return StateFailing
+	case s.lastSuccessAt.IsZero():
+		return StateStarting
+	case s.stallAfter > 0 && time.Since(s.lastSuccessAt) > s.stallAfter:
+		return StateStalled
+	default:
+		return StateHealthy
+	}
+}
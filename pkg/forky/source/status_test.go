@@ -0,0 +1,77 @@
+package source
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusTracker(t *testing.T) {
+	t.Run("state transitions", func(t *testing.T) {
+		tracker := newStatusTracker(0)
+
+		status := tracker.Snapshot("node", BeaconNodeType)
+		assert.Equal(t, StateStarting, status.State)
+		assert.Nil(t, status.LastSuccessAt)
+		assert.Equal(t, "node", status.Name)
+		assert.Equal(t, BeaconNodeType, status.Type)
+
+		tracker.RecordFailure(errors.New("connection refused"))
+		tracker.RecordFailure(errors.New("connection refused"))
+
+		status = tracker.Snapshot("node", BeaconNodeType)
+		assert.Equal(t, StateFailing, status.State)
+		assert.Equal(t, 2, status.ConsecutiveFailures)
+		assert.Equal(t, "connection refused", status.LastError)
+		assert.NotNil(t, status.LastErrorAt)
+
+		tracker.RecordSuccess()
+		tracker.RecordFrame()
+
+		status = tracker.Snapshot("node", BeaconNodeType)
+		assert.Equal(t, StateHealthy, status.State)
+		assert.Equal(t, 0, status.ConsecutiveFailures)
+		assert.Equal(t, uint64(1), status.FramesEmitted)
+		assert.NotNil(t, status.LastSuccessAt)
+		// The last error is kept for context.
+		assert.Equal(t, "connection refused", status.LastError)
+
+		tracker.RecordStopped()
+
+		assert.Equal(t, StateStopped, tracker.Snapshot("node", BeaconNodeType).State)
+	})
+
+	t.Run("redacts addresses in errors", func(t *testing.T) {
+		tracker := newStatusTracker(0)
+
+		tracker.RecordFailure(errors.New(`Get "https://beacon.example.com/eth/v1/debug/fork_choice?apikey=abcdef": EOF`))
+
+		assert.Equal(t, `Get "https://beacon.example.com/xxxxx": EOF`, tracker.Snapshot("node", BeaconNodeType).LastError)
+	})
+
+	t.Run("stalled", func(t *testing.T) {
+		tracker := newStatusTracker(time.Minute)
+
+		tracker.RecordSuccess()
+
+		assert.Equal(t, StateHealthy, tracker.Snapshot("node", BeaconNodeType).State)
+
+		tracker.lastSuccessAt = time.Now().Add(-2 * time.Minute)
+
+		assert.Equal(t, StateStalled, tracker.Snapshot("node", BeaconNodeType).State)
+	})
+
+	t.Run("client and network", func(t *testing.T) {
+		tracker := newStatusTracker(0)
+
+		tracker.SetConsensusClient("lighthouse", "Lighthouse/v5.0.0")
+		tracker.SetNetwork("mainnet")
+
+		status := tracker.Snapshot("node", BeaconNodeType)
+		assert.Equal(t, "lighthouse", status.ConsensusClient)
+		assert.Equal(t, "Lighthouse/v5.0.0", status.ConsensusClientVersion)
+		assert.Equal(t, "mainnet", status.Network)
+	})
+}
@@ -0,0 +1,503 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	eth2v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethpandaops/forky/pkg/forky/ethereum"
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/ethpandaops/xatu/pkg/proto/xatu"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// errFailedToPersist is returned when a frame or reorg was converted from a xatu
+// event but couldn't be persisted. It's the only error worth retrying.
+var errFailedToPersist = errors.New("failed to persist")
+
+// errInvalidEvent is returned when a xatu event converts into a frame or reorg
+// that fails validation. Retrying the event will never succeed.
+var errInvalidEvent = errors.New("invalid event")
+
+// frameNamespace is the namespace of frame IDs derived from xatu event IDs.
+var frameNamespace = uuid.MustParse("6f1c2d9e-8b4a-4c3e-9a7f-1d2e3c4b5a60")
+
+// xatuEventHandler converts xatu events into frames and reorgs. It is shared by
+// the xatu sources.
+type xatuEventHandler struct {
+	log logrus.FieldLogger
+
+	opts *Options
+
+	metrics *BasicMetrics
+
+	status *statusTracker
+
+	filter xatu.EventFilter
+
+	onFrameCallbacks []func(ctx context.Context, frame *types.Frame) error
+	onReorgCallbacks []func(ctx context.Context, reorg *types.Reorg) error
+}
+
+func newXatuEventHandler(log logrus.FieldLogger, opts *Options, metrics *BasicMetrics) (*xatuEventHandler, error) {
+	filter, err := xatu.NewEventFilter(&xatu.EventFilterConfig{
+		EventNames: []string{
+			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE.String(),
+			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_V2.String(),
+			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG.String(),
+			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG_V2.String(),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event filter: %w", err)
+	}
+
+	return &xatuEventHandler{
+		log:              log,
+		opts:             opts,
+		metrics:          metrics,
+		status:           newStatusTracker(0),
+		filter:           filter,
+		onFrameCallbacks: []func(ctx context.Context, frame *types.Frame) error{},
+		onReorgCallbacks: []func(ctx context.Context, reorg *types.Reorg) error{},
+	}, nil
+}
+
+func (x *xatuEventHandler) OnFrame(fn func(ctx context.Context, frame *types.Frame) error) {
+	x.onFrameCallbacks = append(x.onFrameCallbacks, fn)
+}
+
+func (x *xatuEventHandler) OnReorg(fn func(ctx context.Context, reorg *types.Reorg) error) {
+	x.onReorgCallbacks = append(x.onReorgCallbacks, fn)
+}
+
+func (x *xatuEventHandler) publishFrame(ctx context.Context, frame *types.Frame) error {
+	if err := frame.Validate(); err != nil {
+		return fmt.Errorf("%w: frame %s: %w", errInvalidEvent, frame.Metadata.ID, err)
+	}
+
+	for _, fn := range x.onFrameCallbacks {
+		if err := fn(ctx, frame); err != nil {
+			err = fmt.Errorf("%w frame %s: %w", errFailedToPersist, frame.Metadata.ID, err)
+
+			x.status.RecordFailure(err)
+
+			return err
+		}
+	}
+
+	x.status.RecordSuccess()
+	x.status.RecordFrame()
+
+	return nil
+}
+
+func (x *xatuEventHandler) publishReorg(ctx context.Context, reorg *types.Reorg) error {
+	if err := reorg.Validate(); err != nil {
+		return fmt.Errorf("%w: reorg %s: %w", errInvalidEvent, reorg.ID, err)
+	}
+
+	for _, fn := range x.onReorgCallbacks {
+		if err := fn(ctx, reorg); err != nil {
+			err = fmt.Errorf("%w reorg %s: %w", errFailedToPersist, reorg.ID, err)
+
+			x.status.RecordFailure(err)
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleXatuEvents handles a batch of events. Events that can't be converted are
+// logged and dropped, but an error is returned if any frame or reorg failed to be
+// persisted so that the sender can retry.
+func (x *xatuEventHandler) handleXatuEvents(ctx context.Context, events []*xatu.DecoratedEvent) error {
+	var persistErrs []error
+
+	for _, event := range events {
+		if err := x.handleXatuEvent(ctx, event); err != nil {
+			if errors.Is(err, errFailedToPersist) {
+				persistErrs = append(persistErrs, err)
+
+				continue
+			}
+
+			logCtx := x.log.
+				WithField("event_id", event.GetMeta().GetClient().GetId()).
+				WithError(err)
+
+			eventJSON, err := protojson.Marshal(event)
+			if err != nil {
+				logCtx = logCtx.WithField("marshal_error", err)
+			} else {
+				logCtx = logCtx.WithField("event_json", string(eventJSON))
+			}
+
+			logCtx.Error("Failed to handle event")
+		}
+	}
+
+	return errors.Join(persistErrs...)
+}
+
+func (x *xatuEventHandler) handleXatuEvent(ctx context.Context, event *xatu.DecoratedEvent) error {
+	logCtx := x.log.WithField("event_id", event.GetMeta().GetClient().GetId())
+
+	sender := event.GetMeta().GetClient().GetName()
+
+	shouldBeFiltered, err := x.filter.ShouldBeDropped(event)
+	if err != nil {
+		logCtx.WithError(err).Error("Failed to check if event should be dropped")
+
+		return err
+	}
+
+	if shouldBeFiltered {
+		x.metrics.ObserveEventReceived(sender, EventStatusFiltered)
+
+		logCtx.WithField("event_name", event.GetEvent().GetName()).Warn("Dropping xatu event as it was filtered out")
+
+		return nil
+	}
+
+	// Drop it if its from a network that we don't care about
+	name := event.GetMeta().GetClient().GetEthereum().GetNetwork().GetName()
+
+	found := false
+
+	for _, network := range x.opts.AllowedEthereumNetworks {
+		if network == name {
+			found = true
+
+			break
+		}
+	}
+
+	if !found {
+		x.metrics.ObserveEventReceived(sender, EventStatusWrongNetwork)
+
+		logCtx.
+			WithField("network", name).
+			WithField("allowed_networks", x.opts.AllowedEthereumNetworks).
+			Warn("Dropping xatu event as it is from a network we don't care about")
+
+		return nil
+	}
+
+	switch event.GetEvent().GetName() {
+	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE:
+		err = x.handleForkChoiceEvent(ctx, event)
+	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_V2:
+		err = x.handleForkChoiceV2Event(ctx, event)
+	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG:
+		err = x.handleForkChoiceReorgEvent(ctx, event)
+	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG_V2:
+		err = x.handleForkChoiceReorgV2Event(ctx, event)
+	default:
+		err = errors.New("unknown event type") // Should never happen (touch wood (tm (c)))
+	}
+
+	switch {
+	case err == nil:
+		x.metrics.ObserveEventReceived(sender, EventStatusAccepted)
+
+		consensus := event.GetMeta().GetClient().GetEthereum().GetConsensus()
+
+		x.status.SetConsensusClient(consensus.GetImplementation(), consensus.GetVersion())
+		x.status.SetNetwork(name)
+	case errors.Is(err, errFailedToPersist):
+		x.metrics.ObserveEventReceived(sender, EventStatusFailed)
+	default:
+		x.metrics.ObserveEventReceived(sender, EventStatusInvalid)
+	}
+
+	return err
+}
+
+func (x *xatuEventHandler) handleForkChoiceEvent(ctx context.Context, event *xatu.DecoratedEvent) error {
+	// Create a new frame based on the event
+	fc := event.GetEthV1ForkChoiceV2()
+	if fc == nil {
+		return fmt.Errorf("event is not a fork choice event")
+	}
+
+	data, err := fc.AsGoEth2ClientV1ForkChoice()
+	if err != nil {
+		return fmt.Errorf("failed to convert event to fork choice: %w", err)
+	}
+
+	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoice()
+	if additionalData == nil {
+		return fmt.Errorf("event is missing additional data")
+	}
+
+	frame := x.createFrameFromSnapshotAndData(event, "", data, additionalData.GetSnapshot())
+
+	return x.publishFrame(ctx, frame)
+}
+
+func (x *xatuEventHandler) handleForkChoiceV2Event(ctx context.Context, event *xatu.DecoratedEvent) error {
+	// Create a new frame based on the event
+	fc := event.GetEthV1ForkChoiceV2()
+	if fc == nil {
+		return fmt.Errorf("event is not a fork choice event")
+	}
+
+	data, err := fc.AsGoEth2ClientV1ForkChoice()
+	if err != nil {
+		return fmt.Errorf("failed to convert event to fork choice: %w", err)
+	}
+
+	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoiceV2()
+	if additionalData == nil {
+		return fmt.Errorf("event is missing additional data")
+	}
+
+	frame := x.createFrameFromSnapshotV2AndData(event, "", data, additionalData.GetSnapshot())
+
+	return x.publishFrame(ctx, frame)
+}
+
+func (x *xatuEventHandler) handleForkChoiceReorgEvent(ctx context.Context, event *xatu.DecoratedEvent) error {
+	x.log.
+		WithField("event_id", event.GetMeta().GetClient().GetId()).
+		WithField("client_name", event.GetMeta().GetClient().GetName()).
+		Info("Handling fork choice reorg event")
+
+	// Create 2 new frames based on the event (one for `before` the reorg and one for `after` the reorg)
+	// Note: `before` can be nil if the reorg happened before the xatu sentry started
+	fcr := event.GetEthV1ForkChoiceReorgV2()
+	if fcr == nil {
+		return fmt.Errorf("event is not a fork choice reorg event")
+	}
+
+	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoiceReorg()
+	if additionalData == nil {
+		return fmt.Errorf("event is missing additional data")
+	}
+
+	if fcr.After == nil && fcr.Before == nil {
+		return fmt.Errorf("event is missing both before and after data")
+	}
+
+	reorg := newReorgFromEvent(event)
+
+	if fcr.After != nil && additionalData.After != nil {
+		data, err := fcr.After.AsGoEth2ClientV1ForkChoice()
+		if err != nil {
+			x.log.WithError(err).Error("failed to convert fork_choice_reorg.after to fork choice")
+		} else {
+			frame := x.createFrameFromSnapshotAndData(event, "after", data, additionalData.After)
+			frame.Metadata.EventSource = types.XatuReorgEventEventSource.String()
+
+			if err := x.publishFrame(ctx, frame); err != nil {
+				return err
+			}
+
+			reorg.AfterFrameID = frame.Metadata.ID
+		}
+	}
+
+	if fcr.Before != nil && additionalData.Before != nil {
+		data, err := fcr.Before.AsGoEth2ClientV1ForkChoice()
+		if err != nil {
+			x.log.WithError(err).Error("failed to convert fork_choice_reorg.before to fork choice")
+		} else {
+			frame := x.createFrameFromSnapshotAndData(event, "before", data, additionalData.Before)
+			frame.Metadata.EventSource = types.XatuReorgEventEventSource.String()
+
+			if err := x.publishFrame(ctx, frame); err != nil {
+				return err
+			}
+
+			reorg.BeforeFrameID = frame.Metadata.ID
+		}
+	}
+
+	if reorg.BeforeFrameID == "" && reorg.AfterFrameID == "" {
+		return fmt.Errorf("failed to create frames for fork choice reorg")
+	}
+
+	return x.publishReorg(ctx, reorg)
+}
+
+func (x *xatuEventHandler) handleForkChoiceReorgV2Event(ctx context.Context, event *xatu.DecoratedEvent) error {
+	x.log.
+		WithField("event_id", event.GetMeta().GetClient().GetId()).
+		WithField("client_name", event.GetMeta().GetClient().GetName()).
+		Info("Handling fork choice reorg event")
+
+	// Create 2 new frames based on the event (one for `before` the reorg and one for `after` the reorg)
+	// Note: `before` can be nil if the reorg happened before the xatu sentry started
+	fcr := event.GetEthV1ForkChoiceReorgV2()
+	if fcr == nil {
+		return fmt.Errorf("event is not a fork choice reorg event")
+	}
+
+	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoiceReorgV2()
+	if additionalData == nil {
+		return fmt.Errorf("event is missing additional data")
+	}
+
+	if fcr.After == nil && fcr.Before == nil {
+		return fmt.Errorf("event is missing both before and after data")
+	}
+
+	reorg := newReorgFromEvent(event)
+
+	if fcr.After != nil && additionalData.After != nil {
+		data, err := fcr.After.AsGoEth2ClientV1ForkChoice()
+		if err != nil {
+			x.log.WithError(err).Error("failed to convert fork_choice_reorg.after to fork choice")
+		} else {
+			frame := x.createFrameFromSnapshotV2AndData(event, "after", data, additionalData.After)
+			frame.Metadata.EventSource = types.XatuReorgEventEventSource.String()
+
+			if err := x.publishFrame(ctx, frame); err != nil {
+				return err
+			}
+
+			reorg.AfterFrameID = frame.Metadata.ID
+		}
+	}
+
+	if fcr.Before != nil && additionalData.Before != nil {
+		data, err := fcr.Before.AsGoEth2ClientV1ForkChoice()
+		if err != nil {
+			x.log.WithError(err).Error("failed to convert fork_choice_reorg.before to fork choice")
+		} else {
+			frame := x.createFrameFromSnapshotV2AndData(event, "before", data, additionalData.Before)
+			frame.Metadata.EventSource = types.XatuReorgEventEventSource.String()
+
+			if err := x.publishFrame(ctx, frame); err != nil {
+				return err
+			}
+
+			reorg.BeforeFrameID = frame.Metadata.ID
+		}
+	}
+
+	if reorg.BeforeFrameID == "" && reorg.AfterFrameID == "" {
+		return fmt.Errorf("failed to create frames for fork choice reorg")
+	}
+
+	return x.publishReorg(ctx, reorg)
+}
+
+func (x *xatuEventHandler) createFrameFromSnapshotAndData(
+	event *xatu.DecoratedEvent,
+	snapshotName string,
+	data *eth2v1.ForkChoice,
+	snapshot *xatu.ClientMeta_ForkChoiceSnapshot,
+) *types.Frame {
+	return &types.Frame{
+		Metadata: types.FrameMetadata{
+			ID:   frameIDFromEvent(event, snapshotName),
+			Node: event.Meta.Client.Name,
+
+			WallClockSlot:  phase0.Slot(snapshot.GetRequestSlot().Number),
+			WallClockEpoch: phase0.Epoch(snapshot.GetRequestEpoch().Number),
+
+			FetchedAt: snapshot.GetTimestamp().AsTime(),
+
+			ConsensusClient:        event.GetMeta().GetClient().GetEthereum().GetConsensus().GetImplementation(),
+			ConsensusClientVersion: consensusClientVersion(event),
+
+			EventSource: types.XatuPollingEventSource.String(),
+
+			Labels: []string{},
+		},
+		Data: data,
+	}
+}
+
+func (x *xatuEventHandler) createFrameFromSnapshotV2AndData(
+	event *xatu.DecoratedEvent,
+	snapshotName string,
+	data *eth2v1.ForkChoice,
+	snapshot *xatu.ClientMeta_ForkChoiceSnapshotV2,
+) *types.Frame {
+	return &types.Frame{
+		Metadata: types.FrameMetadata{
+			ID:   frameIDFromEvent(event, snapshotName),
+			Node: event.Meta.Client.Name,
+
+			WallClockSlot:  phase0.Slot(snapshot.GetRequestSlot().GetNumber().GetValue()),
+			WallClockEpoch: phase0.Epoch(snapshot.GetRequestEpoch().GetNumber().GetValue()),
+
+			FetchedAt: snapshot.GetTimestamp().AsTime(),
+
+			ConsensusClient:        event.GetMeta().GetClient().GetEthereum().GetConsensus().GetImplementation(),
+			ConsensusClientVersion: consensusClientVersion(event),
+
+			EventSource: types.XatuPollingEventSource.String(),
+
+			Labels: []string{},
+		},
+		Data: data,
+	}
+}
+
+// frameIDFromEvent derives a frame's ID from the ID of the event it came from,
+// so that a redelivered event produces the same frames rather than duplicates.
+// Events can hold more than one snapshot, e.g. either side of a reorg, so the
+// snapshot's name is included.
+func frameIDFromEvent(event *xatu.DecoratedEvent, snapshotName string) string {
+	id := event.GetEvent().GetId()
+	if id == "" {
+		return uuid.New().String()
+	}
+
+	return uuid.NewSHA1(frameNamespace, []byte(id+"/"+snapshotName)).String()
+}
+
+// consensusClientVersion returns the release of the consensus client that
+// produced the event. Xatu reports either the bare version or the full node
+// version string.
+func consensusClientVersion(event *xatu.DecoratedEvent) string {
+	consensus := event.GetMeta().GetClient().GetEthereum().GetConsensus()
+
+	version := consensus.GetVersion()
+	if version == "" {
+		return ""
+	}
+
+	if !strings.Contains(version, "/") {
+		version = consensus.GetImplementation() + "/" + version
+	}
+
+	return ethereum.ParseClientVersion(version).Version
+}
+
+func newReorgFromEvent(event *xatu.DecoratedEvent) *types.Reorg {
+	data := event.GetEthV1ForkChoiceReorgV2().GetEvent()
+	slot := phase0.Slot(data.GetSlot().GetValue())
+
+	detectedAt := time.Now()
+	if dt := event.GetEvent().GetDateTime(); dt != nil {
+		detectedAt = dt.AsTime()
+	}
+
+	return &types.Reorg{
+		ID:              types.NewReorgID(event.GetMeta().GetClient().GetName(), slot, data.GetOldHeadBlock(), data.GetNewHeadBlock()),
+		Network:         event.GetMeta().GetClient().GetEthereum().GetNetwork().GetName(),
+		Node:            event.GetMeta().GetClient().GetName(),
+		ConsensusClient: event.GetMeta().GetClient().GetEthereum().GetConsensus().GetImplementation(),
+		DetectedAt:      detectedAt,
+		Slot:            slot,
+		Epoch:           phase0.Epoch(data.GetEpoch().GetValue()),
+		Depth:           data.GetDepth().GetValue(),
+		OldHeadBlock:    data.GetOldHeadBlock(),
+		OldHeadState:    data.GetOldHeadState(),
+		NewHeadBlock:    data.GetNewHeadBlock(),
+		NewHeadState:    data.GetNewHeadState(),
+	}
+}
@@ -0,0 +1,207 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ethpandaops/xatu/pkg/proto/xatu"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	// Registers the gzip compressor so clients can send compressed requests.
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+var XatuGRPCType = "xatu_grpc"
+
+type XatuGRPCConfig struct {
+	// Address is the address to listen on, e.g. ":8080".
+	Address string `yaml:"address"`
+	// Auth is the authentication clients must provide.
+	Auth AuthConfig `yaml:"auth"`
+	// MaxMessageSize is the maximum size in bytes of a single request. Defaults to 64MiB.
+	MaxMessageSize int `yaml:"max_message_size"`
+}
+
+func (c *XatuGRPCConfig) Validate() error {
+	if c.Address == "" {
+		return errors.New("address is required")
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	if c.MaxMessageSize < 0 {
+		return errors.New("max_message_size must be positive")
+	}
+
+	return nil
+}
+
+// XatuGRPC is a source that implements Xatu's EventIngester gRPC service, so
+// sentries and servers can ship fork choice events to forky directly.
+type XatuGRPC struct {
+	xatu.UnimplementedEventIngesterServer
+	*xatuEventHandler
+
+	log logrus.FieldLogger
+
+	config *XatuGRPCConfig
+
+	name string
+
+	metrics *BasicMetrics
+
+	server *grpc.Server
+
+	handleEvents func(ctx context.Context, events []*xatu.DecoratedEvent) error
+
+	// lis is closed on stop as well, since the server only takes ownership of
+	// it once it starts serving.
+	lis net.Listener
+}
+
+func NewXatuGRPC(namespace, name string, log logrus.FieldLogger, config *XatuGRPCConfig, metrics *BasicMetrics, opts *Options) (*XatuGRPC, error) {
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	log = log.
+		WithField("source_name", name).
+		WithField("component", "source/xatu_grpc")
+
+	handler, err := newXatuEventHandler(log, opts, metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	maxMessageSize := config.MaxMessageSize
+	if maxMessageSize == 0 {
+		maxMessageSize = 64 * 1024 * 1024
+	}
+
+	x := &XatuGRPC{
+		xatuEventHandler: handler,
+		log:              log,
+		config:           config,
+		name:             name,
+		metrics:          metrics,
+	}
+
+	x.handleEvents = x.handleXatuEvents
+
+	x.server = grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageSize),
+		grpc.UnaryInterceptor(x.authInterceptor),
+	)
+
+	xatu.RegisterEventIngesterServer(x.server, x)
+
+	return x, nil
+}
+
+func (x *XatuGRPC) Name() string {
+	return x.name
+}
+
+func (x *XatuGRPC) Type() string {
+	return XatuGRPCType
+}
+
+func (x *XatuGRPC) Status() Status {
+	return x.status.Snapshot(x.name, XatuGRPCType)
+}
+
+func (x *XatuGRPC) Start(ctx context.Context) error {
+	lis, err := net.Listen("tcp", x.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", x.config.Address, err)
+	}
+
+	x.lis = lis
+
+	x.log.WithField("address", x.config.Address).Info("Starting xatu_grpc source")
+
+	go func() {
+		if err := x.server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			x.log.WithError(err).Fatal("Error serving xatu_grpc source")
+		}
+	}()
+
+	return nil
+}
+
+func (x *XatuGRPC) Stop(ctx context.Context) error {
+	defer x.status.RecordStopped()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		x.server.GracefulStop()
+
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		x.server.Stop()
+	case <-time.After(30 * time.Second):
+		x.server.Stop()
+	}
+
+	if x.lis != nil {
+		// Already closed if the server was serving.
+		_ = x.lis.Close()
+	}
+
+	return nil
+}
+
+// CreateEvents implements xatu.EventIngesterServer. Events are only acknowledged
+// once every frame and reorg they contain has been persisted, so a failure is
+// returned as Unavailable for the client to retry. Invalid events are dropped
+// rather than failing the request, since retrying them would never succeed.
+func (x *XatuGRPC) CreateEvents(ctx context.Context, req *xatu.CreateEventsRequest) (*xatu.CreateEventsResponse, error) {
+	events := req.GetEvents()
+
+	if err := x.handleEvents(ctx, events); err != nil {
+		x.log.WithError(err).Error("Failed to persist events")
+
+		return nil, status.Error(codes.Unavailable, "failed to persist events")
+	}
+
+	return &xatu.CreateEventsResponse{
+		EventsIngested: wrapperspb.UInt64(uint64(len(events))),
+	}, nil
+}
+
+func (x *XatuGRPC) authInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !x.config.Auth.Enabled() {
+		return handler(ctx, req)
+	}
+
+	header := ""
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+
+	if !x.config.Auth.Authorize(header) {
+		x.metrics.ObserveRequestRejected(RequestRejectedUnauthorized)
+
+		return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+
+	return handler(ctx, req)
+}
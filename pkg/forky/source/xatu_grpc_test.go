@@ -0,0 +1,120 @@
+package source
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/xatu/pkg/proto/xatu"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestXatuGRPC serves the source over an in-memory connection and returns a
+// client for it.
+func newTestXatuGRPC(t *testing.T, handle func(events []*xatu.DecoratedEvent) error) xatu.EventIngesterClient {
+	t.Helper()
+
+	source, err := NewXatuGRPC("forky_test", "xatu", logrus.New(), &XatuGRPCConfig{Address: "bufconn"}, NewBasicMetrics("forky_test", XatuGRPCType, "xatu", false), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source.handleEvents = func(_ context.Context, events []*xatu.DecoratedEvent) error {
+		return handle(events)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	go func() {
+		_ = source.server.Serve(lis)
+	}()
+
+	t.Cleanup(func() {
+		_ = source.Stop(context.Background())
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return xatu.NewEventIngesterClient(conn)
+}
+
+func TestXatuGRPC(t *testing.T) {
+	t.Run("acknowledges events once they're persisted", func(t *testing.T) {
+		release := make(chan struct{})
+		persisted := make(chan []*xatu.DecoratedEvent, 1)
+
+		client := newTestXatuGRPC(t, func(events []*xatu.DecoratedEvent) error {
+			<-release
+
+			persisted <- events
+
+			return nil
+		})
+
+		type result struct {
+			resp *xatu.CreateEventsResponse
+			err  error
+		}
+
+		done := make(chan result, 1)
+
+		go func() {
+			resp, err := client.CreateEvents(context.Background(), &xatu.CreateEventsRequest{
+				Events: []*xatu.DecoratedEvent{{Event: &xatu.Event{Id: "event-1"}}},
+			})
+
+			done <- result{resp: resp, err: err}
+		}()
+
+		select {
+		case <-done:
+			t.Fatal("events were acknowledged before they were persisted")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		close(release)
+
+		select {
+		case res := <-done:
+			assert.NoError(t, res.err)
+			assert.Equal(t, uint64(1), res.resp.GetEventsIngested().GetValue())
+		case <-time.After(5 * time.Second):
+			t.Fatal("events were never acknowledged")
+		}
+
+		events := <-persisted
+		assert.Len(t, events, 1)
+		assert.Equal(t, "event-1", events[0].GetEvent().GetId())
+	})
+
+	t.Run("fails the request when events can't be persisted", func(t *testing.T) {
+		client := newTestXatuGRPC(t, func(_ []*xatu.DecoratedEvent) error {
+			return errFailedToPersist
+		})
+
+		resp, err := client.CreateEvents(context.Background(), &xatu.CreateEventsRequest{
+			Events: []*xatu.DecoratedEvent{{Event: &xatu.Event{Id: "event-1"}}},
+		})
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
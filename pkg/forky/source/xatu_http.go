@@ -1,31 +1,79 @@
 package source
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
-	eth2v1 "github.com/attestantio/go-eth2-client/api/v1"
-	"github.com/attestantio/go-eth2-client/spec/phase0"
-	"github.com/ethpandaops/forky/pkg/forky/types"
 	"github.com/ethpandaops/xatu/pkg/proto/xatu"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
 var XatuHTTPType = "xatu_http"
 
+const (
+	defaultXatuHTTPMaxBodySize = 64 * 1024 * 1024
+	defaultXatuHTTPWorkers     = 4
+	defaultXatuHTTPQueueSize   = 100
+)
+
 type XatuHTTPConfig struct {
 	Address string `yaml:"address"`
 	Path    string `yaml:"path"`
+	// Auth is the authentication clients must provide.
+	Auth AuthConfig `yaml:"auth"`
+	// MaxBodySize is the maximum size in bytes of a request body after it has been
+	// decompressed. Defaults to 64MiB.
+	MaxBodySize int64 `yaml:"max_body_size"`
+	// Workers is the number of requests that are processed concurrently. Defaults to 4.
+	Workers int `yaml:"workers"`
+	// QueueSize is the number of requests that can wait for a worker. Requests are
+	// rejected with a 429 once the queue is full. Defaults to 100.
+	QueueSize int `yaml:"queue_size"`
+}
+
+func (c *XatuHTTPConfig) Validate() error {
+	if c.Address == "" {
+		return errors.New("address is required")
+	}
+
+	if err := c.Auth.Validate(); err != nil {
+		return fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	if c.MaxBodySize < 0 {
+		return errors.New("max_body_size must be positive")
+	}
+
+	if c.Workers < 0 {
+		return errors.New("workers must be positive")
+	}
+
+	if c.QueueSize < 0 {
+		return errors.New("queue_size must be positive")
+	}
+
+	return nil
+}
+
+// xatuHTTPJob is a decoded request waiting to be processed by a worker.
+type xatuHTTPJob struct {
+	ctx    context.Context //nolint:containedctx // The job is bound to the request.
+	events []*xatu.DecoratedEvent
+	result chan error
 }
 
 type XatuHTTP struct {
+	*xatuEventHandler
+
 	log logrus.FieldLogger
 
 	config *XatuHTTPConfig
@@ -36,25 +84,43 @@ type XatuHTTP struct {
 
 	opts *Options
 
-	onFrameCallbacks []func(ctx context.Context, frame *types.Frame)
-
 	server *http.Server
 	mux    *http.ServeMux
 
-	filter xatu.EventFilter
+	// lis is closed on stop as well, since the server only takes ownership of
+	// it once it starts serving.
+	lis net.Listener
+
+	queue    chan *xatuHTTPJob
+	done     chan struct{}
+	stopOnce sync.Once
+	workers  sync.WaitGroup
 }
 
 func NewXatuHTTP(namespace, name string, log logrus.FieldLogger, config *XatuHTTPConfig, metrics *BasicMetrics, opts *Options) (*XatuHTTP, error) {
-	filter, err := xatu.NewEventFilter(&xatu.EventFilterConfig{
-		EventNames: []string{
-			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE.String(),
-			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_V2.String(),
-			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG.String(),
-			xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG_V2.String(),
-		},
-	})
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if config.MaxBodySize == 0 {
+		config.MaxBodySize = defaultXatuHTTPMaxBodySize
+	}
+
+	if config.Workers == 0 {
+		config.Workers = defaultXatuHTTPWorkers
+	}
+
+	if config.QueueSize == 0 {
+		config.QueueSize = defaultXatuHTTPQueueSize
+	}
+
+	log = log.
+		WithField("source_name", name).
+		WithField("component", "source/xatu_http")
+
+	handler, err := newXatuEventHandler(log, opts, metrics)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create event filter: %w", err)
+		return nil, err
 	}
 
 	mux := http.NewServeMux()
@@ -67,17 +133,16 @@ func NewXatuHTTP(namespace, name string, log logrus.FieldLogger, config *XatuHTT
 	}
 
 	return &XatuHTTP{
-		log: log.
-			WithField("source_name", name).
-			WithField("component", "source/xatu_http"),
+		xatuEventHandler: handler,
+		log:              log,
 		name:             name,
 		metrics:          metrics,
 		mux:              mux,
 		server:           server,
 		config:           config,
 		opts:             opts,
-		onFrameCallbacks: []func(ctx context.Context, frame *types.Frame){},
-		filter:           filter,
+		queue:            make(chan *xatuHTTPJob, config.QueueSize),
+		done:             make(chan struct{}),
 	}, nil
 }
 
@@ -89,13 +154,31 @@ func (x *XatuHTTP) Type() string {
 	return XatuHTTPType
 }
 
+func (x *XatuHTTP) Status() Status {
+	return x.status.Snapshot(x.name, XatuHTTPType)
+}
+
 func (x *XatuHTTP) Start(ctx context.Context) error {
-	x.registerHandler(ctx, x.mux)
+	// Listen up front so that address conflicts are returned rather than fatal.
+	lis, err := net.Listen("tcp", x.config.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", x.config.Address, err)
+	}
 
-	x.log.WithField("address", x.config.Address).Info("Starting xatu_http source")
+	x.lis = lis
+
+	x.registerHandler(x.mux)
+	x.startWorkers()
+
+	x.log.
+		WithField("address", x.config.Address).
+		WithField("auth", x.config.Auth.Type).
+		WithField("workers", x.config.Workers).
+		WithField("queue_size", x.config.QueueSize).
+		Info("Starting xatu_http source")
 
 	go func() {
-		err := x.server.ListenAndServe()
+		err := x.server.Serve(lis)
 		if err != nil && err != http.ErrServerClosed {
 			x.log.Fatalf("Error starting XatuHTTP server: %s", err)
 		}
@@ -105,428 +188,245 @@ func (x *XatuHTTP) Start(ctx context.Context) error {
 }
 
 func (x *XatuHTTP) Stop(ctx context.Context) error {
-	return nil
-}
+	// Let in-flight requests finish before stopping the workers they're waiting on.
+	err := x.server.Shutdown(ctx)
 
-func (x *XatuHTTP) OnFrame(fn func(ctx context.Context, frame *types.Frame)) {
-	x.onFrameCallbacks = append(x.onFrameCallbacks, fn)
-}
-
-func (x *XatuHTTP) registerHandler(ctx context.Context, mux *http.ServeMux) {
-	path := "/"
-	if x.config.Path != "" {
-		path = x.config.Path
+	if x.lis != nil {
+		// Already closed if the server was serving.
+		_ = x.lis.Close()
 	}
 
-	mux.HandleFunc(path, func(w http.ResponseWriter, req *http.Request) {
-		body, err := io.ReadAll(req.Body)
-		if err != nil {
-			w.WriteHeader(http.StatusBadRequest)
+	x.stopOnce.Do(func() {
+		close(x.done)
+	})
 
-			if _, err = w.Write([]byte(err.Error())); err != nil {
-				x.log.WithError(err).Error("Failed to write error response")
-			}
+	x.workers.Wait()
 
-			return
-		}
+	x.status.RecordStopped()
 
-		switch req.Header.Get("Content-Type") {
-		case "application/json":
-			if err = x.handleJSONRequest(ctx, body); err != nil {
-				w.WriteHeader(http.StatusBadRequest)
+	return err
+}
 
-				if _, err = w.Write([]byte(err.Error())); err != nil {
-					x.log.WithError(err).Error("Failed to write error response")
-				}
+func (x *XatuHTTP) startWorkers() {
+	for i := 0; i < x.config.Workers; i++ {
+		x.workers.Add(1)
 
-				return
-			}
-		case "application/x-ndjson":
-			if err = x.handleNDJSONRequest(ctx, body); err != nil {
-				w.WriteHeader(http.StatusBadRequest)
-				x.log.WithError(err).Error("Failed to handle NDJSON request")
+		go func() {
+			defer x.workers.Done()
 
-				if _, err = w.Write([]byte(err.Error())); err != nil {
-					x.log.WithError(err).Error("Failed to write error response")
-				}
+			for {
+				select {
+				case <-x.done:
+					return
+				case job := <-x.queue:
+					// Skip jobs whose client has already gone away.
+					if err := job.ctx.Err(); err != nil {
+						job.result <- err
 
-				return
-			}
-		default:
-			w.WriteHeader(http.StatusBadRequest)
+						continue
+					}
 
-			if _, err = w.Write([]byte("Unsupported content type")); err != nil {
-				x.log.WithError(err).Error("Failed to write error response")
+					job.result <- x.handleXatuEvents(job.ctx, job.events)
+				}
 			}
-
-			return
-		}
-
-		w.WriteHeader(http.StatusOK)
-
-		if _, err = w.Write([]byte("OK")); err != nil {
-			x.log.WithError(err).Error("Failed to write response")
-		}
-	})
+		}()
+	}
 }
 
-func (x *XatuHTTP) handleNDJSONRequest(ctx context.Context, body []byte) error {
-	s := string(body)
+func (x *XatuHTTP) registerHandler(mux *http.ServeMux) {
+	path := "/"
+	if x.config.Path != "" {
+		path = x.config.Path
+	}
 
-	events := []*xatu.DecoratedEvent{}
+	mux.HandleFunc(path, x.handleRequest)
+}
 
-	split := strings.Split(s, "\n")
+func (x *XatuHTTP) handleRequest(w http.ResponseWriter, req *http.Request) {
+	if !x.config.Auth.Authorize(req.Header.Get("Authorization")) {
+		x.metrics.ObserveRequestRejected(RequestRejectedUnauthorized)
 
-	for _, line := range split {
-		if line == "" {
-			continue
+		if x.config.Auth.Type == AuthTypeBasic {
+			w.Header().Set("WWW-Authenticate", `Basic realm="forky"`)
 		}
 
-		var v xatu.DecoratedEvent
-		if err := protojson.Unmarshal([]byte(line), &v); err != nil {
-			return err
-		}
+		x.writeResponse(w, http.StatusUnauthorized, "unauthorized")
 
-		events = append(events, &v)
+		return
 	}
 
-	x.handleXatuEvents(ctx, events)
+	select {
+	case <-x.done:
+		x.metrics.ObserveRequestRejected(RequestRejectedShuttingDown)
 
-	return nil
-}
+		x.writeResponse(w, http.StatusServiceUnavailable, "shutting down")
 
-func (x *XatuHTTP) handleJSONRequest(ctx context.Context, body []byte) error {
-	// Strip the outer array.
-	if len(body) > 0 && body[0] == '[' {
-		body = body[1:]
+		return
+	default:
 	}
 
-	if len(body) > 0 && body[len(body)-1] == ']' {
-		body = body[:len(body)-1]
-	}
-
-	event := xatu.DecoratedEvent{}
-
-	err := protojson.Unmarshal(body, &event)
+	body, err := x.readBody(w, req)
 	if err != nil {
-		return err
-	}
-
-	x.handleXatuEvents(ctx, []*xatu.DecoratedEvent{&event})
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) || errors.Is(err, errBodyTooLarge) {
+			x.metrics.ObserveRequestRejected(RequestRejectedTooLarge)
 
-	return nil
-}
+			x.writeResponse(w, http.StatusRequestEntityTooLarge, errBodyTooLarge.Error())
 
-func (x *XatuHTTP) handleXatuEvents(ctx context.Context, events []*xatu.DecoratedEvent) {
-	for _, event := range events {
-		if err := x.handleXatuEvent(ctx, event); err != nil {
-			logCtx := x.log.
-				WithField("event_id", event.GetMeta().GetClient().GetId()).
-				WithError(err)
-
-			eventJSON, err := protojson.Marshal(event)
-			if err != nil {
-				logCtx = logCtx.WithField("marshal_error", err)
-			} else {
-				logCtx = logCtx.WithField("event_json", string(eventJSON))
-			}
-
-			logCtx.Error("Failed to handle event")
+			return
 		}
-	}
-}
 
-func (x *XatuHTTP) handleXatuEvent(ctx context.Context, event *xatu.DecoratedEvent) error {
-	logCtx := x.log.WithField("event_id", event.GetMeta().GetClient().GetId())
+		x.metrics.ObserveRequestRejected(RequestRejectedBadRequest)
 
-	shouldBeFiltered, err := x.filter.ShouldBeDropped(event)
-	if err != nil {
-		logCtx.WithError(err).Error("Failed to check if event should be dropped")
+		x.writeResponse(w, http.StatusBadRequest, err.Error())
 
-		return err
+		return
 	}
 
-	if shouldBeFiltered {
-		// TODO(sam.calder-mason): Add metrics
-		logCtx.WithField("event_name", event.GetEvent().GetName()).Warn("Dropping xatu event as it was filtered out")
+	var events []*xatu.DecoratedEvent
 
-		return nil
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		events, err = x.decodeJSONRequest(body)
+	case "application/x-ndjson":
+		events, err = x.decodeNDJSONRequest(body)
+	default:
+		err = errors.New("unsupported content type")
 	}
 
-	// Drop it if its from a network that we don't care about
-	name := event.GetMeta().GetClient().GetEthereum().GetNetwork().GetName()
+	if err != nil {
+		x.log.WithError(err).Debug("Failed to decode request")
 
-	found := false
+		x.metrics.ObserveRequestRejected(RequestRejectedBadRequest)
 
-	for _, network := range x.opts.AllowedEthereumNetworks {
-		if network == name {
-			found = true
+		x.writeResponse(w, http.StatusBadRequest, err.Error())
 
-			break
-		}
+		return
 	}
 
-	if !found {
-		logCtx.
-			WithField("network", name).
-			WithField("allowed_networks", x.opts.AllowedEthereumNetworks).
-			Warn("Dropping xatu event as it is from a network we don't care about")
-
-		return nil
+	job := &xatuHTTPJob{
+		ctx:    req.Context(),
+		events: events,
+		result: make(chan error, 1),
 	}
 
-	switch event.Event.Name {
-	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE:
-		return x.handleForkChoiceEvent(ctx, event)
-	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_V2:
-		return x.handleForkChoiceV2Event(ctx, event)
-	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG:
-		return x.handleForkChoiceReorgEvent(ctx, event)
-	case xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG_V2:
-		return x.handleForkChoiceReorgV2Event(ctx, event)
-	}
+	select {
+	case x.queue <- job:
+	default:
+		x.metrics.ObserveRequestRejected(RequestRejectedQueueFull)
 
-	return errors.New("unknown event type") // Should never happen (touch wood (tm (c)))
-}
+		w.Header().Set("Retry-After", "1")
 
-func (x *XatuHTTP) handleForkChoiceEvent(ctx context.Context, event *xatu.DecoratedEvent) error {
-	// Create a new frame based on the event
-	fc := event.GetEthV1ForkChoiceV2()
-	if fc == nil {
-		return fmt.Errorf("event is not a fork choice event")
-	}
+		x.writeResponse(w, http.StatusTooManyRequests, "too many requests")
 
-	data, err := fc.AsGoEth2ClientV1ForkChoice()
-	if err != nil {
-		return fmt.Errorf("failed to convert event to fork choice: %w", err)
+		return
 	}
 
-	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoice()
-	if additionalData == nil {
-		return fmt.Errorf("event is missing additional data")
-	}
+	select {
+	case err = <-job.result:
+	case <-x.done:
+		x.metrics.ObserveRequestRejected(RequestRejectedShuttingDown)
 
-	return x.createFrameFromSnapshotAndData(ctx, event, data, additionalData.GetSnapshot(), "")
-}
+		x.writeResponse(w, http.StatusServiceUnavailable, "shutting down")
 
-func (x *XatuHTTP) handleForkChoiceV2Event(ctx context.Context, event *xatu.DecoratedEvent) error {
-	// Create a new frame based on the event
-	fc := event.GetEthV1ForkChoiceV2()
-	if fc == nil {
-		return fmt.Errorf("event is not a fork choice event")
+		return
+	case <-req.Context().Done():
+		// The client has gone away so there's no one to respond to.
+		return
 	}
 
-	data, err := fc.AsGoEth2ClientV1ForkChoice()
 	if err != nil {
-		return fmt.Errorf("failed to convert event to fork choice: %w", err)
-	}
-
-	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoiceV2()
-	if additionalData == nil {
-		return fmt.Errorf("event is missing additional data")
-	}
-
-	return x.createFrameFromSnapshotV2AndData(ctx, event, data, additionalData.GetSnapshot(), "")
-}
+		x.log.WithError(err).Error("Failed to persist events")
 
-func (x *XatuHTTP) handleForkChoiceReorgEvent(ctx context.Context, event *xatu.DecoratedEvent) error {
-	x.log.
-		WithField("event_id", event.GetMeta().GetClient().GetId()).
-		WithField("client_name", event.GetMeta().GetClient().GetName()).
-		Info("Handling fork choice reorg event")
+		x.writeResponse(w, http.StatusInternalServerError, "failed to persist events")
 
-	// Create 2 new frames based on the event (one for `before` the reorg and one for `after` the reorg)
-	// Note: `before` can be nil if the reorg happened before the xatu sentry started
-	fcr := event.GetEthV1ForkChoiceReorgV2()
-	if fcr == nil {
-		return fmt.Errorf("event is not a fork choice reorg event")
+		return
 	}
 
-	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoiceReorg()
-	if additionalData == nil {
-		return fmt.Errorf("event is missing additional data")
-	}
+	x.writeResponse(w, http.StatusOK, "OK")
+}
 
-	if fcr.After == nil && fcr.Before == nil {
-		return fmt.Errorf("event is missing both before and after data")
-	}
+var errBodyTooLarge = errors.New("request body too large")
 
-	if fcr.After != nil && additionalData.After != nil {
-		data, err := fcr.After.AsGoEth2ClientV1ForkChoice()
-		if err != nil {
-			x.log.WithError(err).Error("failed to convert fork_choice_reorg.after to fork choice")
-		} else {
-			err = x.createFrameFromSnapshotAndData(ctx, event, data, additionalData.After, "after")
-			if err != nil {
-				x.log.WithError(err).Error("failed to create frame from fork_choice_reorg.after")
-			}
-		}
-	}
+// readBody reads the request body, decompressing it if required. The body is
+// limited to the configured max size both before and after decompression.
+func (x *XatuHTTP) readBody(w http.ResponseWriter, req *http.Request) ([]byte, error) {
+	var reader io.Reader = http.MaxBytesReader(w, req.Body, x.config.MaxBodySize)
 
-	if fcr.Before != nil && additionalData.Before != nil {
-		data, err := fcr.Before.AsGoEth2ClientV1ForkChoice()
+	switch strings.ToLower(req.Header.Get("Content-Encoding")) {
+	case "", "identity":
+	case "gzip":
+		gz, err := gzip.NewReader(reader)
 		if err != nil {
-			x.log.WithError(err).Error("failed to convert fork_choice_reorg.before to fork choice")
-		} else {
-			err = x.createFrameFromSnapshotAndData(ctx, event, data, additionalData.Before, "before")
-			if err != nil {
-				x.log.WithError(err).Error("failed to create frame from fork_choice_reorg.before")
-			}
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
 		}
-	}
-
-	return nil
-}
 
-func (x *XatuHTTP) handleForkChoiceReorgV2Event(ctx context.Context, event *xatu.DecoratedEvent) error {
-	x.log.
-		WithField("event_id", event.GetMeta().GetClient().GetId()).
-		WithField("client_name", event.GetMeta().GetClient().GetName()).
-		Info("Handling fork choice reorg event")
+		defer gz.Close()
 
-	// Create 2 new frames based on the event (one for `before` the reorg and one for `after` the reorg)
-	// Note: `before` can be nil if the reorg happened before the xatu sentry started
-	fcr := event.GetEthV1ForkChoiceReorgV2()
-	if fcr == nil {
-		return fmt.Errorf("event is not a fork choice reorg event")
+		reader = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %s", req.Header.Get("Content-Encoding"))
 	}
 
-	additionalData := event.GetMeta().GetClient().GetEthV1DebugForkChoiceReorgV2()
-	if additionalData == nil {
-		return fmt.Errorf("event is missing additional data")
-	}
-
-	if fcr.After == nil && fcr.Before == nil {
-		return fmt.Errorf("event is missing both before and after data")
-	}
-
-	if fcr.After != nil && additionalData.After != nil {
-		data, err := fcr.After.AsGoEth2ClientV1ForkChoice()
-		if err != nil {
-			x.log.WithError(err).Error("failed to convert fork_choice_reorg.after to fork choice")
-		} else {
-			err = x.createFrameFromSnapshotV2AndData(ctx, event, data, additionalData.After, "after")
-			if err != nil {
-				x.log.WithError(err).Error("failed to create frame from fork_choice_reorg.after")
-			}
-		}
+	body, err := io.ReadAll(io.LimitReader(reader, x.config.MaxBodySize+1))
+	if err != nil {
+		return nil, err
 	}
 
-	if fcr.Before != nil && additionalData.Before != nil {
-		data, err := fcr.Before.AsGoEth2ClientV1ForkChoice()
-		if err != nil {
-			x.log.WithError(err).Error("failed to convert fork_choice_reorg.before to fork choice")
-		} else {
-			err = x.createFrameFromSnapshotV2AndData(ctx, event, data, additionalData.Before, "before")
-			if err != nil {
-				x.log.WithError(err).Error("failed to create frame from fork_choice_reorg.before")
-			}
-		}
+	if int64(len(body)) > x.config.MaxBodySize {
+		return nil, errBodyTooLarge
 	}
 
-	return nil
+	return body, nil
 }
 
-func (x *XatuHTTP) createFrameFromSnapshotAndData(ctx context.Context,
-	event *xatu.DecoratedEvent,
-	data *eth2v1.ForkChoice,
-	snapshot *xatu.ClientMeta_ForkChoiceSnapshot,
-	timing string,
-) error {
-	frame := &types.Frame{
-		Metadata: types.FrameMetadata{
-			ID:   uuid.New().String(),
-			Node: event.Meta.Client.Name,
-
-			WallClockSlot:  phase0.Slot(snapshot.GetRequestSlot().Number),
-			WallClockEpoch: phase0.Epoch(snapshot.GetRequestEpoch().Number),
-
-			FetchedAt: snapshot.GetTimestamp().AsTime(),
-
-			ConsensusClient: event.GetMeta().GetClient().GetEthereum().GetConsensus().GetImplementation(),
-
-			EventSource: types.XatuPollingEventSource.String(),
+func (x *XatuHTTP) writeResponse(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
 
-			Labels: []string{},
-		},
-		Data: data,
+	if _, err := w.Write([]byte(message)); err != nil {
+		x.log.WithError(err).Error("Failed to write response")
 	}
+}
 
-	if event.GetEvent().GetName() == xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG {
-		data := event.GetEthV1ForkChoiceReorgV2()
+func (x *XatuHTTP) decodeNDJSONRequest(body []byte) ([]*xatu.DecoratedEvent, error) {
+	s := string(body)
 
-		prefix := "xatu_reorg_event_"
+	events := []*xatu.DecoratedEvent{}
 
-		frame.Metadata.EventSource = types.XatuReorgEventEventSource.String()
+	split := strings.Split(s, "\n")
 
-		frame.Metadata.Labels = append(frame.Metadata.Labels,
-			fmt.Sprintf(prefix+"slot=%d", data.GetEvent().GetSlot()),
-			fmt.Sprintf(prefix+"epoch=%d", data.GetEvent().GetEpoch()),
-			prefix+"old_head_block="+data.GetEvent().GetOldHeadBlock(),
-			prefix+"old_head_state="+data.GetEvent().GetOldHeadState(),
-			prefix+"new_head_block="+data.GetEvent().GetNewHeadBlock(),
-			prefix+"new_head_state="+data.GetEvent().GetNewHeadState(),
-			fmt.Sprintf(prefix+"depth=%d", +data.GetEvent().GetDepth().GetValue()),
+	for _, line := range split {
+		if line == "" {
+			continue
+		}
 
-			"xatu_reorg_frame_timing="+timing,
-		)
-	}
+		var v xatu.DecoratedEvent
+		if err := protojson.Unmarshal([]byte(line), &v); err != nil {
+			return nil, err
+		}
 
-	for _, fn := range x.onFrameCallbacks {
-		fn(ctx, frame)
+		events = append(events, &v)
 	}
 
-	return nil
+	return events, nil
 }
 
-func (x *XatuHTTP) createFrameFromSnapshotV2AndData(ctx context.Context,
-	event *xatu.DecoratedEvent,
-	data *eth2v1.ForkChoice,
-	snapshot *xatu.ClientMeta_ForkChoiceSnapshotV2,
-	timing string,
-) error {
-	frame := &types.Frame{
-		Metadata: types.FrameMetadata{
-			ID:   uuid.New().String(),
-			Node: event.Meta.Client.Name,
-
-			WallClockSlot:  phase0.Slot(snapshot.GetRequestSlot().GetNumber().GetValue()),
-			WallClockEpoch: phase0.Epoch(snapshot.GetRequestEpoch().GetNumber().GetValue()),
-
-			FetchedAt: snapshot.GetTimestamp().AsTime(),
-
-			ConsensusClient: event.GetMeta().GetClient().GetEthereum().GetConsensus().GetImplementation(),
-
-			EventSource: types.XatuPollingEventSource.String(),
-
-			Labels: []string{},
-		},
-		Data: data,
+func (x *XatuHTTP) decodeJSONRequest(body []byte) ([]*xatu.DecoratedEvent, error) {
+	// Strip the outer array.
+	if len(body) > 0 && body[0] == '[' {
+		body = body[1:]
 	}
 
-	if event.GetEvent().GetName() == xatu.Event_BEACON_API_ETH_V1_DEBUG_FORK_CHOICE_REORG_V2 {
-		data := event.GetEthV1ForkChoiceReorgV2()
-
-		prefix := "xatu_reorg_event_"
-
-		frame.Metadata.EventSource = types.XatuReorgEventEventSource.String()
-
-		frame.Metadata.Labels = append(frame.Metadata.Labels,
-			fmt.Sprintf(prefix+"slot=%d", data.GetEvent().GetSlot().GetValue()),
-			fmt.Sprintf(prefix+"epoch=%d", data.GetEvent().GetEpoch().GetValue()),
-			prefix+"old_head_block="+data.GetEvent().GetOldHeadBlock(),
-			prefix+"old_head_state="+data.GetEvent().GetOldHeadState(),
-			prefix+"new_head_block="+data.GetEvent().GetNewHeadBlock(),
-			prefix+"new_head_state="+data.GetEvent().GetNewHeadState(),
-			fmt.Sprintf(prefix+"depth=%d", +data.GetEvent().GetDepth().GetValue()),
-
-			"xatu_reorg_frame_timing="+timing,
-		)
+	if len(body) > 0 && body[len(body)-1] == ']' {
+		body = body[:len(body)-1]
 	}
 
-	for _, fn := range x.onFrameCallbacks {
-		fn(ctx, frame)
+	event := xatu.DecoratedEvent{}
+
+	err := protojson.Unmarshal(body, &event)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return []*xatu.DecoratedEvent{&event}, nil
 }
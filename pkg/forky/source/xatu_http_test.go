@@ -0,0 +1,136 @@
+package source
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestXatuHTTP(t *testing.T, config *XatuHTTPConfig) *XatuHTTP {
+	t.Helper()
+
+	config.Address = ":0"
+
+	source, err := NewXatuHTTP("forky_test", "xatu", logrus.New(), config, NewBasicMetrics("forky_test", XatuHTTPType, "xatu", false), &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source.registerHandler(source.mux)
+
+	return source
+}
+
+func gzipBody(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	gz := gzip.NewWriter(&buf)
+
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func doXatuHTTPRequest(source *XatuHTTP, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+
+	source.mux.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestXatuHTTP(t *testing.T) {
+	t.Run("requires auth", func(t *testing.T) {
+		source := newTestXatuHTTP(t, &XatuHTTPConfig{
+			Auth: AuthConfig{Type: AuthTypeBearer, Token: "secret"},
+		})
+
+		source.startWorkers()
+
+		defer source.Stop(t.Context())
+
+		rec := doXatuHTTPRequest(source, []byte("\n"), nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec = doXatuHTTPRequest(source, []byte("\n"), map[string]string{"Authorization": "Bearer wrong"})
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		rec = doXatuHTTPRequest(source, []byte("\n"), map[string]string{"Authorization": "Bearer secret"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("limits body size", func(t *testing.T) {
+		source := newTestXatuHTTP(t, &XatuHTTPConfig{MaxBodySize: 16})
+
+		source.startWorkers()
+
+		defer source.Stop(t.Context())
+
+		rec := doXatuHTTPRequest(source, []byte(strings.Repeat("\n", 32)), nil)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+		// Compressed bodies are limited after decompression too.
+		rec = doXatuHTTPRequest(source, gzipBody(t, []byte(strings.Repeat("\n", 1024))), map[string]string{"Content-Encoding": "gzip"})
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("decompresses gzip bodies", func(t *testing.T) {
+		source := newTestXatuHTTP(t, &XatuHTTPConfig{})
+
+		source.startWorkers()
+
+		defer source.Stop(t.Context())
+
+		rec := doXatuHTTPRequest(source, gzipBody(t, []byte("\n\n")), map[string]string{"Content-Encoding": "gzip"})
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		rec = doXatuHTTPRequest(source, []byte("not gzip"), map[string]string{"Content-Encoding": "gzip"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = doXatuHTTPRequest(source, []byte("\n"), map[string]string{"Content-Encoding": "br"})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("rejects requests when the queue is full", func(t *testing.T) {
+		source := newTestXatuHTTP(t, &XatuHTTPConfig{QueueSize: 1})
+
+		// No workers are running so the queue never drains.
+		source.queue <- &xatuHTTPJob{}
+
+		rec := doXatuHTTPRequest(source, []byte("\n"), nil)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("rejects requests when stopped", func(t *testing.T) {
+		source := newTestXatuHTTP(t, &XatuHTTPConfig{})
+
+		source.startWorkers()
+
+		assert.NoError(t, source.Stop(t.Context()))
+
+		rec := doXatuHTTPRequest(source, []byte("\n"), nil)
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
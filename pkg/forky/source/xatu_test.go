@@ -0,0 +1,75 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/ethpandaops/xatu/pkg/proto/xatu"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestXatuEventHandler(t *testing.T) *xatuEventHandler {
+	t.Helper()
+
+	handler, err := newXatuEventHandler(logrus.New(), &Options{}, NewBasicMetrics("forky_test", XatuHTTPType, "xatu", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return handler
+}
+
+func TestXatuEventHandler(t *testing.T) {
+	t.Run("invalid frames aren't retried", func(t *testing.T) {
+		handler := newTestXatuEventHandler(t)
+
+		published := 0
+
+		handler.OnFrame(func(_ context.Context, _ *types.Frame) error {
+			published++
+
+			return nil
+		})
+
+		frame := types.GenerateFakeFrame()
+		frame.Metadata.Node = ""
+
+		err := handler.publishFrame(context.Background(), frame)
+		assert.ErrorIs(t, err, errInvalidEvent)
+		assert.NotErrorIs(t, err, errFailedToPersist)
+		assert.Equal(t, 0, published)
+
+		err = handler.publishReorg(context.Background(), &types.Reorg{ID: "reorg", Node: "node-a"})
+		assert.ErrorIs(t, err, errInvalidEvent)
+		assert.NotErrorIs(t, err, errFailedToPersist)
+	})
+
+	t.Run("frames that fail to persist are retried", func(t *testing.T) {
+		handler := newTestXatuEventHandler(t)
+
+		handler.OnFrame(func(_ context.Context, _ *types.Frame) error {
+			return context.DeadlineExceeded
+		})
+
+		err := handler.publishFrame(context.Background(), types.GenerateFakeFrame())
+		assert.ErrorIs(t, err, errFailedToPersist)
+		assert.NotErrorIs(t, err, errInvalidEvent)
+
+		err = handler.publishReorg(context.Background(), &types.Reorg{ID: "reorg", Node: "node-a", DetectedAt: time.Now()})
+		assert.NoError(t, err)
+	})
+
+	t.Run("frame IDs are derived from the event", func(t *testing.T) {
+		event := &xatu.DecoratedEvent{Event: &xatu.Event{Id: "event-1"}}
+
+		assert.Equal(t, frameIDFromEvent(event, "after"), frameIDFromEvent(event, "after"))
+		assert.NotEqual(t, frameIDFromEvent(event, "after"), frameIDFromEvent(event, "before"))
+		assert.NotEqual(t, frameIDFromEvent(event, ""), frameIDFromEvent(&xatu.DecoratedEvent{Event: &xatu.Event{Id: "event-2"}}, ""))
+
+		// Events without an ID get a random one.
+		assert.NotEqual(t, frameIDFromEvent(&xatu.DecoratedEvent{}, ""), frameIDFromEvent(&xatu.DecoratedEvent{}, ""))
+	})
+}
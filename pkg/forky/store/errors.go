@@ -6,4 +6,5 @@ var (
 	ErrFrameNotFound      = errors.New("frame not found")
 	ErrFrameAlreadyStored = errors.New("frame already stored")
 	ErrFrameInvalid       = errors.New("frame invalid")
+	ErrRawFrameNotFound   = errors.New("raw frame not found")
 )
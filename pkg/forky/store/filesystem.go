@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ethpandaops/forky/pkg/forky/types"
 )
@@ -18,6 +19,7 @@ type FileSystem struct {
 
 type FileSystemConfig struct {
 	BaseDir string `yaml:"base_dir"`
+	Format  Format `yaml:"format"`
 }
 
 // NewFileSystem creates a new FileSystem instance with the specified base directory.
@@ -26,7 +28,13 @@ func NewFileSystem(namespace string, config FileSystemConfig, opts *Options) (*F
 		return nil, fmt.Errorf("base directory is required")
 	}
 
-	err := os.MkdirAll(config.BaseDir, 0o755)
+	config.Format = FormatOrDefault(config.Format)
+
+	if !IsValidFormat(config.Format) {
+		return nil, fmt.Errorf("invalid format: %s", config.Format)
+	}
+
+	err := os.MkdirAll(filepath.Join(config.BaseDir, rawFrameDir), 0o755)
 	if err != nil {
 		return nil, err
 	}
@@ -41,11 +49,15 @@ func NewFileSystem(namespace string, config FileSystemConfig, opts *Options) (*F
 }
 
 func (fs *FileSystem) framePath(id string) string {
-	return filepath.Join(fs.config.BaseDir, fmt.Sprintf("%s.json.gz", id))
+	return filepath.Join(fs.config.BaseDir, id+fs.config.Format.Extension())
+}
+
+func (fs *FileSystem) rawFramePath(id string) string {
+	return filepath.Join(fs.config.BaseDir, rawFrameDir, id+rawFrameExtension)
 }
 
 func (fs *FileSystem) SaveFrame(ctx context.Context, frame *types.Frame) error {
-	data, err := frame.AsGzipJSON()
+	data, err := fs.config.Format.Encode(frame)
 	if err != nil {
 		return err
 	}
@@ -66,24 +78,22 @@ func (fs *FileSystem) GetFrame(ctx context.Context, id string) (*types.Frame, er
 	path := fs.framePath(id)
 
 	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
-
-	var frame types.Frame
-
-	err = frame.FromGzipJSON(data)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return nil, ErrFrameNotFound
 		}
 
+		return nil, err
+	}
+
+	frame, err := fs.config.Format.Decode(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to read frame from disk: %v", err.Error())
 	}
 
 	fs.basicMetrics.ObserveItemRetreived(string(FrameDataType))
 
-	return &frame, nil
+	return frame, nil
 }
 
 func (fs *FileSystem) DeleteFrame(ctx context.Context, id string) error {
@@ -100,5 +110,69 @@ func (fs *FileSystem) DeleteFrame(ctx context.Context, id string) error {
 
 	fs.basicMetrics.ObserveItemRemoved(string(FrameDataType))
 
+	if err := os.Remove(fs.rawFramePath(id)); err == nil {
+		fs.basicMetrics.ObserveItemRemoved(string(RawFrameDataType))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete raw frame from disk: %v", err.Error())
+	}
+
 	return nil
 }
+
+func (fs *FileSystem) ListFrames(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(fs.config.BaseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list frames on disk: %v", err.Error())
+	}
+
+	ids := []string{}
+
+	extension := fs.config.Format.Extension()
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), extension) {
+			continue
+		}
+
+		ids = append(ids, strings.TrimSuffix(entry.Name(), extension))
+	}
+
+	fs.basicMetrics.ObserveItemStored(string(FrameDataType), len(ids))
+
+	return ids, nil
+}
+
+func (fs *FileSystem) SaveRawFrame(ctx context.Context, id string, data []byte) error {
+	encoded, err := encodeRawFrame(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(fs.rawFramePath(id), encoded, 0o600); err != nil {
+		return fmt.Errorf("failed to write raw frame to disk: %v", err.Error())
+	}
+
+	fs.basicMetrics.ObserveItemAdded(string(RawFrameDataType))
+
+	return nil
+}
+
+func (fs *FileSystem) GetRawFrame(ctx context.Context, id string) ([]byte, error) {
+	encoded, err := os.ReadFile(fs.rawFramePath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrRawFrameNotFound
+		}
+
+		return nil, err
+	}
+
+	data, err := decodeRawFrame(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read raw frame from disk: %v", err.Error())
+	}
+
+	fs.basicMetrics.ObserveItemRetreived(string(RawFrameDataType))
+
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+)
+
+// Format is the encoding used to persist frames.
+type Format string
+
+const (
+	FormatGzipJSON Format = "json.gz"
+	FormatJSON     Format = "json"
+)
+
+func IsValidFormat(f Format) bool {
+	switch f {
+	case FormatGzipJSON, FormatJSON:
+		return true
+	default:
+		return false
+	}
+}
+
+// FormatOrDefault returns the format, or the default format if none is set.
+func FormatOrDefault(f Format) Format {
+	if f == "" {
+		return FormatGzipJSON
+	}
+
+	return f
+}
+
+// Extension returns the file extension (including the leading dot) for the format.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Encode encodes the frame in the format.
+func (f Format) Encode(frame *types.Frame) ([]byte, error) {
+	switch f {
+	case FormatGzipJSON:
+		return frame.AsGzipJSON()
+	case FormatJSON:
+		return frame.AsJSON()
+	default:
+		return nil, fmt.Errorf("unknown format: %s", f)
+	}
+}
+
+// Decode decodes a frame that was encoded in the format.
+func (f Format) Decode(data []byte) (*types.Frame, error) {
+	var frame types.Frame
+
+	var err error
+
+	switch f {
+	case FormatGzipJSON:
+		err = frame.FromGzipJSON(data)
+	case FormatJSON:
+		err = frame.FromJSON(data)
+	default:
+		err = fmt.Errorf("unknown format: %s", f)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &frame, nil
+}
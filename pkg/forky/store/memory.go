@@ -10,6 +10,7 @@ import (
 
 type MemoryStore struct {
 	frames map[string]*types.Frame
+	raw    map[string][]byte
 	mu     sync.Mutex
 
 	opts *Options
@@ -24,6 +25,7 @@ func NewMemoryStore(namespace string, log logrus.FieldLogger, opts *Options) *Me
 
 	return &MemoryStore{
 		frames:       make(map[string]*types.Frame),
+		raw:          make(map[string][]byte),
 		log:          log,
 		opts:         opts,
 		basicMetrics: metrics,
@@ -73,5 +75,51 @@ func (s *MemoryStore) DeleteFrame(ctx context.Context, id string) error {
 
 	s.basicMetrics.ObserveItemRemoved(string(FrameDataType))
 
+	if _, ok := s.raw[id]; ok {
+		delete(s.raw, id)
+
+		s.basicMetrics.ObserveItemRemoved(string(RawFrameDataType))
+	}
+
 	return nil
 }
+
+func (s *MemoryStore) ListFrames(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.frames))
+
+	for id := range s.frames {
+		ids = append(ids, id)
+	}
+
+	s.basicMetrics.ObserveItemStored(string(FrameDataType), len(ids))
+
+	return ids, nil
+}
+
+func (s *MemoryStore) SaveRawFrame(ctx context.Context, id string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.raw[id] = append([]byte{}, data...)
+
+	s.basicMetrics.ObserveItemAdded(string(RawFrameDataType))
+
+	return nil
+}
+
+func (s *MemoryStore) GetRawFrame(ctx context.Context, id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.raw[id]
+	if !ok {
+		return nil, ErrRawFrameNotFound
+	}
+
+	s.basicMetrics.ObserveItemRetreived(string(RawFrameDataType))
+
+	return data, nil
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MigrateOptions controls how frames are copied between stores.
+type MigrateOptions struct {
+	// Workers is the number of frames copied concurrently.
+	Workers int
+	// Verify reads every frame back from the destination and compares it to the source.
+	Verify bool
+	// SkipExisting skips frames that already exist in the destination.
+	SkipExisting bool
+	// Progress is called after every frame has been processed.
+	Progress func(result MigrateResult)
+}
+
+func DefaultMigrateOptions() MigrateOptions {
+	return MigrateOptions{
+		Workers:      8,
+		Verify:       true,
+		SkipExisting: true,
+	}
+}
+
+func (o *MigrateOptions) Validate() error {
+	if o.Workers < 1 {
+		return errors.New("workers must be greater than 0")
+	}
+
+	return nil
+}
+
+// MigrateResult summarises a migration.
+type MigrateResult struct {
+	Total    int `json:"total"`
+	Copied   int `json:"copied"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+	Verified int `json:"verified"`
+}
+
+// Migrate copies the frames with the given IDs, and their raw payloads, from one
+// store to another. Frames are decoded from the source and re-encoded by the
+// destination, so stores configured with different formats transcode frames as
+// they're copied.
+func Migrate(ctx context.Context, log logrus.FieldLogger, from, to Store, ids []string, opts MigrateOptions) (*MigrateResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	existing := map[string]struct{}{}
+
+	if opts.SkipExisting {
+		existingIDs, err := to.ListFrames(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list frames in destination: %w", err)
+		}
+
+		for _, id := range existingIDs {
+			existing[id] = struct{}{}
+		}
+	}
+
+	result := MigrateResult{
+		Total: len(ids),
+	}
+
+	mu := sync.Mutex{}
+
+	record := func(fn func(r *MigrateResult)) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		fn(&result)
+
+		if opts.Progress != nil {
+			opts.Progress(result)
+		}
+	}
+
+	queue := make(chan string)
+
+	go func() {
+		defer close(queue)
+
+		for _, id := range ids {
+			if _, ok := existing[id]; ok {
+				record(func(r *MigrateResult) { r.Skipped++ })
+
+				continue
+			}
+
+			select {
+			case queue <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for id := range queue {
+				skipped, verified, err := migrateFrame(ctx, from, to, id, opts.Verify)
+				if err != nil {
+					log.WithError(err).WithField("frame_id", id).Error("Failed to migrate frame")
+
+					record(func(r *MigrateResult) { r.Failed++ })
+
+					continue
+				}
+
+				record(func(r *MigrateResult) {
+					if skipped {
+						r.Skipped++
+
+						return
+					}
+
+					r.Copied++
+
+					if verified {
+						r.Verified++
+					}
+				})
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return &result, err
+	}
+
+	return &result, nil
+}
+
+func migrateFrame(ctx context.Context, from, to Store, id string, verify bool) (skipped, verified bool, err error) {
+	frame, err := from.GetFrame(ctx, id)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to get frame from source: %w", err)
+	}
+
+	if err := frame.Validate(); err != nil {
+		return false, false, fmt.Errorf("%w: %s", ErrFrameInvalid, err.Error())
+	}
+
+	if err := to.SaveFrame(ctx, frame); err != nil {
+		if errors.Is(err, ErrFrameAlreadyStored) {
+			return true, false, nil
+		}
+
+		return false, false, fmt.Errorf("failed to save frame to destination: %w", err)
+	}
+
+	// Raw payloads are optional, so frames without one are copied as they are.
+	raw, err := from.GetRawFrame(ctx, id)
+	if err != nil && !errors.Is(err, ErrRawFrameNotFound) {
+		return false, false, fmt.Errorf("failed to get raw frame from source: %w", err)
+	}
+
+	if raw != nil {
+		if err := to.SaveRawFrame(ctx, id, raw); err != nil {
+			return false, false, fmt.Errorf("failed to save raw frame to destination: %w", err)
+		}
+	}
+
+	if !verify {
+		return false, false, nil
+	}
+
+	copied, err := to.GetFrame(ctx, id)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to read back frame from destination: %w", err)
+	}
+
+	expected, err := frame.AsJSON()
+	if err != nil {
+		return false, false, err
+	}
+
+	actual, err := copied.AsJSON()
+	if err != nil {
+		return false, false, err
+	}
+
+	if !bytes.Equal(expected, actual) {
+		return false, false, errors.New("frame in destination does not match source")
+	}
+
+	return false, true, nil
+}
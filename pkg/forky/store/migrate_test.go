@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethpandaops/forky/pkg/forky/types"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Run("memory to filesystem with transcoding", func(t *testing.T) {
+		ctx := context.Background()
+		log := logrus.New()
+		opts := DefaultOptions().SetMetricsEnabled(false)
+
+		from := NewMemoryStore("forky_test", log, opts)
+
+		to, err := NewFileSystem("forky_test", FileSystemConfig{
+			BaseDir: t.TempDir(),
+			Format:  FormatJSON,
+		}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := []string{}
+
+		for i := 0; i < 10; i++ {
+			frame := types.GenerateFakeFrame()
+
+			err = from.SaveFrame(ctx, frame)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids = append(ids, frame.Metadata.ID)
+		}
+
+		// Raw payloads are copied byte-for-byte.
+		raw := []byte(`{"fork_choice_nodes": [{"weight": 1}] }`)
+
+		err = from.SaveRawFrame(ctx, ids[0], raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := Migrate(ctx, log, from, to, ids, DefaultMigrateOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, 10, result.Copied)
+		assert.Equal(t, 10, result.Verified)
+		assert.Equal(t, 0, result.Failed)
+
+		listed, err := to.ListFrames(ctx)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, ids, listed)
+
+		copiedRaw, err := to.GetRawFrame(ctx, ids[0])
+		assert.NoError(t, err)
+		assert.Equal(t, raw, copiedRaw)
+
+		_, err = to.GetRawFrame(ctx, ids[1])
+		assert.ErrorIs(t, err, ErrRawFrameNotFound)
+
+		// Running it again should skip everything.
+		result, err = Migrate(ctx, log, from, to, ids, DefaultMigrateOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Copied)
+		assert.Equal(t, 10, result.Skipped)
+
+		// Raw payloads are deleted along with their frame.
+		assert.NoError(t, to.DeleteFrame(ctx, ids[0]))
+
+		_, err = to.GetRawFrame(ctx, ids[0])
+		assert.ErrorIs(t, err, ErrRawFrameNotFound)
+	})
+
+	t.Run("missing frame in source", func(t *testing.T) {
+		ctx := context.Background()
+		log := logrus.New()
+		opts := DefaultOptions().SetMetricsEnabled(false)
+
+		from := NewMemoryStore("forky_test", log, opts)
+		to := NewMemoryStore("forky_test", log, opts)
+
+		result, err := Migrate(ctx, log, from, to, []string{"missing"}, DefaultMigrateOptions())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Failed)
+	})
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// rawFrameExtension is the file extension of raw frame payloads. Payloads are
+// compressed at rest but always returned exactly as they were saved.
+const rawFrameExtension = ".json.gz"
+
+// rawFrameDir is the directory, relative to where frames are stored, that raw
+// frame payloads are kept in.
+const rawFrameDir = "raw"
+
+func encodeRawFrame(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+
+	gz := gzip.NewWriter(&b)
+
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func decodeRawFrame(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
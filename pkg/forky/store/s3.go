@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -39,10 +40,17 @@ type S3StoreConfig struct {
 	AccessKey    string `yaml:"access_key"`
 	AccessSecret string `yaml:"access_secret"`
 	UsePathStyle bool   `yaml:"use_path_style"`
+	Format       Format `yaml:"format"`
 }
 
 // NewS3Store creates a new S3Store instance with the specified AWS configuration, bucket name, and key prefix.
 func NewS3Store(namespace string, log logrus.FieldLogger, config *S3StoreConfig, opts *Options) (*S3Store, error) {
+	config.Format = FormatOrDefault(config.Format)
+
+	if !IsValidFormat(config.Format) {
+		return nil, fmt.Errorf("invalid format: %s", config.Format)
+	}
+
 	resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...any) (aws.Endpoint, error) {
 		return aws.Endpoint{
 			PartitionID:       "aws",
@@ -82,12 +90,12 @@ func NewS3Store(namespace string, log logrus.FieldLogger, config *S3StoreConfig,
 }
 
 func (s *S3Store) SaveFrame(ctx context.Context, frame *types.Frame) error {
-	gzipped, err := frame.AsGzipJSON()
+	encoded, err := s.config.Format.Encode(frame)
 	if err != nil {
 		return err
 	}
 
-	reader := bytes.NewReader(gzipped)
+	reader := bytes.NewReader(encoded)
 
 	_, err = s.s3Client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket: aws.String(s.config.BucketName),
@@ -146,7 +154,7 @@ func (s *S3Store) GetFrame(ctx context.Context, id string) (*types.Frame, error)
 	}
 	defer data.Body.Close()
 
-	// Read the gzipped data into a buffer.
+	// Read the encoded data into a buffer.
 	var buff bytes.Buffer
 
 	_, err = buff.ReadFrom(data.Body)
@@ -154,18 +162,16 @@ func (s *S3Store) GetFrame(ctx context.Context, id string) (*types.Frame, error)
 		return nil, err
 	}
 
-	var frame types.Frame
-
-	err = frame.FromGzipJSON(buff.Bytes())
+	frame, err := s.config.Format.Decode(buff.Bytes())
 	if err != nil {
 		return nil, err
 	}
 
-	s.frameCache.Set(id, &frame, time.Minute*3)
+	s.frameCache.Set(id, frame, time.Minute*3)
 
 	s.basicMetrics.ObserveItemRetreived(string(FrameDataType))
 
-	return &frame, nil
+	return frame, nil
 }
 
 func (s *S3Store) DeleteFrame(ctx context.Context, id string) error {
@@ -183,21 +189,135 @@ func (s *S3Store) DeleteFrame(ctx context.Context, id string) error {
 				return errors.New("failed to delete frame: " + apiErr.Error())
 			}
 		}
+
+		return err
 	}
 
 	s.basicMetrics.ObserveItemRemoved(string(FrameDataType))
 
-	return err
+	// Deleting an object that doesn't exist succeeds, so this is safe for
+	// frames without a raw payload.
+	_, err = s.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.config.BucketName),
+		Key:    aws.String(s.getRawFullName(id)),
+	})
+	if err != nil {
+		return errors.New("failed to delete raw frame: " + err.Error())
+	}
+
+	return nil
+}
+
+func (s *S3Store) ListFrames(ctx context.Context) ([]string, error) {
+	prefix := s.getFramesPath() + "/"
+
+	paginator := s3.NewListObjectsV2Paginator(s.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.config.BucketName),
+		Prefix: aws.String(prefix),
+	})
+
+	ids := []string{}
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) {
+				return nil, errors.New("failed to list frames: " + apiErr.Error())
+			}
+
+			return nil, err
+		}
+
+		for _, object := range page.Contents {
+			key := strings.TrimPrefix(aws.ToString(object.Key), prefix)
+
+			if strings.Contains(key, "/") || !strings.HasSuffix(key, s.config.Format.Extension()) {
+				continue
+			}
+
+			ids = append(ids, strings.TrimSuffix(key, s.config.Format.Extension()))
+		}
+	}
+
+	s.basicMetrics.ObserveItemStored(string(FrameDataType), len(ids))
+
+	return ids, nil
 }
 
 func (s *S3Store) getFullName(id string) string {
 	return filepath.Join(s.getFramesPath(), s.getFilename(id))
 }
 
+func (s *S3Store) getRawFullName(id string) string {
+	return filepath.Join(s.config.KeyPrefix, rawFrameDir, id+rawFrameExtension)
+}
+
 func (s *S3Store) getFramesPath() string {
 	return filepath.Join(s.config.KeyPrefix, "frames")
 }
 
 func (s *S3Store) getFilename(id string) string {
-	return fmt.Sprintf("%s.json.gz", id)
+	return id + s.config.Format.Extension()
+}
+
+func (s *S3Store) SaveRawFrame(ctx context.Context, id string, data []byte) error {
+	encoded, err := encodeRawFrame(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.config.BucketName),
+		Key:    aws.String(s.getRawFullName(id)),
+		Body:   bytes.NewReader(encoded),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			return errors.New("failed to save raw frame: " + apiErr.Error())
+		}
+
+		return err
+	}
+
+	s.basicMetrics.ObserveItemAdded(string(RawFrameDataType))
+
+	return nil
+}
+
+func (s *S3Store) GetRawFrame(ctx context.Context, id string) ([]byte, error) {
+	data, err := s.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.config.BucketName),
+		Key:    aws.String(s.getRawFullName(id)),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			switch apiErr.(type) {
+			case *s3types.NotFound, *s3types.NoSuchKey:
+				return nil, ErrRawFrameNotFound
+			default:
+				return nil, errors.New("failed to get raw frame: " + apiErr.Error())
+			}
+		}
+
+		return nil, err
+	}
+	defer data.Body.Close()
+
+	var buff bytes.Buffer
+
+	if _, err := buff.ReadFrom(data.Body); err != nil {
+		return nil, err
+	}
+
+	raw, err := decodeRawFrame(buff.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	s.basicMetrics.ObserveItemRetreived(string(RawFrameDataType))
+
+	return raw, nil
 }
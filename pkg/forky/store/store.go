@@ -17,6 +17,14 @@ type Store interface {
 	GetFrame(ctx context.Context, id string) (*types.Frame, error)
 	// Delete deletes a frame from the store
 	DeleteFrame(ctx context.Context, id string) error
+	// ListFrames lists the IDs of all frames in the store
+	ListFrames(ctx context.Context) ([]string, error)
+
+	// SaveRawFrame saves the verbatim payload a frame was parsed from. It's
+	// deleted along with the frame.
+	SaveRawFrame(ctx context.Context, id string, data []byte) error
+	// GetRawFrame fetches the verbatim payload a frame was parsed from
+	GetRawFrame(ctx context.Context, id string) ([]byte, error)
 }
 
 func NewStore(namespace string, log logrus.FieldLogger, storeType Type, config yaml.RawMessage, opts *Options) (Store, error) {
@@ -21,7 +21,8 @@ func IsValidStoreType(st Type) bool {
 type DataType string
 
 const (
-	UnknownDataType DataType = "unknown"
-	FrameDataType   DataType = "frame"
-	BlockDataType   DataType = "block"
+	UnknownDataType  DataType = "unknown"
+	FrameDataType    DataType = "frame"
+	RawFrameDataType DataType = "raw_frame"
+	BlockDataType    DataType = "block"
 )
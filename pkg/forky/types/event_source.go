@@ -10,6 +10,7 @@ const (
 	BeaconNodeEventSource     EventSource = "beacon_node"
 	XatuPollingEventSource    EventSource = "xatu_polling"
 	XatuReorgEventEventSource EventSource = "xatu_reorg_event"
+	FileReplayEventSource     EventSource = "file_replay"
 )
 
 func NewEventSourceFromString(s string) EventSource {
@@ -22,6 +23,8 @@ func NewEventSourceFromString(s string) EventSource {
 		return XatuPollingEventSource
 	case string(XatuReorgEventEventSource):
 		return XatuReorgEventEventSource
+	case string(FileReplayEventSource):
+		return FileReplayEventSource
 	default:
 		return NilEventSource
 	}
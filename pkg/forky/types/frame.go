@@ -31,8 +31,14 @@ type FrameMetadata struct {
 	Labels []string `json:"labels"`
 	// ConsensusClient is the consensus client that provided the frame.
 	ConsensusClient string `json:"consensus_client"`
+	// ConsensusClientVersion is the release of the consensus client, e.g. "5.1.0".
+	ConsensusClientVersion string `json:"consensus_client_version"`
 	// EventSource is the event source that provided the frame.
 	EventSource string `json:"event_source"`
+	// Network is the name of the network the frame is from.
+	Network string `json:"network"`
+	// NodeContext is the state of the node when the frame was fetched, if known.
+	NodeContext *NodeContext `json:"node_context,omitempty"`
 }
 
 func (f *FrameMetadata) Validate() error {
@@ -57,6 +63,12 @@ type Frame struct {
 	Data *v1.ForkChoice `json:"data"`
 	// Metadata is the metadata of the frame.
 	Metadata FrameMetadata `json:"metadata"`
+	// ExtraData is the verbatim top level extra_data of the fork choice dump,
+	// if the node provided any. Per node extra data is kept on Data.
+	ExtraData json.RawMessage `json:"extra_data,omitempty"`
+	// RawPayload is the verbatim response the frame was parsed from, if the
+	// source keeps it. It's stored separately from the frame.
+	RawPayload []byte `json:"-"`
 }
 
 func (f *Frame) Validate() error {
@@ -71,6 +83,24 @@ func (f *Frame) Validate() error {
 	return nil
 }
 
+func (f *Frame) AsJSON() ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func (f *Frame) FromJSON(data []byte) error {
+	var returnFile Frame
+
+	if err := json.Unmarshal(data, &returnFile); err != nil {
+		return err
+	}
+
+	f.Data = returnFile.Data
+	f.Metadata = returnFile.Metadata
+	f.ExtraData = returnFile.ExtraData
+
+	return nil
+}
+
 func (f *Frame) AsGzipJSON() ([]byte, error) {
 	// Convert to JSON
 	asJSON, err := json.Marshal(f)
@@ -118,6 +148,7 @@ func (f *Frame) FromGzipJSON(data []byte) error {
 
 	f.Data = returnFile.Data
 	f.Metadata = returnFile.Metadata
+	f.ExtraData = returnFile.ExtraData
 
 	return nil
 }
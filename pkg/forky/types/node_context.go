@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// NodeContext is the state of the node that provided a frame, captured when the
+// frame was fetched. It allows telling a node that was e.g. syncing apart from
+// a node with a faulty fork choice. Fields are nil if the node didn't provide them.
+type NodeContext struct {
+	// Version is the full version string reported by the node.
+	Version string `json:"version,omitempty"`
+	// PeerCount is the number of connected peers.
+	PeerCount *int `json:"peer_count,omitempty"`
+	// IsSyncing is true if the node was syncing.
+	IsSyncing *bool `json:"is_syncing,omitempty"`
+	// IsOptimistic is true if the node was optimistically synced.
+	IsOptimistic *bool `json:"is_optimistic,omitempty"`
+	// SyncDistance is how many slots the node was behind its head slot.
+	SyncDistance *phase0.Slot `json:"sync_distance,omitempty"`
+	// HeadSlot is the slot of the node's head block.
+	HeadSlot *phase0.Slot `json:"head_slot,omitempty"`
+	// HeadRoot is the root of the node's head block.
+	HeadRoot *phase0.Root `json:"head_root,omitempty"`
+	// FinalizedCheckpoint is the finalized checkpoint of the node's head state.
+	FinalizedCheckpoint *phase0.Checkpoint `json:"finalized_checkpoint,omitempty"`
+}
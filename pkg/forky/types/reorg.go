@@ -0,0 +1,67 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/google/uuid"
+)
+
+// Reorg is a chain reorganization observed by a node, along with the frames
+// captured immediately before and after it.
+type Reorg struct {
+	// ID is the ID of the reorg.
+	ID string `json:"id"`
+	// Network is the Ethereum network the reorg happened on.
+	Network string `json:"network"`
+	// Node is the node that observed the reorg.
+	Node string `json:"node"`
+	// ConsensusClient is the consensus client of the node that observed the reorg.
+	ConsensusClient string `json:"consensus_client"`
+	// DetectedAt is the time the reorg was observed.
+	DetectedAt time.Time `json:"detected_at"`
+	// Slot is the slot of the reorg.
+	Slot phase0.Slot `json:"slot"`
+	// Epoch is the epoch of the reorg.
+	Epoch phase0.Epoch `json:"epoch"`
+	// Depth is the number of blocks that were reorged out.
+	Depth uint64 `json:"depth"`
+	// OldHeadBlock is the root of the head block before the reorg.
+	OldHeadBlock string `json:"old_head_block"`
+	// OldHeadState is the root of the head state before the reorg.
+	OldHeadState string `json:"old_head_state"`
+	// NewHeadBlock is the root of the head block after the reorg.
+	NewHeadBlock string `json:"new_head_block"`
+	// NewHeadState is the root of the head state after the reorg.
+	NewHeadState string `json:"new_head_state"`
+	// BeforeFrameID is the ID of the frame captured before the reorg, if any.
+	BeforeFrameID string `json:"before_frame_id"`
+	// AfterFrameID is the ID of the frame captured after the reorg, if any.
+	AfterFrameID string `json:"after_frame_id"`
+}
+
+func (r *Reorg) Validate() error {
+	if r.ID == "" {
+		return errors.New("invalid id")
+	}
+
+	if r.Node == "" {
+		return errors.New("invalid node")
+	}
+
+	if r.DetectedAt.IsZero() {
+		return errors.New("invalid detected_at")
+	}
+
+	return nil
+}
+
+var reorgNamespace = uuid.MustParse("a4d5b2e4-5f0e-4a8b-9c1d-2e7f3b6a9c80")
+
+// NewReorgID returns a deterministic ID for a reorg so that the same reorg
+// reported more than once by a node is only recorded once.
+func NewReorgID(node string, slot phase0.Slot, oldHeadBlock, newHeadBlock string) string {
+	return uuid.NewSHA1(reorgNamespace, []byte(fmt.Sprintf("%s/%d/%s/%s", node, slot, oldHeadBlock, newHeadBlock))).String()
+}
@@ -1,9 +1,21 @@
 package yaml
 
+import "gopkg.in/yaml.v2"
+
 type RawMessage struct {
 	unmarshal func(interface{}) error
 }
 
+// NewRawMessage returns a RawMessage for a YAML document. As JSON is valid YAML,
+// this can also be used for JSON documents.
+func NewRawMessage(data []byte) RawMessage {
+	return RawMessage{
+		unmarshal: func(v interface{}) error {
+			return yaml.Unmarshal(data, v)
+		},
+	}
+}
+
 func (r *RawMessage) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	r.unmarshal = unmarshal
 
@@ -11,5 +23,10 @@ func (r *RawMessage) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 func (r *RawMessage) Unmarshal(v interface{}) error {
+	// The message is empty if it was never set, e.g. the key was omitted.
+	if r.unmarshal == nil {
+		return nil
+	}
+
 	return r.unmarshal(v)
 }
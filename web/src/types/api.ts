@@ -28,10 +28,16 @@ export interface FrameFilter {
   before?: string;
   after?: string;
   slot?: number;
+  min_slot?: number;
+  max_slot?: number;
   epoch?: number;
   labels?: string[];
   consensus_client?: string;
-  event_source?: 'unknown' | 'beacon_node' | 'xatu_polling' | 'xatu_reorg_event';
+  event_source?: 'unknown' | 'beacon_node' | 'xatu_polling' | 'xatu_reorg_event' | 'file_replay';
+  network?: string;
+  consensus_client_version?: string;
+  min_consensus_client_version?: string;
+  max_consensus_client_version?: string;
 }
 
 export interface PaginationCursor {
@@ -73,6 +79,7 @@ export interface Response<T> {
 export interface Frame {
   data?: ForkChoiceData;
   metadata?: FrameMetaData;
+  extra_data?: unknown;
 }
 
 export interface FrameMetaData {
@@ -83,13 +90,33 @@ export interface FrameMetaData {
   wall_clock_epoch: number;
   labels?: string[] | null;
   consensus_client?: string | null;
+  consensus_client_version?: string | null;
   event_source?: string | null;
+  network?: string | null;
+  node_context?: NodeContext | null;
+}
+
+export interface NodeContext {
+  version?: string;
+  peer_count?: number;
+  is_syncing?: boolean;
+  is_optimistic?: boolean;
+  sync_distance?: number;
+  head_slot?: number;
+  head_root?: string;
+  finalized_checkpoint?: Checkpoint;
 }
 
 export interface EthereumSpec {
   seconds_per_slot: number;
   slots_per_epoch: number;
   genesis_time: string;
+  forks?: ScheduledFork[];
+}
+
+export interface ScheduledFork {
+  name: string;
+  epoch: number;
 }
 
 export interface PaginationResponse {
@@ -104,13 +131,24 @@ export interface V1MetadataListResponse {
 export interface V1GetEthereumSpecResponse {
   network_name: string;
   spec?: EthereumSpec;
+  active_fork?: string;
+  slot?: number;
+  min_slot?: number;
+  max_slot?: number;
 }
 
 export interface V1GetEthereumNowResponse {
+  network_name?: string;
   slot?: number;
+  min_slot?: number;
+  max_slot?: number;
   epoch?: number;
 }
 
+export interface V1ListEthereumNetworksResponse {
+  networks: string[];
+}
+
 export interface V1MetadataListNodesResponse {
   nodes?: string[];
   pagination: PaginationResponse;
@@ -134,3 +172,130 @@ export interface V1MetadataListLabelsResponse {
 export interface V1GetFrameResponse {
   frame?: Frame;
 }
+
+export type FrameAnalysisIssueType =
+  | 'head_mismatch'
+  | 'justified_root_missing'
+  | 'finalized_root_missing'
+  | 'duplicate_block_root'
+  | 'weight_below_children'
+  | 'child_not_after_parent';
+
+export interface FrameAnalysisIssue {
+  type: FrameAnalysisIssueType;
+  message: string;
+  block_root?: string;
+}
+
+export interface FrameAnalysis {
+  frame_id: string;
+  slots_per_epoch: number;
+  computed_head?: {
+    block_root: string;
+    slot: number;
+    weight: number;
+  };
+  reported_head?: string;
+  reported_head_source?: 'snapshot' | 'node_context';
+  head_matches?: boolean;
+  issues: FrameAnalysisIssue[];
+  warnings: FrameAnalysisIssue[];
+}
+
+export interface V1GetFrameAnalysisResponse {
+  analysis?: FrameAnalysis;
+}
+
+export interface V1GetChainReportRequest {
+  filter?: FrameFilter;
+  from_slot: number;
+  to_slot: number;
+}
+
+export interface CanonicalBlock {
+  slot: number;
+  block_root: string;
+  nodes: string[];
+}
+
+export interface OrphanedBlock {
+  slot: number;
+  block_root: string;
+  parent_root: string;
+  seen_by: string[];
+  max_weight: number;
+}
+
+export interface ChainReport {
+  from_slot: number;
+  to_slot: number;
+  frames: string[];
+  canonical: CanonicalBlock[];
+  missed_slots: number[];
+  orphaned: OrphanedBlock[];
+}
+
+export interface V1GetChainReportResponse {
+  report?: ChainReport;
+}
+
+export interface V1GetBlockWeightsRequest {
+  filter: FrameFilter;
+}
+
+export interface BlockWeightPoint {
+  frame_id: string;
+  node: string;
+  fetched_at: string;
+  wall_clock_slot: number;
+  weight: number;
+  parent_weight: number;
+  share?: number;
+  siblings: number;
+}
+
+export interface BlockWeightSeries {
+  block_root: string;
+  slot?: number;
+  min_slot?: number;
+  max_slot?: number;
+  parent_root?: string;
+  points: BlockWeightPoint[];
+  skipped_frames: number;
+  truncated: boolean;
+}
+
+export interface V1GetBlockWeightsResponse {
+  series?: BlockWeightSeries;
+}
+
+export type FinalityCheckpoint = 'justified' | 'finalized';
+
+export interface NodeFinality {
+  network: string;
+  node: string;
+  frame_id: string;
+  observed_at: string;
+  wall_clock_epoch: number;
+  justified_epoch: number;
+  finalized_epoch: number;
+  justification_lag: number;
+  finality_lag: number;
+  justification_lagging: boolean;
+  finality_lagging: boolean;
+}
+
+export interface FinalityLagEvent {
+  network: string;
+  node: string;
+  checkpoint: FinalityCheckpoint;
+  node_epoch: number;
+  majority_epoch: number;
+  detected_at: string;
+  recovered: boolean;
+}
+
+export interface V1GetFinalityResponse {
+  nodes: NodeFinality[];
+  events: FinalityLagEvent[];
+}